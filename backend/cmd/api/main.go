@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"strconv"
@@ -9,7 +10,10 @@ import (
 	"github.com/nouvadev/veridian/backend/internal/app"
 	"github.com/nouvadev/veridian/backend/internal/auth"
 	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/mailer"
 	"github.com/nouvadev/veridian/backend/internal/router"
+	"github.com/nouvadev/veridian/backend/internal/webhook"
 )
 
 func main() {
@@ -47,8 +51,50 @@ func main() {
 		7*24*time.Hour,    // refresh token TTL (7 days)
 	)
 
+	// Log store, from which execution logs are served
+	logs, err := loadLogStore()
+	if err != nil {
+		log.Fatal("Failed to configure log store:", err)
+	}
+
+	// Mailer for account emails such as address verification
+	mail, err := loadMailer()
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
+
+	accounts := app.AccountConfig{
+		VerifyEmailURL:       getEnv("VERIFY_EMAIL_URL", "http://localhost:5173/verify-email"),
+		VerificationTTL:      getEnvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		ResetPasswordURL:     getEnv("RESET_PASSWORD_URL", "http://localhost:5173/reset-password"),
+		PasswordResetTTL:     getEnvDuration("PASSWORD_RESET_TTL", 30*time.Minute),
+		RequireVerifiedEmail: getEnv("REQUIRE_VERIFIED_EMAIL", "false") == "true",
+	}
+
 	// Create application with dependencies
-	app := app.NewApp(db, jwtManager)
+	app := app.NewApp(db, jwtManager, logs, mail, accounts)
+
+	// Relay execution notifications to event stream clients
+	go func() {
+		if err := app.Events.Run(context.Background()); err != nil {
+			log.Println("Event broker stopped with error:", err)
+		}
+	}()
+
+	// Deliver execution lifecycle events to webhook subscriptions
+	dispatcher := webhook.NewDispatcher(app.Queries, webhook.Config{
+		PollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		BatchSize:    int32(getEnvInt("WEBHOOK_BATCH_SIZE", 50)),
+		Timeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts:  int32(getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8)),
+		BaseBackoff:  getEnvDuration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+		MaxBackoff:   getEnvDuration("WEBHOOK_MAX_BACKOFF", 6*time.Hour),
+	})
+	go func() {
+		if err := dispatcher.Run(context.Background()); err != nil {
+			log.Println("Webhook dispatcher stopped with error:", err)
+		}
+	}()
 
 	// Setup router with app dependencies
 	r := router.SetupRouter(app)
@@ -62,6 +108,55 @@ func main() {
 	}
 }
 
+// loadLogStore configures where execution logs are stored from LOG_STORE (local
+// or s3) and the matching LOG_DIR or S3_* settings
+func loadLogStore() (logstore.Store, error) {
+	backend := getEnv("LOG_STORE", "local")
+	store, err := logstore.New(logstore.Config{
+		Backend: backend,
+		Dir:     getEnv("LOG_DIR", "./logs"),
+		S3: logstore.S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", ""),
+			Region:          getEnv("S3_REGION", ""),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			Prefix:          getEnv("S3_PREFIX", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       getEnv("S3_PATH_STYLE", "false") == "true",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Using %s log store", backend)
+	return store, nil
+}
+
+// loadMailer configures how account emails are sent from MAIL_BACKEND (smtp, file
+// or memory) and the matching MAIL_DIR or SMTP_* settings
+func loadMailer() (mailer.Mailer, error) {
+	backend := getEnv("MAIL_BACKEND", "file")
+	mail, err := mailer.New(mailer.Config{
+		Backend: backend,
+		From:    getEnv("MAIL_FROM", "Veridian <noreply@localhost>"),
+		Dir:     getEnv("MAIL_DIR", "./mail"),
+		SMTP: mailer.SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnvInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			Timeout:  getEnvDuration("SMTP_TIMEOUT", 30*time.Second),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Using %s mailer", backend)
+	return mail, nil
+}
+
 // Helper functions for environment variables
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -78,3 +173,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
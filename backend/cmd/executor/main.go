@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/nouvadev/veridian/backend/internal/accounting"
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/executor"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+func main() {
+	// Database configuration from environment variables
+	config := database.DatabaseConfig{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnvInt("DB_PORT", 5432),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", ""),
+		DBName:   getEnv("DB_NAME", "veridian"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	}
+
+	// Create database connection
+	db, err := database.NewConnection(config)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	log.Println("Successfully connected to database")
+
+	// Executor configuration from environment variables
+	runnerConfig := executor.Config{
+		WorkerID:      getEnv("EXECUTOR_WORKER_ID", defaultWorkerID()),
+		PollInterval:  getEnvDuration("EXECUTOR_POLL_INTERVAL", 15*time.Second),
+		BatchSize:     int32(getEnvInt("EXECUTOR_BATCH_SIZE", 10)),
+		LeaseDuration: getEnvDuration("EXECUTOR_LEASE_DURATION", 10*time.Minute),
+		MaxConcurrent: getEnvInt("EXECUTOR_MAX_CONCURRENT", 4),
+	}
+
+	// Compute backend
+	runtime := getEnv("EXECUTOR_LOCAL_RUNTIME", executor.RuntimeDocker)
+	backend, err := executor.NewLocalBackend(runtime)
+	if err != nil {
+		log.Fatal("Failed to configure executor backend:", err)
+	}
+	if runtime == executor.RuntimeProcess {
+		log.Println("WARNING: Process runtime runs image URIs as shell commands! Use for local development only.")
+	}
+
+	// Log store, which receives the output of each execution
+	logs, err := loadLogStore()
+	if err != nil {
+		log.Fatal("Failed to configure log store:", err)
+	}
+
+	// VM prices, used to record the actual cost of each execution
+	prices, err := loadEstimator()
+	if err != nil {
+		log.Fatal("Failed to load pricing catalogue:", err)
+	}
+
+	// Carbon intensity source (optional), used to record the emissions of each execution
+	var provider carbon.Provider
+	if source := getEnv("CARBON_PROVIDER", ""); source != "" {
+		provider, err = carbon.NewProvider(carbon.Config{
+			Source:   source,
+			FilePath: getEnv("CARBON_DATA_FILE", ""),
+			BaseURL:  getEnv("CARBON_API_URL", ""),
+			APIKey:   getEnv("CARBON_API_KEY", ""),
+			Timeout:  getEnvDuration("CARBON_API_TIMEOUT", 10*time.Second),
+		})
+		if err != nil {
+			log.Fatal("Failed to configure carbon provider:", err)
+		}
+		log.Printf("Using %s carbon intensity provider", source)
+	} else {
+		log.Println("WARNING: No carbon provider configured! Set CARBON_PROVIDER to record execution emissions.")
+	}
+
+	// Energy model, used to record the energy use of each execution
+	model, err := loadEnergyModel()
+	if err != nil {
+		log.Fatal("Failed to load energy model:", err)
+	}
+	accountant := accounting.NewAccountant(model, prices.Catalogue(), provider)
+
+	reconcilerConfig := executor.ReconcilerConfig{
+		Interval:    getEnvDuration("EXECUTOR_RECONCILE_INTERVAL", 5*time.Minute),
+		BatchSize:   int32(getEnvInt("EXECUTOR_RECONCILE_BATCH_SIZE", 50)),
+		GracePeriod: getEnvDuration("EXECUTOR_ORPHAN_GRACE_PERIOD", 10*time.Minute),
+		BaseBackoff: getEnvDuration("EXECUTOR_TEARDOWN_BACKOFF", time.Minute),
+		MaxBackoff:  getEnvDuration("EXECUTOR_TEARDOWN_MAX_BACKOFF", time.Hour),
+	}
+
+	queries := database.New(db)
+	r := executor.NewRunner(queries, backend, logs, prices, accountant, runnerConfig)
+	reconciler := executor.NewReconciler(queries, backend, reconcilerConfig)
+
+	// Stop gracefully on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Executor %s starting (%s runtime, poll interval %s, max concurrent %d)", runnerConfig.WorkerID, runtime, runnerConfig.PollInterval, runnerConfig.MaxConcurrent)
+
+	// The reconciler cleans up instances that outlived their execution
+	go func() {
+		if err := reconciler.Run(ctx); err != nil {
+			log.Println("Reconciler stopped with error:", err)
+		}
+	}()
+
+	if err := r.Run(ctx); err != nil {
+		log.Fatal("Executor stopped with error:", err)
+	}
+
+	log.Println("Executor stopped")
+}
+
+// defaultWorkerID identifies this replica by hostname and process ID
+func defaultWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "executor"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// loadEstimator builds the price estimator from PRICING_CATALOGUE_FILE, falling
+// back to the catalogue built into the binary
+func loadEstimator() (*pricing.Estimator, error) {
+	catalogue := pricing.Default()
+	if path := getEnv("PRICING_CATALOGUE_FILE", ""); path != "" {
+		var err error
+		if catalogue, err = pricing.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	option := pricing.PurchaseOption(getEnv("PRICING_PURCHASE_OPTION", string(pricing.OnDemand)))
+	estimator, err := pricing.NewEstimator(catalogue, option)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Using pricing catalogue %s (%s prices)", catalogue.Version(), option)
+	return estimator, nil
+}
+
+// loadEnergyModel reads the energy model from ACCOUNTING_MODEL_FILE, falling back to
+// the model built into the binary
+func loadEnergyModel() (*accounting.Model, error) {
+	model := accounting.Default()
+	if path := getEnv("ACCOUNTING_MODEL_FILE", ""); path != "" {
+		var err error
+		if model, err = accounting.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Using energy model %s", model.Version())
+	return model, nil
+}
+
+// loadLogStore configures where execution logs are stored from LOG_STORE (local
+// or s3) and the matching LOG_DIR or S3_* settings
+func loadLogStore() (logstore.Store, error) {
+	backend := getEnv("LOG_STORE", "local")
+	store, err := logstore.New(logstore.Config{
+		Backend: backend,
+		Dir:     getEnv("LOG_DIR", "./logs"),
+		S3: logstore.S3Config{
+			Endpoint:        getEnv("S3_ENDPOINT", ""),
+			Region:          getEnv("S3_REGION", ""),
+			Bucket:          getEnv("S3_BUCKET", ""),
+			Prefix:          getEnv("S3_PREFIX", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       getEnv("S3_PATH_STYLE", "false") == "true",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Using %s log store", backend)
+	return store, nil
+}
+
+// Helper functions for environment variables
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+	"github.com/nouvadev/veridian/backend/internal/scheduler"
+)
+
+func main() {
+	// Database configuration from environment variables
+	config := database.DatabaseConfig{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnvInt("DB_PORT", 5432),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: getEnv("DB_PASSWORD", ""),
+		DBName:   getEnv("DB_NAME", "veridian"),
+		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+	}
+
+	// Create database connection
+	db, err := database.NewConnection(config)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.Close()
+
+	log.Println("Successfully connected to database")
+
+	// Scheduler configuration from environment variables
+	schedulerConfig := scheduler.Config{
+		WorkerID:       getEnv("SCHEDULER_WORKER_ID", defaultWorkerID()),
+		PollInterval:   getEnvDuration("SCHEDULER_POLL_INTERVAL", 30*time.Second),
+		BatchSize:      int32(getEnvInt("SCHEDULER_BATCH_SIZE", 50)),
+		LeaseDuration:  getEnvDuration("SCHEDULER_LEASE_DURATION", 5*time.Minute),
+		Regions:        getEnvList("SCHEDULER_REGIONS", []string{"westeurope", "northeurope", "swedencentral"}),
+		DefaultVMType:  getEnv("SCHEDULER_DEFAULT_VM_TYPE", "Standard_D2s_v5"),
+		DefaultRuntime: getEnvDuration("SCHEDULER_DEFAULT_RUNTIME", time.Hour),
+	}
+
+	schedulerConfig.VMSelection, err = pricing.ParseStrategy(getEnv("SCHEDULER_VM_SELECTION", string(pricing.Cheapest)))
+	if err != nil {
+		log.Fatal("Invalid SCHEDULER_VM_SELECTION:", err)
+	}
+
+	// VM prices, used to weigh cost against carbon and to estimate execution cost
+	prices, err := loadEstimator()
+	if err != nil {
+		log.Fatal("Failed to load pricing catalogue:", err)
+	}
+
+	// Carbon intensity source (optional)
+	var provider carbon.Provider
+	if source := getEnv("CARBON_PROVIDER", ""); source != "" {
+		provider, err = carbon.NewProvider(carbon.Config{
+			Source:   source,
+			FilePath: getEnv("CARBON_DATA_FILE", ""),
+			BaseURL:  getEnv("CARBON_API_URL", ""),
+			APIKey:   getEnv("CARBON_API_KEY", ""),
+			Timeout:  getEnvDuration("CARBON_API_TIMEOUT", 10*time.Second),
+		})
+		if err != nil {
+			log.Fatal("Failed to configure carbon provider:", err)
+		}
+		log.Printf("Using %s carbon intensity provider", source)
+	} else {
+		log.Println("WARNING: No carbon provider configured! Set CARBON_PROVIDER to record intensity estimates.")
+	}
+
+	s := scheduler.NewScheduler(database.New(db), provider, prices, schedulerConfig)
+
+	// Stop gracefully on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Scheduler %s starting (poll interval %s, regions %v)", schedulerConfig.WorkerID, schedulerConfig.PollInterval, schedulerConfig.Regions)
+
+	if err := s.Run(ctx); err != nil {
+		log.Fatal("Scheduler stopped with error:", err)
+	}
+
+	log.Println("Scheduler stopped")
+}
+
+// defaultWorkerID identifies this replica by hostname and process ID
+func defaultWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// Helper functions for environment variables
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return defaultValue
+	}
+	return items
+}
+
+// loadEstimator builds the price estimator from PRICING_CATALOGUE_FILE, falling
+// back to the catalogue built into the binary
+func loadEstimator() (*pricing.Estimator, error) {
+	catalogue := pricing.Default()
+	if path := getEnv("PRICING_CATALOGUE_FILE", ""); path != "" {
+		var err error
+		if catalogue, err = pricing.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	option := pricing.PurchaseOption(getEnv("PRICING_PURCHASE_OPTION", string(pricing.OnDemand)))
+	estimator, err := pricing.NewEstimator(catalogue, option)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Using pricing catalogue %s (%s prices)", catalogue.Version(), option)
+	return estimator, nil
+}
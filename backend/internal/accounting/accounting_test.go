@@ -0,0 +1,191 @@
+package accounting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testModel = `{
+  "version": "2025-01-01",
+  "utilisation": 0.5,
+  "default_pue": 1.0,
+  "default_profile": {"idle_watts_per_vcpu": 1, "max_watts_per_vcpu": 3, "memory_watts_per_gb": 0.5},
+  "profiles": [
+    {"vm_type": "Hot", "idle_watts_per_vcpu": 2, "max_watts_per_vcpu": 6, "memory_watts_per_gb": 0.5}
+  ],
+  "pue": [{"region": "westeurope", "pue": 1.5}]
+}`
+
+const testCatalogue = `{
+  "version": "test",
+  "vm_types": [
+    {"name": "Small", "vcpus": 2, "memory_gb": 4},
+    {"name": "Hot", "vcpus": 2, "memory_gb": 4}
+  ],
+  "prices": [
+    {"region": "westeurope", "vm_type": "Small", "on_demand": 0.1},
+    {"region": "westeurope", "vm_type": "Hot", "on_demand": 0.1}
+  ]
+}`
+
+// seriesProvider serves a fixed intensity series for every region
+type seriesProvider []carbon.Intensity
+
+func (p seriesProvider) Current(ctx context.Context, region string) (carbon.Intensity, error) {
+	return p[len(p)-1], nil
+}
+
+func (p seriesProvider) Forecast(ctx context.Context, region string, from, to time.Time) ([]carbon.Intensity, error) {
+	if len(p) == 0 {
+		return nil, carbon.ErrNoData
+	}
+	return p, nil
+}
+
+func at(hour, minute int) time.Time {
+	return time.Date(2025, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestModel_Energy(t *testing.T) {
+	model, err := LoadJSON(strings.NewReader(testModel))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-01", model.Version())
+
+	small := pricing.VMType{Name: "Small", VCPUs: 2, MemoryGB: 4}
+	hot := pricing.VMType{Name: "Hot", VCPUs: 2, MemoryGB: 4}
+
+	// 2 vCPU at 2 W plus 4 GB at 0.5 W is 6 W, times a PUE of 1.5
+	assert.InDelta(t, 0.009, model.PowerKW("westeurope", small), 1e-9)
+	assert.Equal(t, 0.018, model.Energy("westeurope", small, 2*time.Hour))
+
+	// Regions without a PUE entry use the default
+	assert.Equal(t, 0.012, model.Energy("northeurope", small, 2*time.Hour))
+
+	// VM types with their own profile: 2 vCPU at 4 W plus 2 W of memory
+	assert.Equal(t, 0.015, model.Energy("westeurope", hot, time.Hour))
+
+	assert.Zero(t, model.Energy("westeurope", small, -time.Hour))
+}
+
+func TestDefault_CoversCatalogueVMTypes(t *testing.T) {
+	model := Default()
+	catalogue := pricing.Default()
+
+	for _, region := range []string{"westeurope", "swedencentral"} {
+		for _, name := range catalogue.VMTypes(region) {
+			spec, ok := catalogue.VMType(name)
+			require.True(t, ok, name)
+			assert.Positive(t, model.Energy(region, spec, time.Hour), name)
+		}
+	}
+}
+
+func TestLoad_RejectsInvalidModels(t *testing.T) {
+	tests := []struct {
+		name  string
+		model string
+	}{
+		{"missing version", `{"utilisation": 0.5, "default_pue": 1.1, "default_profile": {}}`},
+		{"utilisation above one", `{"version": "1", "utilisation": 1.5, "default_pue": 1.1, "default_profile": {}}`},
+		{"pue below one", `{"version": "1", "utilisation": 0.5, "default_pue": 0.9, "default_profile": {}}`},
+		{"max below idle", `{"version": "1", "utilisation": 0.5, "default_pue": 1.1, "default_profile": {"idle_watts_per_vcpu": 3, "max_watts_per_vcpu": 1}}`},
+		{"duplicate profile", `{"version": "1", "utilisation": 0.5, "default_pue": 1.1, "default_profile": {}, "profiles": [{"vm_type": "A"}, {"vm_type": "A"}]}`},
+		{"region pue below one", `{"version": "1", "utilisation": 0.5, "default_pue": 1.1, "default_profile": {}, "pue": [{"region": "r", "pue": 0.5}]}`},
+		{"unknown field", `{"version": "1", "utilisation": 0.5, "default_pue": 1.1, "default_profile": {}, "tdp": 100}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadJSON(strings.NewReader(tt.model))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	model, err := LoadYAML(strings.NewReader(`
+version: "2025-01-01"
+utilisation: 0.5
+default_pue: 1.2
+default_profile:
+  idle_watts_per_vcpu: 1
+  max_watts_per_vcpu: 3
+  memory_watts_per_gb: 0.5
+profiles:
+  - vm_type: Hot
+    idle_watts_per_vcpu: 2
+    max_watts_per_vcpu: 6
+    memory_watts_per_gb: 0.5
+`))
+	require.NoError(t, err)
+	assert.Equal(t, 1.2, model.PUE("westeurope"))
+	assert.Equal(t, 6.0, model.Profile("Hot").MaxWattsPerVCPU)
+}
+
+func TestMeanIntensity(t *testing.T) {
+	series := []carbon.Intensity{
+		{Timestamp: at(12, 0), GramsPerKWh: 100},
+		{Timestamp: at(13, 30), GramsPerKWh: 300},
+	}
+
+	tests := []struct {
+		name     string
+		readings []carbon.Intensity
+		from, to time.Time
+		want     float64
+	}{
+		{"weighted by time in effect", series, at(12, 0), at(14, 0), 150},
+		{"within one reading", series, at(12, 15), at(13, 15), 100},
+		{"first reading covers the start", series[1:], at(13, 0), at(14, 0), 300},
+		{"empty interval uses reading in effect", series, at(13, 45), at(13, 45), 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean, err := MeanIntensity(tt.readings, tt.from, tt.to)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, mean, 1e-9)
+		})
+	}
+
+	_, err := MeanIntensity(nil, at(12, 0), at(13, 0))
+	assert.ErrorIs(t, err, carbon.ErrNoData)
+}
+
+func TestAccountant(t *testing.T) {
+	model, err := LoadJSON(strings.NewReader(testModel))
+	require.NoError(t, err)
+	catalogue, err := pricing.LoadJSON(strings.NewReader(testCatalogue))
+	require.NoError(t, err)
+
+	provider := seriesProvider{
+		{Timestamp: at(12, 0), GramsPerKWh: 100},
+		{Timestamp: at(13, 30), GramsPerKWh: 300},
+	}
+	accountant := NewAccountant(model, catalogue, provider)
+
+	energy, err := accountant.Energy("westeurope", "Small", 2*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 0.018, energy)
+
+	// 0.018 kWh at a mean of 150 g/kWh over the run
+	emitted, err := accountant.Emissions(context.Background(), "westeurope", energy, at(12, 0), at(14, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0027, emitted)
+
+	_, err = accountant.Energy("westeurope", "Unknown", time.Hour)
+	assert.ErrorIs(t, err, ErrUnknownVMType)
+
+	_, err = NewAccountant(model, catalogue, nil).Emissions(context.Background(), "westeurope", energy, at(12, 0), at(14, 0))
+	assert.ErrorIs(t, err, ErrNoProvider)
+
+	_, err = NewAccountant(model, catalogue, seriesProvider{}).Emissions(context.Background(), "westeurope", energy, at(12, 0), at(14, 0))
+	assert.ErrorIs(t, err, carbon.ErrNoData)
+}
@@ -0,0 +1,104 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+var (
+	// ErrUnknownVMType is returned when the pricing catalogue does not describe a VM type
+	ErrUnknownVMType = errors.New("accounting: unknown VM type")
+	// ErrNoProvider is returned for emissions when no carbon intensity source is configured
+	ErrNoProvider = errors.New("accounting: no carbon intensity provider")
+)
+
+// MeanIntensity returns the time-weighted mean grid intensity over [from, to] in
+// gCO2/kWh. Readings are ordered by timestamp and each applies until the next one;
+// the first also covers any part of the interval before it. For an empty interval
+// the reading in effect at from is returned.
+func MeanIntensity(readings []carbon.Intensity, from, to time.Time) (float64, error) {
+	if len(readings) == 0 {
+		return 0, carbon.ErrNoData
+	}
+
+	if !to.After(from) {
+		current := readings[0]
+		for _, reading := range readings[1:] {
+			if reading.Timestamp.After(from) {
+				break
+			}
+			current = reading
+		}
+		return current.GramsPerKWh, nil
+	}
+
+	var weighted float64
+	for i, reading := range readings {
+		start := from
+		if i > 0 && reading.Timestamp.After(from) {
+			start = reading.Timestamp
+		}
+		end := to
+		if i+1 < len(readings) && readings[i+1].Timestamp.Before(to) {
+			end = readings[i+1].Timestamp
+		}
+
+		if end.After(start) {
+			weighted += reading.GramsPerKWh * end.Sub(start).Seconds()
+		}
+	}
+
+	return weighted / to.Sub(from).Seconds(), nil
+}
+
+// Accountant computes the energy and emissions of executions from the energy
+// model, the VM types described by the pricing catalogue and grid intensity
+type Accountant struct {
+	model     *Model
+	catalogue *pricing.Catalogue
+	carbon    carbon.Provider
+}
+
+// NewAccountant creates a new accountant. The carbon provider is optional; without
+// one, energy is still accounted but emissions are not.
+func NewAccountant(model *Model, catalogue *pricing.Catalogue, provider carbon.Provider) *Accountant {
+	return &Accountant{
+		model:     model,
+		catalogue: catalogue,
+		carbon:    provider,
+	}
+}
+
+// Energy returns the energy a VM type in a region draws over runtime, in kWh
+func (a *Accountant) Energy(region, vmType string, runtime time.Duration) (float64, error) {
+	spec, ok := a.catalogue.VMType(vmType)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownVMType, vmType)
+	}
+	return a.model.Energy(region, spec, runtime), nil
+}
+
+// Emissions returns the kg CO2 emitted producing energyKWh drawn evenly over
+// [from, to] in a region, using the intensity series over that interval
+func (a *Accountant) Emissions(ctx context.Context, region string, energyKWh float64, from, to time.Time) (float64, error) {
+	if a.carbon == nil {
+		return 0, ErrNoProvider
+	}
+
+	readings, err := a.carbon.Forecast(ctx, region, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load intensity for %s: %w", region, err)
+	}
+
+	intensity, err := MeanIntensity(readings, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load intensity for %s: %w", region, err)
+	}
+
+	return round6(energyKWh * intensity / 1000), nil
+}
@@ -0,0 +1,223 @@
+// Package accounting estimates the energy drawn by executions and the carbon
+// emitted producing it.
+package accounting
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+//go:embed default_model.json
+var defaultModel []byte
+
+// Profile is the power draw of a VM type
+type Profile struct {
+	IdleWattsPerVCPU float64 `json:"idle_watts_per_vcpu" yaml:"idle_watts_per_vcpu"`
+	MaxWattsPerVCPU  float64 `json:"max_watts_per_vcpu" yaml:"max_watts_per_vcpu"`
+	MemoryWattsPerGB float64 `json:"memory_watts_per_gb" yaml:"memory_watts_per_gb"`
+}
+
+// vmProfile is a profile entry in the model file
+type vmProfile struct {
+	VMType  string `json:"vm_type" yaml:"vm_type"`
+	Profile `yaml:",inline"`
+}
+
+// regionPUE is a power usage effectiveness entry in the model file
+type regionPUE struct {
+	Region string  `json:"region" yaml:"region"`
+	PUE    float64 `json:"pue" yaml:"pue"`
+}
+
+// modelFile is the on-disk model format. The version identifies the coefficients
+// so recorded energy can be traced back to the model that produced it.
+type modelFile struct {
+	Version        string      `json:"version" yaml:"version"`
+	Utilisation    float64     `json:"utilisation" yaml:"utilisation"` // Average CPU utilisation assumed between idle and max draw
+	DefaultPUE     float64     `json:"default_pue" yaml:"default_pue"`
+	DefaultProfile Profile     `json:"default_profile" yaml:"default_profile"`
+	Profiles       []vmProfile `json:"profiles" yaml:"profiles"`
+	PUE            []regionPUE `json:"pue" yaml:"pue"`
+}
+
+// Model holds power profiles per VM type and PUE per region. VM types and regions
+// the model does not list use its defaults. It is immutable once loaded and safe
+// for concurrent use.
+type Model struct {
+	version        string
+	utilisation    float64
+	defaultPUE     float64
+	defaultProfile Profile
+	profiles       map[string]Profile
+	pue            map[string]float64
+}
+
+// Default returns the model built into the binary
+func Default() *Model {
+	model, err := LoadJSON(bytes.NewReader(defaultModel))
+	if err != nil {
+		panic(fmt.Sprintf("accounting: invalid built-in model: %v", err))
+	}
+	return model
+}
+
+// LoadFile reads a model from a .json, .yaml or .yml file
+func LoadFile(path string) (*Model, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open energy model: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return LoadJSON(f)
+	case ".yaml", ".yml":
+		return LoadYAML(f)
+	default:
+		return nil, fmt.Errorf("energy model must be a .json, .yaml or .yml file, got %q", path)
+	}
+}
+
+// LoadJSON parses a model from a JSON stream
+func LoadJSON(r io.Reader) (*Model, error) {
+	var file modelFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse energy model: %w", err)
+	}
+	return newModel(file)
+}
+
+// LoadYAML parses a model from a YAML stream
+func LoadYAML(r io.Reader) (*Model, error) {
+	var file modelFile
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse energy model: %w", err)
+	}
+	return newModel(file)
+}
+
+// newModel validates a parsed model file and indexes its entries
+func newModel(file modelFile) (*Model, error) {
+	if file.Version == "" {
+		return nil, errors.New("energy model is missing a version")
+	}
+	if file.Utilisation < 0 || file.Utilisation > 1 {
+		return nil, errors.New("utilisation must be between 0 and 1")
+	}
+	if file.DefaultPUE < 1 {
+		return nil, errors.New("default_pue must be at least 1")
+	}
+	if err := file.DefaultProfile.validate(); err != nil {
+		return nil, fmt.Errorf("default_profile: %w", err)
+	}
+
+	profiles := make(map[string]Profile, len(file.Profiles))
+	for i, entry := range file.Profiles {
+		if entry.VMType == "" {
+			return nil, fmt.Errorf("profile %d: vm_type is required", i)
+		}
+		if err := entry.Profile.validate(); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", entry.VMType, err)
+		}
+		if _, exists := profiles[entry.VMType]; exists {
+			return nil, fmt.Errorf("profile %s: duplicate entry", entry.VMType)
+		}
+		profiles[entry.VMType] = entry.Profile
+	}
+
+	pue := make(map[string]float64, len(file.PUE))
+	for i, entry := range file.PUE {
+		if entry.Region == "" {
+			return nil, fmt.Errorf("pue %d: region is required", i)
+		}
+		if entry.PUE < 1 {
+			return nil, fmt.Errorf("pue %s: must be at least 1", entry.Region)
+		}
+		if _, exists := pue[entry.Region]; exists {
+			return nil, fmt.Errorf("pue %s: duplicate entry", entry.Region)
+		}
+		pue[entry.Region] = entry.PUE
+	}
+
+	return &Model{
+		version:        file.Version,
+		utilisation:    file.Utilisation,
+		defaultPUE:     file.DefaultPUE,
+		defaultProfile: file.DefaultProfile,
+		profiles:       profiles,
+		pue:            pue,
+	}, nil
+}
+
+// validate checks that a profile's draws are non-negative and max is not below idle
+func (p Profile) validate() error {
+	if p.IdleWattsPerVCPU < 0 || p.MemoryWattsPerGB < 0 {
+		return errors.New("watts must not be negative")
+	}
+	if p.MaxWattsPerVCPU < p.IdleWattsPerVCPU {
+		return errors.New("max_watts_per_vcpu must not be below idle_watts_per_vcpu")
+	}
+	return nil
+}
+
+// Version identifies the loaded coefficients
+func (m *Model) Version() string {
+	return m.version
+}
+
+// PUE returns the power usage effectiveness of a region's data centres
+func (m *Model) PUE(region string) float64 {
+	if pue, ok := m.pue[region]; ok {
+		return pue
+	}
+	return m.defaultPUE
+}
+
+// Profile returns the power profile of a VM type
+func (m *Model) Profile(vmType string) Profile {
+	if profile, ok := m.profiles[vmType]; ok {
+		return profile
+	}
+	return m.defaultProfile
+}
+
+// PowerKW returns the average power a VM type draws in a region, in kW. CPU draw is
+// interpolated between idle and max at the model's utilisation; data centre
+// overhead is included through the region's PUE.
+func (m *Model) PowerKW(region string, vmType pricing.VMType) float64 {
+	profile := m.Profile(vmType.Name)
+	cpuWatts := profile.IdleWattsPerVCPU + m.utilisation*(profile.MaxWattsPerVCPU-profile.IdleWattsPerVCPU)
+	watts := float64(vmType.VCPUs)*cpuWatts + vmType.MemoryGB*profile.MemoryWattsPerGB
+	return watts / 1000 * m.PUE(region)
+}
+
+// Energy returns the energy a VM type draws in a region over runtime, in kWh
+func (m *Model) Energy(region string, vmType pricing.VMType, runtime time.Duration) float64 {
+	if runtime < 0 {
+		runtime = 0
+	}
+	return round6(m.PowerKW(region, vmType) * runtime.Hours())
+}
+
+// round6 rounds to the six decimal places stored in the energy and emissions columns
+func round6(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
@@ -1,23 +1,45 @@
 package app
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nouvadev/veridian/backend/internal/auth"
 	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/events"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/mailer"
 )
 
+// AccountConfig controls the account emails sent to users
+type AccountConfig struct {
+	VerifyEmailURL       string        // Frontend page that submits ?token= to POST /auth/verify-email
+	VerificationTTL      time.Duration // How long verification links stay valid
+	ResetPasswordURL     string        // Frontend page that submits ?token= to POST /auth/reset-password
+	PasswordResetTTL     time.Duration // How long password reset links stay valid
+	RequireVerifiedEmail bool          // Reject job submission until the email is verified
+}
+
 // App holds application dependencies
 type App struct {
 	DB         *pgxpool.Pool
 	Queries    *database.Queries
 	JWTManager *auth.JWTManager
+	Logs       logstore.Store
+	Events     *events.Broker
+	Mailer     mailer.Mailer
+	Accounts   AccountConfig
 }
 
 // NewApp creates a new application instance with dependencies
-func NewApp(db *pgxpool.Pool, jwtManager *auth.JWTManager) *App {
+func NewApp(db *pgxpool.Pool, jwtManager *auth.JWTManager, logs logstore.Store, mail mailer.Mailer, accounts AccountConfig) *App {
 	return &App{
 		DB:         db,
 		Queries:    database.New(db),
 		JWTManager: jwtManager,
+		Logs:       logs,
+		Events:     events.NewBroker(db),
+		Mailer:     mail,
+		Accounts:   accounts,
 	}
 }
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key, so keys are recognisable in the
+// Authorization header and in leaked-secret scans
+const APIKeyPrefix = "vrd_"
+
+// Scope is a permission granted to an API key
+type Scope string
+
+const (
+	ScopeJobsRead      Scope = "jobs:read"      // List jobs, executions, logs and statistics
+	ScopeJobsWrite     Scope = "jobs:write"     // Create, update, delete and run jobs
+	ScopeWebhooksRead  Scope = "webhooks:read"  // List webhook subscriptions and deliveries
+	ScopeWebhooksWrite Scope = "webhooks:write" // Create and delete webhook subscriptions
+	ScopeSettingsRead  Scope = "settings:read"  // Read user settings
+	ScopeSettingsWrite Scope = "settings:write" // Update user settings
+)
+
+// AllScopes lists every scope an API key can be granted
+func AllScopes() []Scope {
+	return []Scope{
+		ScopeJobsRead, ScopeJobsWrite,
+		ScopeWebhooksRead, ScopeWebhooksWrite,
+		ScopeSettingsRead, ScopeSettingsWrite,
+	}
+}
+
+// Valid reports whether s is a known scope
+func (s Scope) Valid() bool {
+	for _, known := range AllScopes() {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAPIKey returns a new API key and its displayable prefix. The key has
+// the form "vrd_<8 hex>_<token>"; the prefix is everything before the second
+// underscore and identifies the key without revealing it.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(b)
+	return prefix + "_" + token, prefix, nil
+}
+
+// IsAPIKey reports whether a bearer credential is an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
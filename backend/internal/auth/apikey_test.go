@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^vrd_[0-9a-f]{8}$`, prefix)
+	assert.True(t, strings.HasPrefix(key, prefix+"_"))
+	assert.Len(t, key, len(prefix)+1+43)
+	assert.True(t, IsAPIKey(key))
+
+	other, _, err := GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestIsAPIKey(t *testing.T) {
+	assert.True(t, IsAPIKey("vrd_0123abcd_token"))
+	assert.False(t, IsAPIKey("eyJhbGciOiJIUzI1NiJ9.payload.signature"))
+	assert.False(t, IsAPIKey(""))
+}
+
+func TestScopeValid(t *testing.T) {
+	for _, scope := range AllScopes() {
+		assert.True(t, scope.Valid(), scope)
+	}
+	assert.False(t, Scope("jobs:admin").Valid())
+	assert.False(t, Scope("").Valid())
+}
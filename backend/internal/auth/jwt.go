@@ -18,6 +18,7 @@ type JWTClaims struct {
 	Issuer    string    `json:"iss"`
 	Audience  string    `json:"aud"`
 	JTI       string    `json:"jti"` // JWT ID for revocation
+	SessionID uuid.UUID `json:"sid"` // Refresh token family the token was issued for
 	jwt.RegisteredClaims
 }
 
@@ -41,8 +42,9 @@ func NewJWTManager(secretKey, issuer, audience string, accessTTL, refreshTTL tim
 	}
 }
 
-// GenerateAccessToken generates a new access token
-func (j *JWTManager) GenerateAccessToken(userID uuid.UUID, email string) (string, time.Time, error) {
+// GenerateAccessToken generates a new access token for a session, identified by
+// its refresh token family
+func (j *JWTManager) GenerateAccessToken(userID uuid.UUID, email string, sessionID uuid.UUID) (string, time.Time, error) {
 	now := time.Now()
 	expiresAt := now.Add(j.accessTokenTTL)
 
@@ -55,6 +57,7 @@ func (j *JWTManager) GenerateAccessToken(userID uuid.UUID, email string) (string
 		Issuer:    j.issuer,
 		Audience:  j.audience,
 		JTI:       uuid.New().String(),
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   userID.String(),
 			IssuedAt:  jwt.NewNumericDate(now),
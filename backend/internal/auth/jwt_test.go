@@ -23,7 +23,7 @@ func TestJWTManager_GenerateAccessToken(t *testing.T) {
 	email := "test@example.com"
 
 	// Test
-	token, expiresAt, err := jwtManager.GenerateAccessToken(userID, email)
+	token, expiresAt, err := jwtManager.GenerateAccessToken(userID, email, uuid.New())
 
 	// Assertions
 	require.NoError(t, err)
@@ -45,8 +45,10 @@ func TestJWTManager_ValidateAccessToken(t *testing.T) {
 	userID := uuid.New()
 	email := "test@example.com"
 
+	sessionID := uuid.New()
+
 	// Generate token
-	token, _, err := jwtManager.GenerateAccessToken(userID, email)
+	token, _, err := jwtManager.GenerateAccessToken(userID, email, sessionID)
 	require.NoError(t, err)
 
 	// Test validation
@@ -56,6 +58,7 @@ func TestJWTManager_ValidateAccessToken(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, userID, claims.UserID)
 	assert.Equal(t, email, claims.Email)
+	assert.Equal(t, sessionID, claims.SessionID)
 	assert.Equal(t, "test-issuer", claims.Issuer)
 	assert.Equal(t, "test-audience", claims.Audience)
 }
@@ -85,7 +88,7 @@ func TestJWTManager_ValidateAccessToken_WrongSecret(t *testing.T) {
 	)
 
 	userID := uuid.New()
-	token, _, err := jwtManager1.GenerateAccessToken(userID, "test@example.com")
+	token, _, err := jwtManager1.GenerateAccessToken(userID, "test@example.com", uuid.New())
 	require.NoError(t, err)
 
 	// Try to validate with different secret
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	first, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+	second, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.Regexp(t, `^[A-Za-z0-9_-]+$`, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	// SHA-256 of "abc"
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", HashToken("abc"))
+	assert.NotEqual(t, HashToken("abc"), HashToken("abd"))
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJWTManager() *JWTManager {
+	return NewJWTManager(
+		"test-secret-key-32-characters-long",
+		"test-issuer",
+		"test-audience",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+}
+
+func TestJWTManager_EmailVerificationToken(t *testing.T) {
+	jwtManager := newTestJWTManager()
+	userID := uuid.New()
+
+	token, expiresAt, err := jwtManager.GenerateEmailVerificationToken(userID, "test@example.com", time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	gotUserID, email, err := jwtManager.ValidateEmailVerificationToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, gotUserID)
+	assert.Equal(t, "test@example.com", email)
+}
+
+func TestJWTManager_EmailVerificationToken_Rejected(t *testing.T) {
+	jwtManager := newTestJWTManager()
+	userID := uuid.New()
+
+	expired, _, err := jwtManager.GenerateEmailVerificationToken(userID, "test@example.com", -time.Minute)
+	require.NoError(t, err)
+	_, _, err = jwtManager.ValidateEmailVerificationToken(expired)
+	assert.Error(t, err)
+
+	other := NewJWTManager("another-secret-key-32-characters", "test-issuer", "test-audience", time.Minute, time.Hour)
+	forged, _, err := other.GenerateEmailVerificationToken(userID, "test@example.com", time.Hour)
+	require.NoError(t, err)
+	_, _, err = jwtManager.ValidateEmailVerificationToken(forged)
+	assert.Error(t, err)
+
+	// Session tokens are not verification tokens, and vice versa
+	accessToken, _, err := jwtManager.GenerateAccessToken(userID, "test@example.com", uuid.New())
+	require.NoError(t, err)
+	_, _, err = jwtManager.ValidateEmailVerificationToken(accessToken)
+	assert.Error(t, err)
+
+	refreshToken, _, err := jwtManager.GenerateRefreshToken(userID)
+	require.NoError(t, err)
+	_, _, err = jwtManager.ValidateEmailVerificationToken(refreshToken)
+	assert.Error(t, err)
+
+	verification, _, err := jwtManager.GenerateEmailVerificationToken(userID, "test@example.com", time.Hour)
+	require.NoError(t, err)
+	_, err = jwtManager.ValidateAccessToken(verification)
+	assert.Error(t, err)
+	_, err = jwtManager.ValidateRefreshToken(verification)
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package carbon
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider serves carbon intensity from an in-memory series loaded from CSV.
+// The CSV must have a header row with region, timestamp (RFC 3339) and
+// g_co2_per_kwh columns; rows may contain both historic and forecast readings.
+type FileProvider struct {
+	series map[string][]Intensity
+	now    func() time.Time
+}
+
+// NewFileProvider loads a CSV file of intensity readings
+func NewFileProvider(path string) (*FileProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open carbon data file: %w", err)
+	}
+	defer f.Close()
+
+	return LoadCSV(f)
+}
+
+// LoadCSV parses intensity readings from a CSV stream
+func LoadCSV(r io.Reader) (*FileProvider, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read carbon data header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"region", "timestamp", "g_co2_per_kwh"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("carbon data is missing column %q", required)
+		}
+	}
+
+	series := make(map[string][]Intensity)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read carbon data: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		timestamp, err := time.Parse(time.RFC3339, record[columns["timestamp"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp: %w", line, err)
+		}
+
+		value, err := strconv.ParseFloat(record[columns["g_co2_per_kwh"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid intensity: %w", line, err)
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("line %d: intensity must not be negative", line)
+		}
+
+		region := record[columns["region"]]
+		series[region] = append(series[region], Intensity{
+			Region:      region,
+			Timestamp:   timestamp,
+			GramsPerKWh: value,
+		})
+	}
+
+	for region := range series {
+		sortByTimestamp(series[region])
+	}
+
+	return &FileProvider{
+		series: series,
+		now:    time.Now,
+	}, nil
+}
+
+// Regions returns the regions present in the data set
+func (p *FileProvider) Regions() []string {
+	regions := make([]string, 0, len(p.series))
+	for region := range p.series {
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// Current returns the latest reading at or before now
+func (p *FileProvider) Current(ctx context.Context, region string) (Intensity, error) {
+	readings, ok := p.series[region]
+	if !ok {
+		return Intensity{}, ErrRegionNotFound
+	}
+
+	now := p.now()
+	current := window(readings, now, now)
+	if len(current) == 0 {
+		return Intensity{}, ErrNoData
+	}
+
+	return current[0], nil
+}
+
+// Forecast returns the readings covering [from, to]
+func (p *FileProvider) Forecast(ctx context.Context, region string, from, to time.Time) ([]Intensity, error) {
+	readings, ok := p.series[region]
+	if !ok {
+		return nil, ErrRegionNotFound
+	}
+
+	forecast := window(readings, from, to)
+	if len(forecast) == 0 {
+		return nil, ErrNoData
+	}
+
+	return forecast, nil
+}
@@ -0,0 +1,127 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPProvider fetches carbon intensity from a JSON API.
+//
+// The API is expected to expose:
+//
+//	GET {base}/regions/{region}/intensity              -> Intensity
+//	GET {base}/regions/{region}/forecast?from=&to=     -> {"forecast": [Intensity, ...]}
+//
+// Timestamps are RFC 3339. Adapters for grid operator or vendor feeds can be
+// deployed behind this contract without changing the scheduler.
+type HTTPProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// forecastResponse is the payload returned by the forecast endpoint
+type forecastResponse struct {
+	Forecast []Intensity `json:"forecast"`
+}
+
+// NewHTTPProvider creates a provider backed by an HTTP API
+func NewHTTPProvider(baseURL, apiKey string, timeout time.Duration) (*HTTPProvider, error) {
+	if baseURL == "" {
+		return nil, errors.New("carbon: base URL is required")
+	}
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, fmt.Errorf("carbon: invalid base URL: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &HTTPProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Current returns the most recent intensity reading for a region
+func (p *HTTPProvider) Current(ctx context.Context, region string) (Intensity, error) {
+	var reading Intensity
+	if err := p.get(ctx, "/regions/"+url.PathEscape(region)+"/intensity", nil, &reading); err != nil {
+		return Intensity{}, err
+	}
+
+	if reading.Region == "" {
+		reading.Region = region
+	}
+	return reading, nil
+}
+
+// Forecast returns the readings covering [from, to]
+func (p *HTTPProvider) Forecast(ctx context.Context, region string, from, to time.Time) ([]Intensity, error) {
+	query := url.Values{}
+	query.Set("from", from.UTC().Format(time.RFC3339))
+	query.Set("to", to.UTC().Format(time.RFC3339))
+
+	var response forecastResponse
+	if err := p.get(ctx, "/regions/"+url.PathEscape(region)+"/forecast", query, &response); err != nil {
+		return nil, err
+	}
+
+	for i := range response.Forecast {
+		if response.Forecast[i].Region == "" {
+			response.Forecast[i].Region = region
+		}
+	}
+	sortByTimestamp(response.Forecast)
+
+	// The API may send more than was asked for
+	forecast := window(response.Forecast, from, to)
+	if len(forecast) == 0 {
+		return nil, ErrNoData
+	}
+
+	return forecast, nil
+}
+
+// get performs a GET request and decodes the JSON response into out
+func (p *HTTPProvider) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := p.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("carbon: failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("carbon: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrRegionNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("carbon: unexpected status %d from %s", resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("carbon: failed to decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package carbon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrRegionNotFound is returned when a provider has no data for a region
+	ErrRegionNotFound = errors.New("carbon: region not found")
+	// ErrNoData is returned when a region has no readings for the requested time
+	ErrNoData = errors.New("carbon: no intensity data for requested time")
+)
+
+// Intensity is a grid carbon intensity reading for a region at a point in time
+type Intensity struct {
+	Region      string    `json:"region"`
+	Timestamp   time.Time `json:"timestamp"`
+	GramsPerKWh float64   `json:"g_co2_per_kwh"`
+}
+
+// Provider returns current and forecast carbon intensity per region.
+// Implementations must be safe for concurrent use.
+type Provider interface {
+	// Current returns the most recent intensity reading for a region
+	Current(ctx context.Context, region string) (Intensity, error)
+	// Forecast returns readings covering [from, to], ordered by timestamp.
+	// The first reading is the one in effect at from.
+	Forecast(ctx context.Context, region string, from, to time.Time) ([]Intensity, error)
+}
+
+// Config selects and configures a provider implementation
+type Config struct {
+	Source   string        // "file" or "http"
+	FilePath string        // CSV file for the file source
+	BaseURL  string        // API base URL for the http source
+	APIKey   string        // Optional bearer token for the http source
+	Timeout  time.Duration // Request timeout for the http source
+}
+
+// NewProvider creates the provider described by config
+func NewProvider(config Config) (Provider, error) {
+	switch config.Source {
+	case "file":
+		return NewFileProvider(config.FilePath)
+	case "http":
+		return NewHTTPProvider(config.BaseURL, config.APIKey, config.Timeout)
+	default:
+		return nil, fmt.Errorf("carbon: unknown provider source %q", config.Source)
+	}
+}
+
+// sortByTimestamp orders readings from oldest to newest
+func sortByTimestamp(readings []Intensity) {
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+}
+
+// window returns the readings covering [from, to] from a sorted series,
+// including the latest reading at or before from
+func window(readings []Intensity, from, to time.Time) []Intensity {
+	start := sort.Search(len(readings), func(i int) bool {
+		return readings[i].Timestamp.After(from)
+	})
+	if start > 0 {
+		start--
+	}
+
+	var result []Intensity
+	for _, r := range readings[start:] {
+		if r.Timestamp.After(to) {
+			break
+		}
+		result = append(result, r)
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = `region,timestamp,g_co2_per_kwh
+westeurope,2025-01-01T00:00:00Z,300
+westeurope,2025-01-01T02:00:00Z,150
+westeurope,2025-01-01T01:00:00Z,250
+swedencentral,2025-01-01T00:00:00Z,20
+`
+
+func TestLoadCSV_Current(t *testing.T) {
+	provider, err := LoadCSV(strings.NewReader(testCSV))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"westeurope", "swedencentral"}, provider.Regions())
+
+	provider.now = func() time.Time { return time.Date(2025, 1, 1, 1, 30, 0, 0, time.UTC) }
+
+	current, err := provider.Current(context.Background(), "westeurope")
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, current.GramsPerKWh)
+
+	_, err = provider.Current(context.Background(), "eastus")
+	assert.ErrorIs(t, err, ErrRegionNotFound)
+
+	// Nothing is known before the first reading
+	provider.now = func() time.Time { return time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC) }
+	_, err = provider.Current(context.Background(), "westeurope")
+	assert.ErrorIs(t, err, ErrNoData)
+}
+
+func TestLoadCSV_Forecast(t *testing.T) {
+	provider, err := LoadCSV(strings.NewReader(testCSV))
+	require.NoError(t, err)
+
+	from := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	forecast, err := provider.Forecast(context.Background(), "westeurope", from, to)
+	require.NoError(t, err)
+	require.Len(t, forecast, 3)
+
+	// The reading in effect at from comes first, then readings in order
+	assert.Equal(t, 300.0, forecast[0].GramsPerKWh)
+	assert.Equal(t, 250.0, forecast[1].GramsPerKWh)
+	assert.Equal(t, 150.0, forecast[2].GramsPerKWh)
+}
+
+func TestLoadCSV_InvalidData(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing column", "region,timestamp\nwesteurope,2025-01-01T00:00:00Z\n"},
+		{"bad timestamp", "region,timestamp,g_co2_per_kwh\nwesteurope,yesterday,100\n"},
+		{"bad intensity", "region,timestamp,g_co2_per_kwh\nwesteurope,2025-01-01T00:00:00Z,lots\n"},
+		{"negative intensity", "region,timestamp,g_co2_per_kwh\nwesteurope,2025-01-01T00:00:00Z,-1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCSV(strings.NewReader(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/regions/westeurope/intensity":
+			json.NewEncoder(w).Encode(Intensity{
+				Timestamp:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				GramsPerKWh: 210,
+			})
+		case "/regions/westeurope/forecast":
+			assert.Equal(t, "2025-01-01T00:00:00Z", r.URL.Query().Get("from"))
+			// Readings outside the requested range are dropped
+			json.NewEncoder(w).Encode(forecastResponse{Forecast: []Intensity{
+				{Timestamp: time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC), GramsPerKWh: 180},
+				{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), GramsPerKWh: 200},
+				{Timestamp: time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC), GramsPerKWh: 90},
+				{Timestamp: time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC), GramsPerKWh: 60},
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL, "test-key", time.Second)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	current, err := provider.Current(ctx, "westeurope")
+	require.NoError(t, err)
+	assert.Equal(t, "westeurope", current.Region)
+	assert.Equal(t, 210.0, current.GramsPerKWh)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	forecast, err := provider.Forecast(ctx, "westeurope", from, from.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, forecast, 2)
+	assert.Equal(t, 200.0, forecast[0].GramsPerKWh)
+	assert.Equal(t, 180.0, forecast[1].GramsPerKWh)
+
+	_, err = provider.Current(ctx, "eastus")
+	assert.ErrorIs(t, err, ErrRegionNotFound)
+}
+
+func TestNewProvider(t *testing.T) {
+	_, err := NewProvider(Config{Source: "carrier-pigeon"})
+	assert.Error(t, err)
+
+	_, err = NewProvider(Config{Source: "http"})
+	assert.Error(t, err)
+
+	provider, err := NewProvider(Config{Source: "http", BaseURL: "http://localhost:9999"})
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPProvider{}, provider)
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ClaimOptions configures how a worker leases pending executions
+type ClaimOptions struct {
+	Owner         string        // Unique identifier of the claiming worker
+	BatchSize     int32         // Maximum number of executions to lease
+	LeaseDuration time.Duration // How long the worker may hold each execution
+}
+
+// ClaimExecutions returns expired leases to pending, then atomically leases a batch of
+// pending executions to the given owner, recording each transition. Rows locked by
+// other workers are skipped, so concurrent schedulers never receive the same execution.
+func (q *Queries) ClaimExecutions(ctx context.Context, opts ClaimOptions) ([]Execution, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ReleaseExpiredLeases(ctx, ExecutionActorScheduler); err != nil {
+		return nil, fmt.Errorf("failed to release expired leases: %w", err)
+	}
+
+	executions, err := q.ClaimPendingExecutions(ctx, ClaimPendingExecutionsParams{
+		LeaseOwner:   opts.Owner,
+		LeaseSeconds: int32(opts.LeaseDuration / time.Second),
+		BatchSize:    opts.BatchSize,
+		Actor:        ExecutionActorScheduler,
+		Reason:       "claimed by scheduler " + opts.Owner,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending executions: %w", err)
+	}
+
+	return executions, nil
+}
+
+// ClaimDueExecutions leases a batch of scheduled executions whose planned start time
+// has passed. The worker renews the lease until the execution finishes. If it dies
+// before the execution starts, the lease expires and the execution returns to
+// pending for rescheduling; if it dies later, the reconciler settles the execution.
+func (q *Queries) ClaimDueExecutions(ctx context.Context, opts ClaimOptions) ([]Execution, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.ReleaseExpiredLeases(ctx, ExecutionActorExecutor); err != nil {
+		return nil, fmt.Errorf("failed to release expired leases: %w", err)
+	}
+
+	executions, err := q.LeaseDueExecutions(ctx, LeaseDueExecutionsParams{
+		LeaseOwner:   opts.Owner,
+		LeaseSeconds: int32(opts.LeaseDuration / time.Second),
+		BatchSize:    opts.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due executions: %w", err)
+	}
+
+	return executions, nil
+}
+
+// validate checks that the options describe a usable lease
+func (opts ClaimOptions) validate() error {
+	if opts.Owner == "" {
+		return errors.New("claim owner is required")
+	}
+	if opts.BatchSize <= 0 {
+		return errors.New("claim batch size must be positive")
+	}
+	if opts.LeaseDuration < time.Second {
+		return errors.New("claim lease duration must be at least one second")
+	}
+	return nil
+}
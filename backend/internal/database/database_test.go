@@ -3,11 +3,16 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/netip"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
@@ -252,16 +257,39 @@ func (suite *DatabaseTestSuite) TestExecutionOperations() {
 	assert.Equal(suite.T(), testStatus, execution.Status)
 	assert.False(suite.T(), execution.CreatedAt.IsZero())
 
-	// Test UpdateExecutionStatus
-	newStatus := ExecutionStatusRunning
+	// Status changes record their transition in the same statement
+	claimed, err := suite.queries.ClaimPendingExecutions(suite.ctx, ClaimPendingExecutionsParams{
+		LeaseOwner:   "test-worker",
+		LeaseSeconds: 60,
+		BatchSize:    10,
+		Actor:        ExecutionActorScheduler,
+		Reason:       "claimed for scheduling",
+	})
+	require.NoError(suite.T(), err)
+	require.NotEmpty(suite.T(), claimed)
 
-	updatedExecution, err := suite.queries.UpdateExecutionStatus(suite.ctx, UpdateExecutionStatusParams{
-		ID:     execution.ID,
-		Status: newStatus,
+	updatedExecution, err := suite.queries.GetExecution(suite.ctx, execution.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ExecutionStatusEvaluating, updatedExecution.Status)
+
+	// Changes from a status the execution is no longer in are not applied or recorded
+	_, err = suite.queries.UpdateExecutionComplete(suite.ctx, UpdateExecutionCompleteParams{
+		ID:         execution.ID,
+		FromStatus: ExecutionStatusPending,
+		Status:     ExecutionStatusCompletedError,
+		Actor:      ExecutionActorScheduler,
+		Reason:     "stale",
 	})
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	// Test GetExecutionEvents
+	events, err := suite.queries.GetExecutionEvents(suite.ctx, execution.ID)
 	require.NoError(suite.T(), err)
-	assert.Equal(suite.T(), execution.ID, updatedExecution.ID)
-	assert.Equal(suite.T(), newStatus, updatedExecution.Status)
+	require.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), ExecutionStatusPending, events[0].FromStatus.ExecutionStatus)
+	assert.Equal(suite.T(), ExecutionStatusEvaluating, events[0].ToStatus)
+	assert.Equal(suite.T(), ExecutionActorScheduler, events[0].Actor)
+	assert.Equal(suite.T(), "claimed for scheduling", events[0].Reason)
 
 	// Test GetExecutionsByJobID
 	executions, err := suite.queries.GetExecutionsByJobID(suite.ctx, job.ID)
@@ -275,6 +303,409 @@ func (suite *DatabaseTestSuite) TestExecutionOperations() {
 	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", createdUserID)
 }
 
+// TestClaimExecutions tests leasing pending executions across workers
+func (suite *DatabaseTestSuite) TestClaimExecutions() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "claim@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	job, err := suite.queries.CreateJob(suite.ctx, CreateJobParams{
+		OwnerID:             user.ID,
+		ImageUri:            "nginx:latest",
+		EnvVars:             []byte(`{}`),
+		DelayToleranceHours: 0,
+	})
+	require.NoError(suite.T(), err)
+
+	for i := 0; i < 3; i++ {
+		_, err := suite.queries.CreateExecution(suite.ctx, CreateExecutionParams{
+			JobID:  job.ID,
+			Status: ExecutionStatusPending,
+		})
+		require.NoError(suite.T(), err)
+	}
+
+	// First worker leases two executions
+	claimed, err := suite.queries.ClaimExecutions(suite.ctx, ClaimOptions{
+		Owner:         "worker-a",
+		BatchSize:     2,
+		LeaseDuration: time.Minute,
+	})
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), claimed, 2)
+	for _, e := range claimed {
+		assert.Equal(suite.T(), ExecutionStatusEvaluating, e.Status)
+		require.NotNil(suite.T(), e.LeaseOwner)
+		assert.Equal(suite.T(), "worker-a", *e.LeaseOwner)
+		assert.True(suite.T(), e.LeaseExpiresAt.Valid)
+	}
+
+	// Second worker only receives the remaining execution
+	claimed, err = suite.queries.ClaimExecutions(suite.ctx, ClaimOptions{
+		Owner:         "worker-b",
+		BatchSize:     10,
+		LeaseDuration: time.Minute,
+	})
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), claimed, 1)
+
+	// Expired leases return to pending
+	_, err = suite.db.Exec(suite.ctx, "UPDATE executions SET lease_expires_at = now() - interval '1 second' WHERE job_id = $1", job.ID)
+	require.NoError(suite.T(), err)
+
+	released, err := suite.queries.ReleaseExpiredLeases(suite.ctx, ExecutionActorScheduler)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), released)
+
+	pending, err := suite.queries.GetPendingExecutions(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), pending, 3)
+
+	// Clean up
+	suite.db.Exec(suite.ctx, "DELETE FROM executions WHERE job_id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM jobs WHERE id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestUserExecutionStats tests dashboard aggregates over a time range
+func (suite *DatabaseTestSuite) TestUserExecutionStats() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "stats@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	job, err := suite.queries.CreateJob(suite.ctx, CreateJobParams{
+		OwnerID:             user.ID,
+		ImageUri:            "nginx:latest",
+		EnvVars:             []byte(`{}`),
+		DelayToleranceHours: 4,
+	})
+	require.NoError(suite.T(), err)
+
+	for i := 0; i < 2; i++ {
+		_, err := suite.queries.CreateExecution(suite.ctx, CreateExecutionParams{
+			JobID:  job.ID,
+			Status: ExecutionStatusPending,
+		})
+		require.NoError(suite.T(), err)
+	}
+
+	// One execution was deferred two hours and saved against its baseline
+	_, err = suite.db.Exec(suite.ctx, `UPDATE executions SET status = 'completed_success',
+		planned_start_at = created_at + interval '2 hours',
+		cost_actual_usd = 0.1, baseline_cost_usd = 0.15,
+		carbon_emitted_kg = 0.002, baseline_carbon_kg = 0.005
+		WHERE id = (SELECT id FROM executions WHERE job_id = $1 LIMIT 1)`, job.ID)
+	require.NoError(suite.T(), err)
+
+	now := time.Now()
+	stats, err := suite.queries.GetUserExecutionStats(suite.ctx, GetUserExecutionStatsParams{
+		OwnerID:     user.ID,
+		CreatedFrom: now.Add(-time.Hour),
+		CreatedTo:   now.Add(time.Hour),
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), stats.TotalExecutions)
+	assert.Equal(suite.T(), int64(1), stats.PendingExecutions)
+	assert.Equal(suite.T(), int64(1), stats.SuccessfulExecutions)
+	assert.InDelta(suite.T(), 0.1, stats.TotalCost, 1e-9)
+	assert.InDelta(suite.T(), 0.05, stats.CostSavings, 1e-9)
+	assert.InDelta(suite.T(), 0.003, stats.CarbonSavings, 1e-9)
+	assert.InDelta(suite.T(), 7200, stats.AvgDeferralSeconds, 1e-6)
+
+	buckets, err := suite.queries.GetUserExecutionStatsByBucket(suite.ctx, GetUserExecutionStatsByBucketParams{
+		Bucket:      "day",
+		OwnerID:     user.ID,
+		CreatedFrom: now.Add(-time.Hour),
+		CreatedTo:   now.Add(time.Hour),
+	})
+	require.NoError(suite.T(), err)
+	require.NotEmpty(suite.T(), buckets)
+
+	// Executions outside the range are excluded
+	stats, err = suite.queries.GetUserExecutionStats(suite.ctx, GetUserExecutionStatsParams{
+		OwnerID:     user.ID,
+		CreatedFrom: now.Add(time.Hour),
+		CreatedTo:   now.Add(2 * time.Hour),
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), stats.TotalExecutions)
+
+	// Clean up
+	suite.db.Exec(suite.ctx, "DELETE FROM executions WHERE job_id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM jobs WHERE id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestStreamSustainabilityReport tests the monthly footprint aggregation
+func (suite *DatabaseTestSuite) TestStreamSustainabilityReport() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "report@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	job, err := suite.queries.CreateJob(suite.ctx, CreateJobParams{
+		OwnerID:             user.ID,
+		ImageUri:            "etl:1",
+		EnvVars:             []byte(`{}`),
+		DelayToleranceHours: 0,
+	})
+	require.NoError(suite.T(), err)
+
+	for i := 0; i < 2; i++ {
+		_, err := suite.queries.CreateExecution(suite.ctx, CreateExecutionParams{
+			JobID:  job.ID,
+			Status: ExecutionStatusPending,
+		})
+		require.NoError(suite.T(), err)
+	}
+
+	_, err = suite.db.Exec(suite.ctx, `UPDATE executions SET status = 'completed_success', cloud_region = 'westeurope',
+		started_at = '2025-01-10T10:00:00Z', completed_at = '2025-01-10T12:00:00Z',
+		energy_kwh = 0.02, carbon_emitted_kg = 0.002
+		WHERE job_id = $1`, job.ID)
+	require.NoError(suite.T(), err)
+
+	var rows []SustainabilityReportRow
+	err = suite.queries.StreamSustainabilityReport(suite.ctx, SustainabilityReportParams{
+		OwnerID: user.ID,
+		From:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	}, func(row SustainabilityReportRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rows, 1)
+	assert.Equal(suite.T(), "westeurope", rows[0].Region)
+	assert.Equal(suite.T(), int64(2), rows[0].Executions)
+	assert.InDelta(suite.T(), 4, rows[0].RuntimeHours, 1e-9)
+	assert.InDelta(suite.T(), 0.04, rows[0].EnergyKwh, 1e-9)
+	assert.InDelta(suite.T(), 0.004, rows[0].EmissionsKg, 1e-9)
+
+	// Clean up
+	suite.db.Exec(suite.ctx, "DELETE FROM executions WHERE job_id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM jobs WHERE id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+func (suite *DatabaseTestSuite) TestExecutionNotifications() {
+	conn, err := suite.db.Acquire(suite.ctx)
+	require.NoError(suite.T(), err)
+	defer conn.Release()
+	_, err = conn.Exec(suite.ctx, "LISTEN "+ExecutionEventsChannel)
+	require.NoError(suite.T(), err)
+	defer conn.Exec(suite.ctx, "UNLISTEN "+ExecutionEventsChannel)
+
+	next := func() ExecutionNotification {
+		ctx, cancel := context.WithTimeout(suite.ctx, 5*time.Second)
+		defer cancel()
+		n, err := conn.Conn().WaitForNotification(ctx)
+		require.NoError(suite.T(), err)
+
+		var notification ExecutionNotification
+		require.NoError(suite.T(), json.Unmarshal([]byte(n.Payload), &notification))
+		return notification
+	}
+
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "notify@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	job, err := suite.queries.CreateJob(suite.ctx, CreateJobParams{
+		OwnerID:             user.ID,
+		ImageUri:            "etl:1",
+		EnvVars:             []byte(`{}`),
+		DelayToleranceHours: 0,
+	})
+	require.NoError(suite.T(), err)
+
+	execution, err := suite.queries.CreateExecution(suite.ctx, CreateExecutionParams{
+		JobID:  job.ID,
+		Status: ExecutionStatusPending,
+	})
+	require.NoError(suite.T(), err)
+
+	created := next()
+	assert.Equal(suite.T(), ExecutionNotificationStatus, created.Type)
+	assert.Equal(suite.T(), execution.ID, created.ExecutionID)
+	assert.Equal(suite.T(), user.ID, created.OwnerID)
+	assert.Equal(suite.T(), ExecutionStatusPending, created.Status)
+
+	// Updates that leave the status unchanged are not broadcast
+	_, err = suite.db.Exec(suite.ctx, "UPDATE executions SET cloud_region = 'westeurope' WHERE id = $1", execution.ID)
+	require.NoError(suite.T(), err)
+	_, err = suite.db.Exec(suite.ctx, "UPDATE executions SET status = 'running' WHERE id = $1", execution.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ExecutionStatusRunning, next().Status)
+
+	err = suite.queries.NotifyExecutionEvent(suite.ctx, ExecutionNotification{
+		Type:        ExecutionNotificationLog,
+		ExecutionID: execution.ID,
+		JobID:       job.ID,
+		OwnerID:     user.ID,
+		Lines:       []string{"hello"},
+		At:          time.Now(),
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"hello"}, next().Lines)
+
+	// Clean up
+	suite.db.Exec(suite.ctx, "DELETE FROM executions WHERE job_id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM jobs WHERE id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestWebhookDeliveries tests that execution changes are enqueued for matching
+// subscriptions and that deliveries are leased and recorded
+func (suite *DatabaseTestSuite) TestWebhookDeliveries() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "webhooks@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	all, err := suite.queries.CreateWebhookSubscription(suite.ctx, CreateWebhookSubscriptionParams{
+		OwnerID: user.ID,
+		Url:     "https://example.com/all",
+		Secret:  "secret-all",
+		Events:  []string{},
+	})
+	require.NoError(suite.T(), err)
+	completions, err := suite.queries.CreateWebhookSubscription(suite.ctx, CreateWebhookSubscriptionParams{
+		OwnerID: user.ID,
+		Url:     "https://example.com/completions",
+		Secret:  "secret-completions",
+		Events:  []string{"completed_success", "completed_error"},
+	})
+	require.NoError(suite.T(), err)
+
+	job, err := suite.queries.CreateJob(suite.ctx, CreateJobParams{
+		OwnerID:             user.ID,
+		ImageUri:            "etl:1",
+		EnvVars:             []byte(`{}`),
+		DelayToleranceHours: 0,
+	})
+	require.NoError(suite.T(), err)
+
+	execution, err := suite.queries.CreateExecution(suite.ctx, CreateExecutionParams{
+		JobID:  job.ID,
+		Status: ExecutionStatusPending,
+	})
+	require.NoError(suite.T(), err)
+
+	for _, update := range []string{
+		"UPDATE executions SET chosen_at = now(), cloud_region = 'westeurope' WHERE id = $1",
+		"UPDATE executions SET vm_type = 'Standard_D2s_v5' WHERE id = $1",
+		"UPDATE executions SET status = 'running', started_at = now() WHERE id = $1",
+		"UPDATE executions SET status = 'completed_success', completed_at = now(), exit_code = 0 WHERE id = $1",
+	} {
+		_, err = suite.db.Exec(suite.ctx, update, execution.ID)
+		require.NoError(suite.T(), err)
+	}
+
+	events := func(subscriptionID uuid.UUID) []string {
+		deliveries, err := suite.queries.ListWebhookDeliveries(suite.ctx, ListWebhookDeliveriesParams{
+			SubscriptionID: subscriptionID,
+			Limit:          10,
+		})
+		require.NoError(suite.T(), err)
+
+		names := make([]string, len(deliveries))
+		for i, delivery := range deliveries {
+			names[i] = delivery.Event
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal(suite.T(), []string{"completed_success", "scheduled", "started"}, events(all.ID))
+	assert.Equal(suite.T(), []string{"completed_success"}, events(completions.ID))
+
+	// Claimed deliveries are leased, so a second claim skips them
+	claimed, err := suite.queries.ClaimDueWebhookDeliveries(suite.ctx, ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: 60,
+		BatchSize:    10,
+	})
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), claimed, 4)
+
+	claimed, err = suite.queries.ClaimDueWebhookDeliveries(suite.ctx, ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: 60,
+		BatchSize:    10,
+	})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), claimed)
+
+	delivery, err := suite.queries.ListWebhookDeliveries(suite.ctx, ListWebhookDeliveriesParams{
+		SubscriptionID: completions.ID,
+		Limit:          1,
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), delivery, 1)
+
+	var payload struct {
+		Event     string `json:"event"`
+		Execution struct {
+			ID     uuid.UUID `json:"id"`
+			Status string    `json:"status"`
+		} `json:"execution"`
+	}
+	require.NoError(suite.T(), json.Unmarshal(delivery[0].Payload, &payload))
+	assert.Equal(suite.T(), "completed_success", payload.Event)
+	assert.Equal(suite.T(), execution.ID, payload.Execution.ID)
+
+	statusCode := int32(200)
+	err = suite.queries.CreateWebhookDeliveryAttempt(suite.ctx, CreateWebhookDeliveryAttemptParams{
+		DeliveryID: delivery[0].ID,
+		StatusCode: &statusCode,
+		DurationMs: 12,
+	})
+	require.NoError(suite.T(), err)
+	err = suite.queries.UpdateWebhookDeliveryAttempt(suite.ctx, UpdateWebhookDeliveryAttemptParams{
+		ID:            delivery[0].ID,
+		Status:        WebhookDeliveryStatusDelivered,
+		NextAttemptAt: time.Now(),
+		DeliveredAt:   null.TimeFrom(time.Now()),
+	})
+	require.NoError(suite.T(), err)
+
+	delivered, err := suite.queries.GetWebhookDelivery(suite.ctx, GetWebhookDeliveryParams{
+		ID:             delivery[0].ID,
+		SubscriptionID: completions.ID,
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), WebhookDeliveryStatusDelivered, delivered.Status)
+	assert.Equal(suite.T(), int32(1), delivered.Attempts)
+	assert.True(suite.T(), delivered.DeliveredAt.Valid)
+
+	attempts, err := suite.queries.ListWebhookDeliveryAttempts(suite.ctx, delivered.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), attempts, 1)
+	assert.Equal(suite.T(), &statusCode, attempts[0].StatusCode)
+
+	// Clean up; deliveries and attempts cascade
+	suite.db.Exec(suite.ctx, "DELETE FROM webhook_subscriptions WHERE owner_id = $1", user.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM executions WHERE job_id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM jobs WHERE id = $1", job.ID)
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
 // TestRefreshTokenOperations tests refresh token database operations
 func (suite *DatabaseTestSuite) TestRefreshTokenOperations() {
 	testEmail := "test@example.com"
@@ -300,6 +731,7 @@ func (suite *DatabaseTestSuite) TestRefreshTokenOperations() {
 		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
 		UserAgent: nil,
 		IpAddress: nil,
+		FamilyID:  uuid.New(),
 	})
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), createdUserID, refreshToken.UserID)
@@ -327,6 +759,246 @@ func (suite *DatabaseTestSuite) TestRefreshTokenOperations() {
 	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", createdUserID)
 }
 
+// TestPasswordResetTokens tests that reset tokens are single-use and expire
+func (suite *DatabaseTestSuite) TestPasswordResetTokens() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "reset@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	create := func(hash string, expiresAt time.Time) {
+		_, err := suite.queries.CreatePasswordResetToken(suite.ctx, CreatePasswordResetTokenParams{
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		})
+		require.NoError(suite.T(), err)
+	}
+	create("usable", time.Now().Add(time.Hour))
+	create("expired", time.Now().Add(-time.Minute))
+	create("outstanding", time.Now().Add(time.Hour))
+
+	token, err := suite.queries.ConsumePasswordResetToken(suite.ctx, "usable")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.ID, token.UserID)
+	assert.True(suite.T(), token.UsedAt.Valid)
+
+	// A token works once
+	_, err = suite.queries.ConsumePasswordResetToken(suite.ctx, "usable")
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	_, err = suite.queries.ConsumePasswordResetToken(suite.ctx, "expired")
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	require.NoError(suite.T(), suite.queries.InvalidateUserPasswordResetTokens(suite.ctx, user.ID))
+	_, err = suite.queries.ConsumePasswordResetToken(suite.ctx, "outstanding")
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	// Clean up; tokens cascade
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestRefreshTokenFamilies tests token rotation and family revocation
+func (suite *DatabaseTestSuite) TestRefreshTokenFamilies() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "families@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	expiresAt := pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	create := func(hash string, familyID uuid.UUID) RefreshToken {
+		token, err := suite.queries.CreateRefreshToken(suite.ctx, CreateRefreshTokenParams{
+			UserID:    user.ID,
+			TokenHash: hash,
+			ExpiresAt: expiresAt,
+			FamilyID:  familyID,
+		})
+		require.NoError(suite.T(), err)
+		return token
+	}
+
+	family, otherFamily := uuid.New(), uuid.New()
+	first := create("family-first", family)
+	create("other-device", otherFamily)
+
+	// Rotation retires the token but keeps it for reuse detection
+	require.NoError(suite.T(), suite.queries.RotateRefreshToken(suite.ctx, first.ID))
+	create("family-second", family)
+
+	rotated, err := suite.queries.GetRefreshTokenForUpdate(suite.ctx, "family-first")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), rotated.IsRevoked)
+	assert.True(suite.T(), rotated.RotatedAt.Valid)
+	assert.Equal(suite.T(), family, rotated.FamilyID)
+
+	_, err = suite.queries.GetRefreshToken(suite.ctx, "family-first")
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	// Cleanup keeps rotated tokens while their family is live and drops dead families
+	_, err = suite.queries.CreateRefreshToken(suite.ctx, CreateRefreshTokenParams{
+		UserID:    user.ID,
+		TokenHash: "family-expired",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		FamilyID:  uuid.New(),
+	})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.queries.DeleteExpiredRefreshTokens(suite.ctx))
+
+	_, err = suite.queries.GetRefreshTokenForUpdate(suite.ctx, "family-first")
+	assert.NoError(suite.T(), err)
+	_, err = suite.queries.GetRefreshTokenForUpdate(suite.ctx, "family-expired")
+	assert.ErrorIs(suite.T(), err, pgx.ErrNoRows)
+
+	// Revoking the family leaves other sessions alone
+	require.NoError(suite.T(), suite.queries.RevokeRefreshTokenFamily(suite.ctx, family))
+	active, err := suite.queries.GetUserRefreshTokens(suite.ctx, user.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), active, 1)
+	assert.Equal(suite.T(), otherFamily, active[0].FamilyID)
+
+	err = suite.queries.CreateSecurityEvent(suite.ctx, CreateSecurityEventParams{
+		UserID:    user.ID,
+		EventType: SecurityEventTypeRefreshTokenReuse,
+		Details:   []byte(`{"family_id":"` + family.String() + `"}`),
+	})
+	require.NoError(suite.T(), err)
+
+	// Clean up; tokens and events cascade
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestRevokeSessions tests revoking one session, or all but one, of a user
+func (suite *DatabaseTestSuite) TestRevokeSessions() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "sessions@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  false,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	sessions := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, familyID := range sessions {
+		_, err := suite.queries.CreateRefreshToken(suite.ctx, CreateRefreshTokenParams{
+			UserID:    user.ID,
+			TokenHash: fmt.Sprintf("session-%d", i),
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+			FamilyID:  familyID,
+		})
+		require.NoError(suite.T(), err)
+	}
+
+	revoked, err := suite.queries.RevokeUserRefreshTokenFamily(suite.ctx, RevokeUserRefreshTokenFamilyParams{
+		UserID:   user.ID,
+		FamilyID: sessions[0],
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), revoked)
+
+	// Sessions of other users cannot be revoked
+	revoked, err = suite.queries.RevokeUserRefreshTokenFamily(suite.ctx, RevokeUserRefreshTokenFamilyParams{
+		UserID:   uuid.New(),
+		FamilyID: sessions[1],
+	})
+	require.NoError(suite.T(), err)
+	assert.Zero(suite.T(), revoked)
+
+	revoked, err = suite.queries.RevokeOtherUserRefreshTokens(suite.ctx, RevokeOtherUserRefreshTokensParams{
+		UserID:   user.ID,
+		FamilyID: sessions[2],
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), revoked)
+
+	active, err := suite.queries.GetUserRefreshTokens(suite.ctx, user.ID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), active, 1)
+	assert.Equal(suite.T(), sessions[2], active[0].FamilyID)
+
+	// Clean up; tokens cascade
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
+// TestAPIKeys tests API key lookup, expiry, usage tracking and revocation
+func (suite *DatabaseTestSuite) TestAPIKeys() {
+	user, err := suite.queries.CreateUser(suite.ctx, CreateUserParams{
+		Email:          "apikeys@example.com",
+		HashedPassword: "$2a$10$hashedpasswordexample",
+		EmailVerified:  true,
+		IsActive:       true,
+	})
+	require.NoError(suite.T(), err)
+
+	key, err := suite.queries.CreateAPIKey(suite.ctx, CreateAPIKeyParams{
+		UserID:  user.ID,
+		Name:    "ci",
+		Prefix:  "vrd_00000001",
+		KeyHash: "api-key-active",
+		Scopes:  []string{"jobs:read", "jobs:write"},
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"jobs:read", "jobs:write"}, key.Scopes)
+	assert.False(suite.T(), key.ExpiresAt.Valid)
+
+	_, err = suite.queries.CreateAPIKey(suite.ctx, CreateAPIKeyParams{
+		UserID:    user.ID,
+		Name:      "expired",
+		Prefix:    "vrd_00000002",
+		KeyHash:   "api-key-expired",
+		Scopes:    []string{"jobs:read"},
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	require.NoError(suite.T(), err)
+
+	found, err := suite.queries.GetActiveAPIKeyByHash(suite.ctx, "api-key-active")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), key.ID, found.ID)
+
+	_, err = suite.queries.GetActiveAPIKeyByHash(suite.ctx, "api-key-expired")
+	assert.Error(suite.T(), err)
+
+	// Use is recorded at most once a minute
+	ip := netip.MustParseAddr("192.0.2.10")
+	require.NoError(suite.T(), suite.queries.UpdateAPIKeyLastUsed(suite.ctx, UpdateAPIKeyLastUsedParams{ID: key.ID, LastUsedIp: &ip}))
+	other := netip.MustParseAddr("192.0.2.20")
+	require.NoError(suite.T(), suite.queries.UpdateAPIKeyLastUsed(suite.ctx, UpdateAPIKeyLastUsedParams{ID: key.ID, LastUsedIp: &other}))
+
+	found, err = suite.queries.GetActiveAPIKeyByHash(suite.ctx, "api-key-active")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), found.LastUsedAt.Valid)
+	require.NotNil(suite.T(), found.LastUsedIp)
+	assert.Equal(suite.T(), ip, *found.LastUsedIp)
+
+	keys, err := suite.queries.ListUserAPIKeys(suite.ctx, user.ID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 2)
+
+	// Keys of other users cannot be revoked
+	revoked, err := suite.queries.RevokeAPIKey(suite.ctx, RevokeAPIKeyParams{ID: key.ID, UserID: uuid.New()})
+	require.NoError(suite.T(), err)
+	assert.Zero(suite.T(), revoked)
+
+	revoked, err = suite.queries.RevokeAPIKey(suite.ctx, RevokeAPIKeyParams{ID: key.ID, UserID: user.ID})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), revoked)
+
+	_, err = suite.queries.GetActiveAPIKeyByHash(suite.ctx, "api-key-active")
+	assert.Error(suite.T(), err)
+
+	keys, err = suite.queries.ListUserAPIKeys(suite.ctx, user.ID)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 1)
+
+	// Clean up; keys cascade
+	suite.db.Exec(suite.ctx, "DELETE FROM users WHERE id = $1", user.ID)
+}
+
 // TestTransactionOperations tests database operations with transactions
 func (suite *DatabaseTestSuite) TestTransactionOperations() {
 	testEmail := "test@example.com"
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrIllegalTransition is returned when the execution state machine forbids a status change
+var ErrIllegalTransition = errors.New("illegal execution status transition")
+
+// executionTransitions lists the statuses each status may move to. Executions stay
+// evaluating while they are placed by the scheduler and then leased by an executor;
+// if the lease expires they return to pending. Finished executions only move to
+// orphaned when their instance outlives them, and back once it is torn down.
+var executionTransitions = map[ExecutionStatus][]ExecutionStatus{
+	ExecutionStatusPending:          {ExecutionStatusEvaluating},
+	ExecutionStatusEvaluating:       {ExecutionStatusEvaluating, ExecutionStatusPending, ExecutionStatusRunning, ExecutionStatusCompletedError},
+	ExecutionStatusRunning:          {ExecutionStatusCompletedSuccess, ExecutionStatusCompletedError, ExecutionStatusOrphaned},
+	ExecutionStatusCompletedSuccess: {ExecutionStatusOrphaned},
+	ExecutionStatusCompletedError:   {ExecutionStatusOrphaned},
+	ExecutionStatusOrphaned:         {ExecutionStatusCompletedSuccess, ExecutionStatusCompletedError},
+}
+
+// CanTransitionTo reports whether an execution in status e may move to next
+func (e ExecutionStatus) CanTransitionTo(next ExecutionStatus) bool {
+	for _, allowed := range executionTransitions[e] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition is a status change of one execution, as recorded in execution_events
+type Transition struct {
+	ExecutionID uuid.UUID
+	From        ExecutionStatus // Empty when the execution is being created
+	To          ExecutionStatus
+	Actor       ExecutionActor
+	Reason      string
+}
+
+// Validate checks the transition against the execution state machine. New
+// executions must start out pending.
+func (t Transition) Validate() error {
+	if !t.Actor.Valid() {
+		return fmt.Errorf("invalid execution actor %q", t.Actor)
+	}
+
+	if t.From == "" {
+		if t.To != ExecutionStatusPending {
+			return fmt.Errorf("%w: executions are created pending, not %s", ErrIllegalTransition, t.To)
+		}
+		return nil
+	}
+
+	if !t.From.CanTransitionTo(t.To) {
+		return fmt.Errorf("%w: %s to %s", ErrIllegalTransition, t.From, t.To)
+	}
+	return nil
+}
+
+// Event returns the parameters that record the transition in execution_events
+func (t Transition) Event() CreateExecutionEventParams {
+	return CreateExecutionEventParams{
+		ExecutionID: t.ExecutionID,
+		FromStatus:  NullExecutionStatus{ExecutionStatus: t.From, Valid: t.From != ""},
+		ToStatus:    t.To,
+		Actor:       t.Actor,
+		Reason:      t.Reason,
+	}
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to ExecutionStatus
+		allowed  bool
+	}{
+		{ExecutionStatusPending, ExecutionStatusEvaluating, true},
+		{ExecutionStatusEvaluating, ExecutionStatusEvaluating, true},
+		{ExecutionStatusEvaluating, ExecutionStatusPending, true},
+		{ExecutionStatusEvaluating, ExecutionStatusRunning, true},
+		{ExecutionStatusRunning, ExecutionStatusCompletedSuccess, true},
+		{ExecutionStatusCompletedError, ExecutionStatusOrphaned, true},
+		{ExecutionStatusOrphaned, ExecutionStatusCompletedSuccess, true},
+
+		{ExecutionStatusCompletedSuccess, ExecutionStatusPending, false},
+		{ExecutionStatusCompletedError, ExecutionStatusRunning, false},
+		{ExecutionStatusPending, ExecutionStatusRunning, false},
+		{ExecutionStatusRunning, ExecutionStatusPending, false},
+		{ExecutionStatusOrphaned, ExecutionStatusPending, false},
+		{ExecutionStatusPending, ExecutionStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestExecutionStatus_EveryStatusHasTransitions(t *testing.T) {
+	// No status is a dead end: finished executions can still be orphaned
+	for _, status := range AllExecutionStatusValues() {
+		assert.NotEmpty(t, executionTransitions[status], status)
+	}
+}
+
+func TestTransition_Validate(t *testing.T) {
+	id := uuid.New()
+
+	created := Transition{ExecutionID: id, To: ExecutionStatusPending, Actor: ExecutionActorUser, Reason: "run requested"}
+	assert.NoError(t, created.Validate())
+
+	createdRunning := Transition{ExecutionID: id, To: ExecutionStatusRunning, Actor: ExecutionActorUser}
+	assert.ErrorIs(t, createdRunning.Validate(), ErrIllegalTransition)
+
+	rewound := Transition{ExecutionID: id, From: ExecutionStatusCompletedSuccess, To: ExecutionStatusPending, Actor: ExecutionActorScheduler}
+	assert.ErrorIs(t, rewound.Validate(), ErrIllegalTransition)
+
+	unknownActor := Transition{ExecutionID: id, From: ExecutionStatusPending, To: ExecutionStatusEvaluating, Actor: "cron"}
+	assert.Error(t, unknownActor.Validate())
+}
+
+func TestTransition_Event(t *testing.T) {
+	id := uuid.New()
+
+	event := Transition{ExecutionID: id, To: ExecutionStatusPending, Actor: ExecutionActorUser, Reason: "run requested"}.Event()
+	assert.Equal(t, id, event.ExecutionID)
+	assert.False(t, event.FromStatus.Valid)
+	assert.Equal(t, ExecutionStatusPending, event.ToStatus)
+	assert.Equal(t, "run requested", event.Reason)
+
+	event = Transition{ExecutionID: id, From: ExecutionStatusRunning, To: ExecutionStatusCompletedError, Actor: ExecutionActorExecutor}.Event()
+	assert.True(t, event.FromStatus.Valid)
+	assert.Equal(t, ExecutionStatusRunning, event.FromStatus.ExecutionStatus)
+}
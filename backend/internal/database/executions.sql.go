@@ -7,27 +7,128 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimPendingExecutions = `-- name: ClaimPendingExecutions :many
+WITH claimed AS (
+    UPDATE executions 
+    SET 
+        status = 'evaluating',
+        lease_owner = $1::text,
+        lease_expires_at = now() + ($2::int * interval '1 second')
+    WHERE id IN (
+        SELECT id FROM executions 
+        WHERE status = 'pending'
+        ORDER BY created_at ASC
+        LIMIT $3
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'pending', 'evaluating', $4::execution_actor, $5::text FROM claimed
+)
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM claimed
+`
+
+type ClaimPendingExecutionsParams struct {
+	LeaseOwner   string         `json:"lease_owner"`
+	LeaseSeconds int32          `json:"lease_seconds"`
+	BatchSize    int32          `json:"batch_size"`
+	Actor        ExecutionActor `json:"actor"`
+	Reason       string         `json:"reason"`
+}
+
+// Leases pending executions to lease_owner and records the transitions
+func (q *Queries) ClaimPendingExecutions(ctx context.Context, arg ClaimPendingExecutionsParams) ([]Execution, error) {
+	rows, err := q.db.Query(ctx, claimPendingExecutions,
+		arg.LeaseOwner,
+		arg.LeaseSeconds,
+		arg.BatchSize,
+		arg.Actor,
+		arg.Reason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Execution{}
+	for rows.Next() {
+		var i Execution
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Status,
+			&i.ChosenAt,
+			&i.CloudRegion,
+			&i.VmType,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExitCode,
+			&i.LogUri,
+			&i.CostEstimateUsd,
+			&i.CostActualUsd,
+			&i.CarbonIntensityGKwh,
+			&i.CarbonEmittedKg,
+			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createExecution = `-- name: CreateExecution :one
 INSERT INTO executions (
     job_id,
-    status
+    status,
+    env_vars,
+    delay_tolerance_hours
 ) VALUES (
-    $1, $2
-) RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
+    $1, $2, $3, $4
+) RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
 `
 
 type CreateExecutionParams struct {
-	JobID  uuid.UUID       `json:"job_id"`
-	Status ExecutionStatus `json:"status"`
+	JobID               uuid.UUID       `json:"job_id"`
+	Status              ExecutionStatus `json:"status"`
+	EnvVars             []byte          `json:"env_vars"`
+	DelayToleranceHours *int32          `json:"delay_tolerance_hours"`
 }
 
 func (q *Queries) CreateExecution(ctx context.Context, arg CreateExecutionParams) (Execution, error) {
-	row := q.db.QueryRow(ctx, createExecution, arg.JobID, arg.Status)
+	row := q.db.QueryRow(ctx, createExecution,
+		arg.JobID,
+		arg.Status,
+		arg.EnvVars,
+		arg.DelayToleranceHours,
+	)
 	var i Execution
 	err := row.Scan(
 		&i.ID,
@@ -45,6 +146,22 @@ func (q *Queries) CreateExecution(ctx context.Context, arg CreateExecutionParams
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
@@ -59,8 +176,84 @@ func (q *Queries) DeleteExecution(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getAbandonedRunningExecutions = `-- name: GetAbandonedRunningExecutions :many
+SELECT e.id, e.job_id, e.status, e.chosen_at, e.cloud_region, e.vm_type, e.started_at, e.completed_at, e.exit_code, e.log_uri, e.cost_estimate_usd, e.cost_actual_usd, e.carbon_intensity_g_kwh, e.carbon_emitted_kg, e.created_at, e.lease_owner, e.lease_expires_at, e.planned_start_at, e.env_vars, e.delay_tolerance_hours, e.scheduled_for, e.instance_id, e.teardown_attempts, e.next_teardown_at, e.last_teardown_error, e.energy_kwh, e.baseline_region, e.baseline_vm_type, e.baseline_start_at, e.baseline_cost_usd, e.baseline_carbon_kg FROM executions e
+JOIN jobs j ON j.id = e.job_id
+WHERE e.status = 'running'
+    AND (
+        e.lease_expires_at < $1
+        OR (
+            e.lease_expires_at IS NULL
+            AND j.max_runtime_minutes IS NOT NULL
+            AND e.started_at + make_interval(mins => j.max_runtime_minutes) < $1
+        )
+    )
+ORDER BY e.started_at ASC
+LIMIT $2
+`
+
+type GetAbandonedRunningExecutionsParams struct {
+	Cutoff    null.Time `json:"cutoff"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+// Lists running executions whose worker stopped renewing its lease before cutoff.
+// Executions started without a lease count once their job's max runtime had
+// elapsed by cutoff.
+func (q *Queries) GetAbandonedRunningExecutions(ctx context.Context, arg GetAbandonedRunningExecutionsParams) ([]Execution, error) {
+	rows, err := q.db.Query(ctx, getAbandonedRunningExecutions, arg.Cutoff, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Execution{}
+	for rows.Next() {
+		var i Execution
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Status,
+			&i.ChosenAt,
+			&i.CloudRegion,
+			&i.VmType,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExitCode,
+			&i.LogUri,
+			&i.CostEstimateUsd,
+			&i.CostActualUsd,
+			&i.CarbonIntensityGKwh,
+			&i.CarbonEmittedKg,
+			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getExecution = `-- name: GetExecution :one
-SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at FROM executions 
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
 WHERE id = $1
 `
 
@@ -83,6 +276,22 @@ func (q *Queries) GetExecution(ctx context.Context, id uuid.UUID) (Execution, er
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
@@ -94,8 +303,9 @@ SELECT
     COUNT(*) FILTER (WHERE status = 'completed_error') as failed_executions,
     COUNT(*) FILTER (WHERE status = 'pending') as pending_executions,
     COUNT(*) FILTER (WHERE status = 'running') as running_executions,
-    AVG(cost_actual_usd) as avg_cost,
-    SUM(carbon_emitted_kg) as total_carbon
+    COALESCE(AVG(cost_actual_usd), 0)::float8 as avg_cost,
+    COALESCE(SUM(cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(carbon_emitted_kg), 0)::float8 as total_carbon
 FROM executions 
 WHERE job_id = $1
 `
@@ -107,7 +317,8 @@ type GetExecutionStatsRow struct {
 	PendingExecutions    int64   `json:"pending_executions"`
 	RunningExecutions    int64   `json:"running_executions"`
 	AvgCost              float64 `json:"avg_cost"`
-	TotalCarbon          int64   `json:"total_carbon"`
+	TotalCost            float64 `json:"total_cost"`
+	TotalCarbon          float64 `json:"total_carbon"`
 }
 
 func (q *Queries) GetExecutionStats(ctx context.Context, jobID uuid.UUID) (GetExecutionStatsRow, error) {
@@ -120,13 +331,14 @@ func (q *Queries) GetExecutionStats(ctx context.Context, jobID uuid.UUID) (GetEx
 		&i.PendingExecutions,
 		&i.RunningExecutions,
 		&i.AvgCost,
+		&i.TotalCost,
 		&i.TotalCarbon,
 	)
 	return i, err
 }
 
 const getExecutionsByJobID = `-- name: GetExecutionsByJobID :many
-SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at FROM executions 
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
 WHERE job_id = $1
 ORDER BY created_at DESC
 `
@@ -156,6 +368,22 @@ func (q *Queries) GetExecutionsByJobID(ctx context.Context, jobID uuid.UUID) ([]
 			&i.CarbonIntensityGKwh,
 			&i.CarbonEmittedKg,
 			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
 		); err != nil {
 			return nil, err
 		}
@@ -168,7 +396,7 @@ func (q *Queries) GetExecutionsByJobID(ctx context.Context, jobID uuid.UUID) ([]
 }
 
 const getExecutionsByJobIDWithLimit = `-- name: GetExecutionsByJobIDWithLimit :many
-SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at FROM executions 
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
 WHERE job_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -205,6 +433,22 @@ func (q *Queries) GetExecutionsByJobIDWithLimit(ctx context.Context, arg GetExec
 			&i.CarbonIntensityGKwh,
 			&i.CarbonEmittedKg,
 			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
 		); err != nil {
 			return nil, err
 		}
@@ -217,7 +461,7 @@ func (q *Queries) GetExecutionsByJobIDWithLimit(ctx context.Context, arg GetExec
 }
 
 const getExecutionsByStatus = `-- name: GetExecutionsByStatus :many
-SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at FROM executions 
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
 WHERE status = $1
 ORDER BY created_at DESC
 `
@@ -247,6 +491,87 @@ func (q *Queries) GetExecutionsByStatus(ctx context.Context, status ExecutionSta
 			&i.CarbonIntensityGKwh,
 			&i.CarbonEmittedKg,
 			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrphanedExecutionsDue = `-- name: GetOrphanedExecutionsDue :many
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
+WHERE status = 'orphaned'
+    AND (next_teardown_at IS NULL OR next_teardown_at <= $1)
+ORDER BY next_teardown_at ASC NULLS FIRST
+LIMIT $2
+`
+
+type GetOrphanedExecutionsDueParams struct {
+	DueBefore null.Time `json:"due_before"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+func (q *Queries) GetOrphanedExecutionsDue(ctx context.Context, arg GetOrphanedExecutionsDueParams) ([]Execution, error) {
+	rows, err := q.db.Query(ctx, getOrphanedExecutionsDue, arg.DueBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Execution{}
+	for rows.Next() {
+		var i Execution
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Status,
+			&i.ChosenAt,
+			&i.CloudRegion,
+			&i.VmType,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExitCode,
+			&i.LogUri,
+			&i.CostEstimateUsd,
+			&i.CostActualUsd,
+			&i.CarbonIntensityGKwh,
+			&i.CarbonEmittedKg,
+			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
 		); err != nil {
 			return nil, err
 		}
@@ -259,7 +584,7 @@ func (q *Queries) GetExecutionsByStatus(ctx context.Context, status ExecutionSta
 }
 
 const getPendingExecutions = `-- name: GetPendingExecutions :many
-SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at FROM executions 
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM executions 
 WHERE status = 'pending'
 ORDER BY created_at ASC
 `
@@ -289,6 +614,22 @@ func (q *Queries) GetPendingExecutions(ctx context.Context) ([]Execution, error)
 			&i.CarbonIntensityGKwh,
 			&i.CarbonEmittedKg,
 			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
 		); err != nil {
 			return nil, err
 		}
@@ -303,71 +644,454 @@ func (q *Queries) GetPendingExecutions(ctx context.Context) ([]Execution, error)
 const getUserExecutionStats = `-- name: GetUserExecutionStats :one
 SELECT 
     COUNT(*) as total_executions,
-    COUNT(*) FILTER (WHERE status = 'completed_success') as successful_executions,
-    COUNT(*) FILTER (WHERE status = 'completed_error') as failed_executions,
-    AVG(cost_actual_usd) as avg_cost,
-    SUM(cost_actual_usd) as total_cost,
-    SUM(carbon_emitted_kg) as total_carbon
+    COUNT(*) FILTER (WHERE e.status = 'pending') as pending_executions,
+    COUNT(*) FILTER (WHERE e.status = 'evaluating') as evaluating_executions,
+    COUNT(*) FILTER (WHERE e.status = 'running') as running_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_success') as successful_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_error') as failed_executions,
+    COUNT(*) FILTER (WHERE e.status = 'orphaned') as orphaned_executions,
+    COALESCE(AVG(e.cost_actual_usd), 0)::float8 as avg_cost,
+    COALESCE(SUM(e.cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(e.carbon_emitted_kg), 0)::float8 as total_carbon,
+    COALESCE(SUM(e.baseline_cost_usd - e.cost_actual_usd), 0)::float8 as cost_savings,
+    COALESCE(SUM(e.baseline_carbon_kg - e.carbon_emitted_kg), 0)::float8 as carbon_savings,
+    COALESCE(AVG(EXTRACT(EPOCH FROM (e.planned_start_at - COALESCE(e.scheduled_for, e.created_at)))), 0)::float8 as avg_deferral_seconds
 FROM executions e
 JOIN jobs j ON e.job_id = j.id
 WHERE j.owner_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
 `
 
+type GetUserExecutionStatsParams struct {
+	OwnerID     uuid.UUID `json:"owner_id"`
+	CreatedFrom time.Time `json:"created_from"`
+	CreatedTo   time.Time `json:"created_to"`
+}
+
 type GetUserExecutionStatsRow struct {
 	TotalExecutions      int64   `json:"total_executions"`
+	PendingExecutions    int64   `json:"pending_executions"`
+	EvaluatingExecutions int64   `json:"evaluating_executions"`
+	RunningExecutions    int64   `json:"running_executions"`
 	SuccessfulExecutions int64   `json:"successful_executions"`
 	FailedExecutions     int64   `json:"failed_executions"`
+	OrphanedExecutions   int64   `json:"orphaned_executions"`
 	AvgCost              float64 `json:"avg_cost"`
-	TotalCost            int64   `json:"total_cost"`
-	TotalCarbon          int64   `json:"total_carbon"`
+	TotalCost            float64 `json:"total_cost"`
+	TotalCarbon          float64 `json:"total_carbon"`
+	CostSavings          float64 `json:"cost_savings"`
+	CarbonSavings        float64 `json:"carbon_savings"`
+	AvgDeferralSeconds   float64 `json:"avg_deferral_seconds"`
 }
 
-func (q *Queries) GetUserExecutionStats(ctx context.Context, ownerID uuid.UUID) (GetUserExecutionStatsRow, error) {
-	row := q.db.QueryRow(ctx, getUserExecutionStats, ownerID)
+// Executions submitted in [created_from, created_to). Savings only count executions
+// with both a baseline and an actual figure; deferral is planned start minus submission.
+func (q *Queries) GetUserExecutionStats(ctx context.Context, arg GetUserExecutionStatsParams) (GetUserExecutionStatsRow, error) {
+	row := q.db.QueryRow(ctx, getUserExecutionStats, arg.OwnerID, arg.CreatedFrom, arg.CreatedTo)
 	var i GetUserExecutionStatsRow
 	err := row.Scan(
 		&i.TotalExecutions,
+		&i.PendingExecutions,
+		&i.EvaluatingExecutions,
+		&i.RunningExecutions,
 		&i.SuccessfulExecutions,
 		&i.FailedExecutions,
+		&i.OrphanedExecutions,
 		&i.AvgCost,
 		&i.TotalCost,
 		&i.TotalCarbon,
+		&i.CostSavings,
+		&i.CarbonSavings,
+		&i.AvgDeferralSeconds,
 	)
 	return i, err
 }
 
-const updateExecutionComplete = `-- name: UpdateExecutionComplete :one
+const getUserExecutionStatsByBucket = `-- name: GetUserExecutionStatsByBucket :many
+SELECT 
+    date_trunc($1::text, e.created_at, 'UTC')::timestamptz as bucket_start,
+    COUNT(*) as total_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_success') as successful_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_error') as failed_executions,
+    COALESCE(SUM(e.cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(e.carbon_emitted_kg), 0)::float8 as total_carbon,
+    COALESCE(SUM(e.baseline_cost_usd - e.cost_actual_usd), 0)::float8 as cost_savings,
+    COALESCE(SUM(e.baseline_carbon_kg - e.carbon_emitted_kg), 0)::float8 as carbon_savings,
+    COALESCE(AVG(EXTRACT(EPOCH FROM (e.planned_start_at - COALESCE(e.scheduled_for, e.created_at)))), 0)::float8 as avg_deferral_seconds
+FROM executions e
+JOIN jobs j ON e.job_id = j.id
+WHERE j.owner_id = $2
+  AND e.created_at >= $3
+  AND e.created_at < $4
+GROUP BY bucket_start
+ORDER BY bucket_start
+`
+
+type GetUserExecutionStatsByBucketParams struct {
+	Bucket      string    `json:"bucket"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	CreatedFrom time.Time `json:"created_from"`
+	CreatedTo   time.Time `json:"created_to"`
+}
+
+type GetUserExecutionStatsByBucketRow struct {
+	BucketStart          pgtype.Timestamptz `json:"bucket_start"`
+	TotalExecutions      int64              `json:"total_executions"`
+	SuccessfulExecutions int64              `json:"successful_executions"`
+	FailedExecutions     int64              `json:"failed_executions"`
+	TotalCost            float64            `json:"total_cost"`
+	TotalCarbon          float64            `json:"total_carbon"`
+	CostSavings          float64            `json:"cost_savings"`
+	CarbonSavings        float64            `json:"carbon_savings"`
+	AvgDeferralSeconds   float64            `json:"avg_deferral_seconds"`
+}
+
+// GetUserExecutionStats per UTC day, week or month of submission, for trend charts
+func (q *Queries) GetUserExecutionStatsByBucket(ctx context.Context, arg GetUserExecutionStatsByBucketParams) ([]GetUserExecutionStatsByBucketRow, error) {
+	rows, err := q.db.Query(ctx, getUserExecutionStatsByBucket,
+		arg.Bucket,
+		arg.OwnerID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUserExecutionStatsByBucketRow{}
+	for rows.Next() {
+		var i GetUserExecutionStatsByBucketRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.TotalExecutions,
+			&i.SuccessfulExecutions,
+			&i.FailedExecutions,
+			&i.TotalCost,
+			&i.TotalCarbon,
+			&i.CostSavings,
+			&i.CarbonSavings,
+			&i.AvgDeferralSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserSavings = `-- name: GetUserSavings :one
+SELECT 
+    COUNT(*) FILTER (WHERE e.baseline_cost_usd IS NOT NULL OR e.baseline_carbon_kg IS NOT NULL) as executions,
+    COALESCE(SUM(e.baseline_cost_usd) FILTER (WHERE e.cost_actual_usd IS NOT NULL), 0)::float8 as baseline_cost,
+    COALESCE(SUM(e.cost_actual_usd) FILTER (WHERE e.baseline_cost_usd IS NOT NULL), 0)::float8 as actual_cost,
+    COALESCE(SUM(e.baseline_carbon_kg) FILTER (WHERE e.carbon_emitted_kg IS NOT NULL), 0)::float8 as baseline_carbon,
+    COALESCE(SUM(e.carbon_emitted_kg) FILTER (WHERE e.baseline_carbon_kg IS NOT NULL), 0)::float8 as actual_carbon
+FROM executions e
+JOIN jobs j ON e.job_id = j.id
+WHERE j.owner_id = $1
+`
+
+type GetUserSavingsRow struct {
+	Executions     int64   `json:"executions"`
+	BaselineCost   float64 `json:"baseline_cost"`
+	ActualCost     float64 `json:"actual_cost"`
+	BaselineCarbon float64 `json:"baseline_carbon"`
+	ActualCarbon   float64 `json:"actual_carbon"`
+}
+
+// Totals compare only executions with both a baseline and an actual figure
+func (q *Queries) GetUserSavings(ctx context.Context, ownerID uuid.UUID) (GetUserSavingsRow, error) {
+	row := q.db.QueryRow(ctx, getUserSavings, ownerID)
+	var i GetUserSavingsRow
+	err := row.Scan(
+		&i.Executions,
+		&i.BaselineCost,
+		&i.ActualCost,
+		&i.BaselineCarbon,
+		&i.ActualCarbon,
+	)
+	return i, err
+}
+
+const leaseDueExecutions = `-- name: LeaseDueExecutions :many
+UPDATE executions 
+SET 
+    lease_owner = $1::text,
+    lease_expires_at = now() + ($2::int * interval '1 second')
+WHERE id IN (
+    SELECT id FROM executions 
+    WHERE status = 'evaluating'
+      AND lease_owner IS NULL
+      AND planned_start_at <= now()
+    ORDER BY planned_start_at ASC
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
+`
+
+type LeaseDueExecutionsParams struct {
+	LeaseOwner   string `json:"lease_owner"`
+	LeaseSeconds int32  `json:"lease_seconds"`
+	BatchSize    int32  `json:"batch_size"`
+}
+
+func (q *Queries) LeaseDueExecutions(ctx context.Context, arg LeaseDueExecutionsParams) ([]Execution, error) {
+	rows, err := q.db.Query(ctx, leaseDueExecutions, arg.LeaseOwner, arg.LeaseSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Execution{}
+	for rows.Next() {
+		var i Execution
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Status,
+			&i.ChosenAt,
+			&i.CloudRegion,
+			&i.VmType,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.ExitCode,
+			&i.LogUri,
+			&i.CostEstimateUsd,
+			&i.CostActualUsd,
+			&i.CarbonIntensityGKwh,
+			&i.CarbonEmittedKg,
+			&i.CreatedAt,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.PlannedStartAt,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.ScheduledFor,
+			&i.InstanceID,
+			&i.TeardownAttempts,
+			&i.NextTeardownAt,
+			&i.LastTeardownError,
+			&i.EnergyKwh,
+			&i.BaselineRegion,
+			&i.BaselineVmType,
+			&i.BaselineStartAt,
+			&i.BaselineCostUsd,
+			&i.BaselineCarbonKg,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markExecutionOrphaned = `-- name: MarkExecutionOrphaned :execrows
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = 'orphaned',
+        instance_id = $1,
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        next_teardown_at = $2,
+        last_teardown_error = $3
+    WHERE id = $4 AND status = $5
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, $5, 'orphaned', $6::execution_actor, $7::text FROM updated
+`
+
+type MarkExecutionOrphanedParams struct {
+	InstanceID        *string         `json:"instance_id"`
+	NextTeardownAt    null.Time       `json:"next_teardown_at"`
+	LastTeardownError *string         `json:"last_teardown_error"`
+	ID                uuid.UUID       `json:"id"`
+	FromStatus        ExecutionStatus `json:"from_status"`
+	Actor             ExecutionActor  `json:"actor"`
+	Reason            string          `json:"reason"`
+}
+
+// Marks an execution still in from_status orphaned and records the transition
+func (q *Queries) MarkExecutionOrphaned(ctx context.Context, arg MarkExecutionOrphanedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markExecutionOrphaned,
+		arg.InstanceID,
+		arg.NextTeardownAt,
+		arg.LastTeardownError,
+		arg.ID,
+		arg.FromStatus,
+		arg.Actor,
+		arg.Reason,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordTeardownFailure = `-- name: RecordTeardownFailure :exec
 UPDATE executions 
 SET 
-    status = $2,
-    completed_at = $3,
-    exit_code = $4,
-    log_uri = $5,
-    cost_actual_usd = $6,
-    carbon_emitted_kg = $7
+    teardown_attempts = teardown_attempts + 1,
+    next_teardown_at = $2,
+    last_teardown_error = $3
 WHERE id = $1
-RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
+`
+
+type RecordTeardownFailureParams struct {
+	ID                uuid.UUID `json:"id"`
+	NextTeardownAt    null.Time `json:"next_teardown_at"`
+	LastTeardownError *string   `json:"last_teardown_error"`
+}
+
+func (q *Queries) RecordTeardownFailure(ctx context.Context, arg RecordTeardownFailureParams) error {
+	_, err := q.db.Exec(ctx, recordTeardownFailure, arg.ID, arg.NextTeardownAt, arg.LastTeardownError)
+	return err
+}
+
+const releaseExpiredLeases = `-- name: ReleaseExpiredLeases :execrows
+WITH released AS (
+    UPDATE executions 
+    SET 
+        status = 'pending',
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE status = 'evaluating'
+      AND lease_owner IS NOT NULL
+      AND lease_expires_at < now()
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, 'evaluating', 'pending', $1::execution_actor, 'lease expired before the worker finished' FROM released
+`
+
+// Returns executions whose lease expired to pending and records the transitions
+func (q *Queries) ReleaseExpiredLeases(ctx context.Context, actor ExecutionActor) (int64, error) {
+	result, err := q.db.Exec(ctx, releaseExpiredLeases, actor)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const renewExecutionLease = `-- name: RenewExecutionLease :execrows
+UPDATE executions 
+SET lease_expires_at = now() + ($1::int * interval '1 second')
+WHERE id = $2
+  AND lease_owner = $3::text
+  AND (status = 'running' OR (status = 'evaluating' AND lease_expires_at > now()))
+`
+
+type RenewExecutionLeaseParams struct {
+	LeaseSeconds int32     `json:"lease_seconds"`
+	ID           uuid.UUID `json:"id"`
+	LeaseOwner   string    `json:"lease_owner"`
+}
+
+// Extends the lease lease_owner holds on an execution it is starting or running.
+// An expired lease on an evaluating execution may already have been released.
+func (q *Queries) RenewExecutionLease(ctx context.Context, arg RenewExecutionLeaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, renewExecutionLease, arg.LeaseSeconds, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resolveOrphanedExecution = `-- name: ResolveOrphanedExecution :execrows
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = CASE WHEN exit_code = 0 THEN 'completed_success'::execution_status ELSE 'completed_error'::execution_status END,
+        completed_at = COALESCE(completed_at, now()),
+        next_teardown_at = NULL,
+        last_teardown_error = NULL
+    WHERE id = $1 AND status = 'orphaned'
+    RETURNING id, status
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, 'orphaned', status, $2::execution_actor, $3::text FROM updated
+`
+
+type ResolveOrphanedExecutionParams struct {
+	ID     uuid.UUID      `json:"id"`
+	Actor  ExecutionActor `json:"actor"`
+	Reason string         `json:"reason"`
+}
+
+// Restores the outcome recorded before teardown failed and records the transition
+func (q *Queries) ResolveOrphanedExecution(ctx context.Context, arg ResolveOrphanedExecutionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolveOrphanedExecution, arg.ID, arg.Actor, arg.Reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateExecutionComplete = `-- name: UpdateExecutionComplete :one
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = $1,
+        completed_at = $2,
+        exit_code = $3,
+        log_uri = $4,
+        cost_actual_usd = $5,
+        carbon_emitted_kg = $6,
+        energy_kwh = $7,
+        baseline_cost_usd = $8,
+        baseline_carbon_kg = $9,
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE id = $10 AND status = $11
+    RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, $11, status, $12::execution_actor, $13::text FROM updated
+)
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM updated
 `
 
 type UpdateExecutionCompleteParams struct {
-	ID              uuid.UUID       `json:"id"`
-	Status          ExecutionStatus `json:"status"`
-	CompletedAt     null.Time       `json:"completed_at"`
-	ExitCode        null.Int        `json:"exit_code"`
-	LogUri          *string         `json:"log_uri"`
-	CostActualUsd   null.Float      `json:"cost_actual_usd"`
-	CarbonEmittedKg null.Float      `json:"carbon_emitted_kg"`
+	Status           ExecutionStatus `json:"status"`
+	CompletedAt      null.Time       `json:"completed_at"`
+	ExitCode         null.Int        `json:"exit_code"`
+	LogUri           *string         `json:"log_uri"`
+	CostActualUsd    null.Float      `json:"cost_actual_usd"`
+	CarbonEmittedKg  null.Float      `json:"carbon_emitted_kg"`
+	EnergyKwh        null.Float      `json:"energy_kwh"`
+	BaselineCostUsd  null.Float      `json:"baseline_cost_usd"`
+	BaselineCarbonKg null.Float      `json:"baseline_carbon_kg"`
+	ID               uuid.UUID       `json:"id"`
+	FromStatus       ExecutionStatus `json:"from_status"`
+	Actor            ExecutionActor  `json:"actor"`
+	Reason           string          `json:"reason"`
 }
 
+// Records the outcome of an execution still in from_status and the transition
 func (q *Queries) UpdateExecutionComplete(ctx context.Context, arg UpdateExecutionCompleteParams) (Execution, error) {
 	row := q.db.QueryRow(ctx, updateExecutionComplete,
-		arg.ID,
 		arg.Status,
 		arg.CompletedAt,
 		arg.ExitCode,
 		arg.LogUri,
 		arg.CostActualUsd,
 		arg.CarbonEmittedKg,
+		arg.EnergyKwh,
+		arg.BaselineCostUsd,
+		arg.BaselineCarbonKg,
+		arg.ID,
+		arg.FromStatus,
+		arg.Actor,
+		arg.Reason,
 	)
 	var i Execution
 	err := row.Scan(
@@ -386,6 +1110,22 @@ func (q *Queries) UpdateExecutionComplete(ctx context.Context, arg UpdateExecuti
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
@@ -396,7 +1136,7 @@ SET
     cost_estimate_usd = $2,
     carbon_intensity_g_kwh = $3
 WHERE id = $1
-RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
+RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
 `
 
 type UpdateExecutionCostEstimateParams struct {
@@ -424,36 +1164,95 @@ func (q *Queries) UpdateExecutionCostEstimate(ctx context.Context, arg UpdateExe
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
 
-const updateExecutionScheduling = `-- name: UpdateExecutionScheduling :one
+const updateExecutionInstance = `-- name: UpdateExecutionInstance :exec
 UPDATE executions 
-SET 
-    status = $2,
-    chosen_at = $3,
-    cloud_region = $4,
-    vm_type = $5
+SET instance_id = $2
 WHERE id = $1
-RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
+`
+
+type UpdateExecutionInstanceParams struct {
+	ID         uuid.UUID `json:"id"`
+	InstanceID *string   `json:"instance_id"`
+}
+
+func (q *Queries) UpdateExecutionInstance(ctx context.Context, arg UpdateExecutionInstanceParams) error {
+	_, err := q.db.Exec(ctx, updateExecutionInstance, arg.ID, arg.InstanceID)
+	return err
+}
+
+const updateExecutionScheduling = `-- name: UpdateExecutionScheduling :one
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = $1,
+        chosen_at = $2,
+        cloud_region = $3,
+        vm_type = $4,
+        planned_start_at = $5,
+        baseline_region = $6,
+        baseline_vm_type = $7,
+        baseline_start_at = $8,
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE id = $9 AND status = 'evaluating'
+    RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'evaluating', status, $10::execution_actor, $11::text FROM updated
+)
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM updated
 `
 
 type UpdateExecutionSchedulingParams struct {
-	ID          uuid.UUID       `json:"id"`
-	Status      ExecutionStatus `json:"status"`
-	ChosenAt    null.Time       `json:"chosen_at"`
-	CloudRegion *string         `json:"cloud_region"`
-	VmType      *string         `json:"vm_type"`
+	Status          ExecutionStatus `json:"status"`
+	ChosenAt        null.Time       `json:"chosen_at"`
+	CloudRegion     *string         `json:"cloud_region"`
+	VmType          *string         `json:"vm_type"`
+	PlannedStartAt  null.Time       `json:"planned_start_at"`
+	BaselineRegion  *string         `json:"baseline_region"`
+	BaselineVmType  *string         `json:"baseline_vm_type"`
+	BaselineStartAt null.Time       `json:"baseline_start_at"`
+	ID              uuid.UUID       `json:"id"`
+	Actor           ExecutionActor  `json:"actor"`
+	Reason          string          `json:"reason"`
 }
 
+// Records the placement of an evaluating execution, releases its lease and
+// records the transition
 func (q *Queries) UpdateExecutionScheduling(ctx context.Context, arg UpdateExecutionSchedulingParams) (Execution, error) {
 	row := q.db.QueryRow(ctx, updateExecutionScheduling,
-		arg.ID,
 		arg.Status,
 		arg.ChosenAt,
 		arg.CloudRegion,
 		arg.VmType,
+		arg.PlannedStartAt,
+		arg.BaselineRegion,
+		arg.BaselineVmType,
+		arg.BaselineStartAt,
+		arg.ID,
+		arg.Actor,
+		arg.Reason,
 	)
 	var i Execution
 	err := row.Scan(
@@ -472,61 +1271,64 @@ func (q *Queries) UpdateExecutionScheduling(ctx context.Context, arg UpdateExecu
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
 
 const updateExecutionStart = `-- name: UpdateExecutionStart :one
-UPDATE executions 
-SET 
-    status = 'running',
-    started_at = $2
-WHERE id = $1
-RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = 'running',
+        started_at = $1
+    WHERE id = $2
+      AND status = 'evaluating'
+      AND lease_owner = $3::text
+      AND lease_expires_at > now()
+    RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'evaluating', 'running', $4::execution_actor, $5::text FROM updated
+)
+SELECT id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at, lease_owner, lease_expires_at, planned_start_at, env_vars, delay_tolerance_hours, scheduled_for, instance_id, teardown_attempts, next_teardown_at, last_teardown_error, energy_kwh, baseline_region, baseline_vm_type, baseline_start_at, baseline_cost_usd, baseline_carbon_kg FROM updated
 `
 
 type UpdateExecutionStartParams struct {
-	ID        uuid.UUID `json:"id"`
-	StartedAt null.Time `json:"started_at"`
+	StartedAt null.Time      `json:"started_at"`
+	ID        uuid.UUID      `json:"id"`
+	WorkerID  string         `json:"worker_id"`
+	Actor     ExecutionActor `json:"actor"`
+	Reason    string         `json:"reason"`
 }
 
+// Marks an evaluating execution running and records the transition, provided
+// worker_id still holds its lease. The worker keeps renewing the lease while the
+// execution runs.
 func (q *Queries) UpdateExecutionStart(ctx context.Context, arg UpdateExecutionStartParams) (Execution, error) {
-	row := q.db.QueryRow(ctx, updateExecutionStart, arg.ID, arg.StartedAt)
-	var i Execution
-	err := row.Scan(
-		&i.ID,
-		&i.JobID,
-		&i.Status,
-		&i.ChosenAt,
-		&i.CloudRegion,
-		&i.VmType,
-		&i.StartedAt,
-		&i.CompletedAt,
-		&i.ExitCode,
-		&i.LogUri,
-		&i.CostEstimateUsd,
-		&i.CostActualUsd,
-		&i.CarbonIntensityGKwh,
-		&i.CarbonEmittedKg,
-		&i.CreatedAt,
+	row := q.db.QueryRow(ctx, updateExecutionStart,
+		arg.StartedAt,
+		arg.ID,
+		arg.WorkerID,
+		arg.Actor,
+		arg.Reason,
 	)
-	return i, err
-}
-
-const updateExecutionStatus = `-- name: UpdateExecutionStatus :one
-UPDATE executions 
-SET status = $2
-WHERE id = $1
-RETURNING id, job_id, status, chosen_at, cloud_region, vm_type, started_at, completed_at, exit_code, log_uri, cost_estimate_usd, cost_actual_usd, carbon_intensity_g_kwh, carbon_emitted_kg, created_at
-`
-
-type UpdateExecutionStatusParams struct {
-	ID     uuid.UUID       `json:"id"`
-	Status ExecutionStatus `json:"status"`
-}
-
-func (q *Queries) UpdateExecutionStatus(ctx context.Context, arg UpdateExecutionStatusParams) (Execution, error) {
-	row := q.db.QueryRow(ctx, updateExecutionStatus, arg.ID, arg.Status)
 	var i Execution
 	err := row.Scan(
 		&i.ID,
@@ -544,6 +1346,22 @@ func (q *Queries) UpdateExecutionStatus(ctx context.Context, arg UpdateExecution
 		&i.CarbonIntensityGKwh,
 		&i.CarbonEmittedKg,
 		&i.CreatedAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.PlannedStartAt,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.ScheduledFor,
+		&i.InstanceID,
+		&i.TeardownAttempts,
+		&i.NextTeardownAt,
+		&i.LastTeardownError,
+		&i.EnergyKwh,
+		&i.BaselineRegion,
+		&i.BaselineVmType,
+		&i.BaselineStartAt,
+		&i.BaselineCostUsd,
+		&i.BaselineCarbonKg,
 	)
 	return i, err
 }
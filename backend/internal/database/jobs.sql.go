@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
 )
 
 const createJob = `-- name: CreateJob :one
@@ -17,17 +18,33 @@ INSERT INTO jobs (
     owner_id,
     image_uri,
     env_vars,
-    delay_tolerance_hours
+    delay_tolerance_hours,
+    cron_schedule,
+    timezone,
+    next_run_at,
+    cpu_cores,
+    memory_mb,
+    disk_gb,
+    expected_runtime_minutes,
+    max_runtime_minutes
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes
 `
 
 type CreateJobParams struct {
-	OwnerID             uuid.UUID `json:"owner_id"`
-	ImageUri            string    `json:"image_uri"`
-	EnvVars             []byte    `json:"env_vars"`
-	DelayToleranceHours int32     `json:"delay_tolerance_hours"`
+	OwnerID                uuid.UUID `json:"owner_id"`
+	ImageUri               string    `json:"image_uri"`
+	EnvVars                []byte    `json:"env_vars"`
+	DelayToleranceHours    int32     `json:"delay_tolerance_hours"`
+	CronSchedule           *string   `json:"cron_schedule"`
+	Timezone               string    `json:"timezone"`
+	NextRunAt              null.Time `json:"next_run_at"`
+	CpuCores               int32     `json:"cpu_cores"`
+	MemoryMb               int32     `json:"memory_mb"`
+	DiskGb                 int32     `json:"disk_gb"`
+	ExpectedRuntimeMinutes *int32    `json:"expected_runtime_minutes"`
+	MaxRuntimeMinutes      *int32    `json:"max_runtime_minutes"`
 }
 
 func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (Job, error) {
@@ -36,6 +53,14 @@ func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (Job, erro
 		arg.ImageUri,
 		arg.EnvVars,
 		arg.DelayToleranceHours,
+		arg.CronSchedule,
+		arg.Timezone,
+		arg.NextRunAt,
+		arg.CpuCores,
+		arg.MemoryMb,
+		arg.DiskGb,
+		arg.ExpectedRuntimeMinutes,
+		arg.MaxRuntimeMinutes,
 	)
 	var i Job
 	err := row.Scan(
@@ -46,6 +71,14 @@ func (q *Queries) CreateJob(ctx context.Context, arg CreateJobParams) (Job, erro
 		&i.DelayToleranceHours,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CronSchedule,
+		&i.Timezone,
+		&i.NextRunAt,
+		&i.CpuCores,
+		&i.MemoryMb,
+		&i.DiskGb,
+		&i.ExpectedRuntimeMinutes,
+		&i.MaxRuntimeMinutes,
 	)
 	return i, err
 }
@@ -65,8 +98,91 @@ func (q *Queries) DeleteJob(ctx context.Context, arg DeleteJobParams) error {
 	return err
 }
 
+const fireJobSchedule = `-- name: FireJobSchedule :execrows
+WITH advanced AS (
+    UPDATE jobs 
+    SET next_run_at = $1
+    WHERE id = $2 AND next_run_at = $3
+    RETURNING id
+),
+created AS (
+    INSERT INTO executions (job_id, status, scheduled_for)
+    SELECT id, 'pending', $3 FROM advanced
+    ON CONFLICT DO NOTHING
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, to_status, actor, reason)
+SELECT id, 'pending', 'scheduler', 'recurring schedule fired' FROM created
+`
+
+type FireJobScheduleParams struct {
+	NextRunAt    null.Time `json:"next_run_at"`
+	JobID        uuid.UUID `json:"job_id"`
+	ScheduledFor null.Time `json:"scheduled_for"`
+}
+
+// Advances the schedule, creates the occurrence's execution and records its creation
+// in one statement.
+// Affects no rows if another scheduler replica already fired this occurrence.
+func (q *Queries) FireJobSchedule(ctx context.Context, arg FireJobScheduleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, fireJobSchedule, arg.NextRunAt, arg.JobID, arg.ScheduledFor)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDueJobSchedules = `-- name: GetDueJobSchedules :many
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
+WHERE cron_schedule IS NOT NULL
+    AND next_run_at <= $1
+ORDER BY next_run_at ASC
+LIMIT $2
+`
+
+type GetDueJobSchedulesParams struct {
+	DueBefore null.Time `json:"due_before"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+func (q *Queries) GetDueJobSchedules(ctx context.Context, arg GetDueJobSchedulesParams) ([]Job, error) {
+	rows, err := q.db.Query(ctx, getDueJobSchedules, arg.DueBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Job{}
+	for rows.Next() {
+		var i Job
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.ImageUri,
+			&i.EnvVars,
+			&i.DelayToleranceHours,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CronSchedule,
+			&i.Timezone,
+			&i.NextRunAt,
+			&i.CpuCores,
+			&i.MemoryMb,
+			&i.DiskGb,
+			&i.ExpectedRuntimeMinutes,
+			&i.MaxRuntimeMinutes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getJob = `-- name: GetJob :one
-SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at FROM jobs 
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
 WHERE id = $1 AND owner_id = $2
 `
 
@@ -86,6 +202,42 @@ func (q *Queries) GetJob(ctx context.Context, arg GetJobParams) (Job, error) {
 		&i.DelayToleranceHours,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CronSchedule,
+		&i.Timezone,
+		&i.NextRunAt,
+		&i.CpuCores,
+		&i.MemoryMb,
+		&i.DiskGb,
+		&i.ExpectedRuntimeMinutes,
+		&i.MaxRuntimeMinutes,
+	)
+	return i, err
+}
+
+const getJobByID = `-- name: GetJobByID :one
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
+WHERE id = $1
+`
+
+func (q *Queries) GetJobByID(ctx context.Context, id uuid.UUID) (Job, error) {
+	row := q.db.QueryRow(ctx, getJobByID, id)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.ImageUri,
+		&i.EnvVars,
+		&i.DelayToleranceHours,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CronSchedule,
+		&i.Timezone,
+		&i.NextRunAt,
+		&i.CpuCores,
+		&i.MemoryMb,
+		&i.DiskGb,
+		&i.ExpectedRuntimeMinutes,
+		&i.MaxRuntimeMinutes,
 	)
 	return i, err
 }
@@ -102,8 +254,50 @@ func (q *Queries) GetJobCount(ctx context.Context, ownerID uuid.UUID) (int64, er
 	return count, err
 }
 
+const getJobSchedulingInfo = `-- name: GetJobSchedulingInfo :one
+SELECT 
+    j.owner_id,
+    j.delay_tolerance_hours,
+    j.cpu_cores,
+    j.memory_mb,
+    j.disk_gb,
+    j.expected_runtime_minutes,
+    COALESCE(s.cost_weight, 0.50)::float8 AS cost_weight,
+    COALESCE(s.carbon_weight, 0.50)::float8 AS carbon_weight
+FROM jobs j
+LEFT JOIN user_settings s ON s.user_id = j.owner_id
+WHERE j.id = $1
+`
+
+type GetJobSchedulingInfoRow struct {
+	OwnerID                uuid.UUID `json:"owner_id"`
+	DelayToleranceHours    int32     `json:"delay_tolerance_hours"`
+	CpuCores               int32     `json:"cpu_cores"`
+	MemoryMb               int32     `json:"memory_mb"`
+	DiskGb                 int32     `json:"disk_gb"`
+	ExpectedRuntimeMinutes *int32    `json:"expected_runtime_minutes"`
+	CostWeight             float64   `json:"cost_weight"`
+	CarbonWeight           float64   `json:"carbon_weight"`
+}
+
+func (q *Queries) GetJobSchedulingInfo(ctx context.Context, id uuid.UUID) (GetJobSchedulingInfoRow, error) {
+	row := q.db.QueryRow(ctx, getJobSchedulingInfo, id)
+	var i GetJobSchedulingInfoRow
+	err := row.Scan(
+		&i.OwnerID,
+		&i.DelayToleranceHours,
+		&i.CpuCores,
+		&i.MemoryMb,
+		&i.DiskGb,
+		&i.ExpectedRuntimeMinutes,
+		&i.CostWeight,
+		&i.CarbonWeight,
+	)
+	return i, err
+}
+
 const getJobsByOwner = `-- name: GetJobsByOwner :many
-SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at FROM jobs 
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
 WHERE owner_id = $1
 ORDER BY created_at DESC
 `
@@ -125,6 +319,14 @@ func (q *Queries) GetJobsByOwner(ctx context.Context, ownerID uuid.UUID) ([]Job,
 			&i.DelayToleranceHours,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CronSchedule,
+			&i.Timezone,
+			&i.NextRunAt,
+			&i.CpuCores,
+			&i.MemoryMb,
+			&i.DiskGb,
+			&i.ExpectedRuntimeMinutes,
+			&i.MaxRuntimeMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -137,7 +339,7 @@ func (q *Queries) GetJobsByOwner(ctx context.Context, ownerID uuid.UUID) ([]Job,
 }
 
 const getJobsByOwnerWithLimit = `-- name: GetJobsByOwnerWithLimit :many
-SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at FROM jobs 
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
 WHERE owner_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -166,6 +368,14 @@ func (q *Queries) GetJobsByOwnerWithLimit(ctx context.Context, arg GetJobsByOwne
 			&i.DelayToleranceHours,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CronSchedule,
+			&i.Timezone,
+			&i.NextRunAt,
+			&i.CpuCores,
+			&i.MemoryMb,
+			&i.DiskGb,
+			&i.ExpectedRuntimeMinutes,
+			&i.MaxRuntimeMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -178,7 +388,7 @@ func (q *Queries) GetJobsByOwnerWithLimit(ctx context.Context, arg GetJobsByOwne
 }
 
 const getRecentJobs = `-- name: GetRecentJobs :many
-SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at FROM jobs 
+SELECT id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes FROM jobs 
 WHERE owner_id = $1 
     AND created_at >= $2
 ORDER BY created_at DESC
@@ -206,6 +416,14 @@ func (q *Queries) GetRecentJobs(ctx context.Context, arg GetRecentJobsParams) ([
 			&i.DelayToleranceHours,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CronSchedule,
+			&i.Timezone,
+			&i.NextRunAt,
+			&i.CpuCores,
+			&i.MemoryMb,
+			&i.DiskGb,
+			&i.ExpectedRuntimeMinutes,
+			&i.MaxRuntimeMinutes,
 		); err != nil {
 			return nil, err
 		}
@@ -220,29 +438,57 @@ func (q *Queries) GetRecentJobs(ctx context.Context, arg GetRecentJobsParams) ([
 const updateJob = `-- name: UpdateJob :one
 UPDATE jobs 
 SET 
-    image_uri = $3,
-    env_vars = $4,
-    delay_tolerance_hours = $5,
+    image_uri = $1,
+    env_vars = $2,
+    delay_tolerance_hours = $3,
+    cron_schedule = $4,
+    timezone = $5,
+    next_run_at = CASE WHEN $6::boolean THEN $7 ELSE next_run_at END,
+    cpu_cores = $8,
+    memory_mb = $9,
+    disk_gb = $10,
+    expected_runtime_minutes = $11,
+    max_runtime_minutes = $12,
     updated_at = now()
-WHERE id = $1 AND owner_id = $2
-RETURNING id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at
+WHERE id = $13 AND owner_id = $14
+RETURNING id, owner_id, image_uri, env_vars, delay_tolerance_hours, created_at, updated_at, cron_schedule, timezone, next_run_at, cpu_cores, memory_mb, disk_gb, expected_runtime_minutes, max_runtime_minutes
 `
 
 type UpdateJobParams struct {
-	ID                  uuid.UUID `json:"id"`
-	OwnerID             uuid.UUID `json:"owner_id"`
-	ImageUri            string    `json:"image_uri"`
-	EnvVars             []byte    `json:"env_vars"`
-	DelayToleranceHours int32     `json:"delay_tolerance_hours"`
+	ImageUri               string    `json:"image_uri"`
+	EnvVars                []byte    `json:"env_vars"`
+	DelayToleranceHours    int32     `json:"delay_tolerance_hours"`
+	CronSchedule           *string   `json:"cron_schedule"`
+	Timezone               string    `json:"timezone"`
+	Reschedule             bool      `json:"reschedule"`
+	NextRunAt              null.Time `json:"next_run_at"`
+	CpuCores               int32     `json:"cpu_cores"`
+	MemoryMb               int32     `json:"memory_mb"`
+	DiskGb                 int32     `json:"disk_gb"`
+	ExpectedRuntimeMinutes *int32    `json:"expected_runtime_minutes"`
+	MaxRuntimeMinutes      *int32    `json:"max_runtime_minutes"`
+	ID                     uuid.UUID `json:"id"`
+	OwnerID                uuid.UUID `json:"owner_id"`
 }
 
+// Updates a job definition. next_run_at is only replaced when reschedule is set,
+// so an edit that keeps the schedule neither skips nor shifts its next occurrence.
 func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (Job, error) {
 	row := q.db.QueryRow(ctx, updateJob,
-		arg.ID,
-		arg.OwnerID,
 		arg.ImageUri,
 		arg.EnvVars,
 		arg.DelayToleranceHours,
+		arg.CronSchedule,
+		arg.Timezone,
+		arg.Reschedule,
+		arg.NextRunAt,
+		arg.CpuCores,
+		arg.MemoryMb,
+		arg.DiskGb,
+		arg.ExpectedRuntimeMinutes,
+		arg.MaxRuntimeMinutes,
+		arg.ID,
+		arg.OwnerID,
 	)
 	var i Job
 	err := row.Scan(
@@ -253,6 +499,14 @@ func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (Job, erro
 		&i.DelayToleranceHours,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CronSchedule,
+		&i.Timezone,
+		&i.NextRunAt,
+		&i.CpuCores,
+		&i.MemoryMb,
+		&i.DiskGb,
+		&i.ExpectedRuntimeMinutes,
+		&i.MaxRuntimeMinutes,
 	)
 	return i, err
 }
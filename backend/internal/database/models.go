@@ -15,6 +15,68 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Who caused an execution status transition
+type ExecutionActor string
+
+const (
+	ExecutionActorScheduler ExecutionActor = "scheduler"
+	ExecutionActorExecutor  ExecutionActor = "executor"
+	ExecutionActorUser      ExecutionActor = "user"
+)
+
+func (e *ExecutionActor) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExecutionActor(s)
+	case string:
+		*e = ExecutionActor(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExecutionActor: %T", src)
+	}
+	return nil
+}
+
+type NullExecutionActor struct {
+	ExecutionActor ExecutionActor `json:"execution_actor"`
+	Valid          bool           `json:"valid"` // Valid is true if ExecutionActor is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullExecutionActor) Scan(value interface{}) error {
+	if value == nil {
+		ns.ExecutionActor, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ExecutionActor.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullExecutionActor) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ExecutionActor), nil
+}
+
+func (e ExecutionActor) Valid() bool {
+	switch e {
+	case ExecutionActorScheduler,
+		ExecutionActorExecutor,
+		ExecutionActorUser:
+		return true
+	}
+	return false
+}
+
+func AllExecutionActorValues() []ExecutionActor {
+	return []ExecutionActor{
+		ExecutionActorScheduler,
+		ExecutionActorExecutor,
+		ExecutionActorUser,
+	}
+}
+
 // Possible states of job executions
 type ExecutionStatus string
 
@@ -86,6 +148,150 @@ func AllExecutionStatusValues() []ExecutionStatus {
 	}
 }
 
+// Kind of security-relevant account event
+type SecurityEventType string
+
+const (
+	SecurityEventTypeRefreshTokenReuse SecurityEventType = "refresh_token_reuse"
+)
+
+func (e *SecurityEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SecurityEventType(s)
+	case string:
+		*e = SecurityEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SecurityEventType: %T", src)
+	}
+	return nil
+}
+
+type NullSecurityEventType struct {
+	SecurityEventType SecurityEventType `json:"security_event_type"`
+	Valid             bool              `json:"valid"` // Valid is true if SecurityEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSecurityEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.SecurityEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SecurityEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSecurityEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SecurityEventType), nil
+}
+
+func (e SecurityEventType) Valid() bool {
+	switch e {
+	case SecurityEventTypeRefreshTokenReuse:
+		return true
+	}
+	return false
+}
+
+func AllSecurityEventTypeValues() []SecurityEventType {
+	return []SecurityEventType{
+		SecurityEventTypeRefreshTokenReuse,
+	}
+}
+
+// Progress of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+func (e WebhookDeliveryStatus) Valid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllWebhookDeliveryStatusValues() []WebhookDeliveryStatus {
+	return []WebhookDeliveryStatus{
+		WebhookDeliveryStatusPending,
+		WebhookDeliveryStatusDelivered,
+		WebhookDeliveryStatusFailed,
+	}
+}
+
+// Stores hashed personal API keys
+type ApiKey struct {
+	// Unique API key identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to the user the key acts as
+	UserID uuid.UUID `json:"user_id"`
+	// User-chosen label, such as the pipeline using the key
+	Name string `json:"name"`
+	// Leading characters of the key, shown to identify it
+	Prefix string `json:"prefix"`
+	// SHA-256 hash of the key
+	KeyHash string `json:"key_hash"`
+	// Permissions granted to the key, such as jobs:write
+	Scopes []string `json:"scopes"`
+	// When the key stops working (NULL if it never expires)
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	// When the key last authenticated a request, to the minute
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	// IP address of the client that last used the key
+	LastUsedIp *netip.Addr `json:"last_used_ip"`
+	// When the key was revoked (NULL while active)
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	// When the key was created
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // Execution history and metrics for job runs
 type Execution struct {
 	// Unique execution identifier
@@ -117,6 +323,56 @@ type Execution struct {
 	// Total carbon emissions (kg CO2)
 	CarbonEmittedKg null.Float `json:"carbon_emitted_kg"`
 	CreatedAt       time.Time  `json:"created_at"`
+	// Identifier of the scheduler worker currently holding this execution
+	LeaseOwner *string `json:"lease_owner"`
+	// When the current lease expires and the execution returns to pending
+	LeaseExpiresAt null.Time `json:"lease_expires_at"`
+	// Start time chosen by the scheduler within the delay tolerance window
+	PlannedStartAt null.Time `json:"planned_start_at"`
+	// Environment variables merged over the job env_vars for this run
+	EnvVars []byte `json:"env_vars"`
+	// Delay tolerance for this run, overriding the job value (0-168 hours)
+	DelayToleranceHours *int32 `json:"delay_tolerance_hours"`
+	// Nominal fire time of the schedule occurrence that created this execution
+	ScheduledFor null.Time `json:"scheduled_for"`
+	// Provider-side identifier of the compute instance
+	InstanceID *string `json:"instance_id"`
+	// Failed teardown attempts for an orphaned instance
+	TeardownAttempts int32 `json:"teardown_attempts"`
+	// When the reconciler next retries teardown
+	NextTeardownAt null.Time `json:"next_teardown_at"`
+	// Error from the most recent failed teardown
+	LastTeardownError *string `json:"last_teardown_error"`
+	// Estimated energy drawn by the execution, including data centre overhead (kWh)
+	EnergyKwh null.Float `json:"energy_kwh"`
+	// Default region the execution would have run in without carbon-aware scheduling
+	BaselineRegion *string `json:"baseline_region"`
+	// VM type the execution would have used in the baseline region
+	BaselineVmType *string `json:"baseline_vm_type"`
+	// When the execution would have started without deferral (submission time)
+	BaselineStartAt null.Time `json:"baseline_start_at"`
+	// Cost of the actual runtime in the baseline region and start time
+	BaselineCostUsd null.Float `json:"baseline_cost_usd"`
+	// Carbon emissions of the actual runtime in the baseline region and start time (kg CO2)
+	BaselineCarbonKg null.Float `json:"baseline_carbon_kg"`
+}
+
+// History of execution status transitions
+type ExecutionEvent struct {
+	// Unique event identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to the execution that changed
+	ExecutionID uuid.UUID `json:"execution_id"`
+	// Status before the transition (NULL on creation)
+	FromStatus NullExecutionStatus `json:"from_status"`
+	// Status after the transition
+	ToStatus ExecutionStatus `json:"to_status"`
+	// Component or user that caused the transition
+	Actor ExecutionActor `json:"actor"`
+	// Human-readable explanation of the transition
+	Reason string `json:"reason"`
+	// When the transition happened
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Job definitions and configurations
@@ -135,6 +391,40 @@ type Job struct {
 	CreatedAt time.Time `json:"created_at"`
 	// Last job update timestamp
 	UpdatedAt time.Time `json:"updated_at"`
+	// Cron expression for recurring runs (NULL for on-demand jobs)
+	CronSchedule *string `json:"cron_schedule"`
+	// IANA timezone the cron expression is evaluated in
+	Timezone string `json:"timezone"`
+	// Next nominal fire time of the cron schedule
+	NextRunAt null.Time `json:"next_run_at"`
+	// Minimum number of vCPUs the job needs
+	CpuCores int32 `json:"cpu_cores"`
+	// Minimum memory the job needs, in MiB
+	MemoryMb int32 `json:"memory_mb"`
+	// Minimum ephemeral disk the job needs, in GiB
+	DiskGb int32 `json:"disk_gb"`
+	// Expected runtime used for cost and carbon estimates (NULL uses the scheduler default)
+	ExpectedRuntimeMinutes *int32 `json:"expected_runtime_minutes"`
+	// Runtime after which an execution is stopped and marked failed (NULL for no limit)
+	MaxRuntimeMinutes *int32 `json:"max_runtime_minutes"`
+}
+
+// Stores single-use password reset tokens
+type PasswordResetToken struct {
+	// Unique reset token identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to users table
+	UserID uuid.UUID `json:"user_id"`
+	// SHA-256 hash of the reset token
+	TokenHash string `json:"token_hash"`
+	// When this reset token expires
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	// When this reset token was used or invalidated (NULL while usable)
+	UsedAt pgtype.Timestamptz `json:"used_at"`
+	// When this reset token was requested
+	CreatedAt time.Time `json:"created_at"`
+	// IP address of the client that requested this token
+	IpAddress *netip.Addr `json:"ip_address"`
 }
 
 // Stores refresh tokens for JWT authentication
@@ -157,6 +447,28 @@ type RefreshToken struct {
 	UserAgent *string `json:"user_agent"`
 	// IP address of the client that created this token
 	IpAddress *netip.Addr `json:"ip_address"`
+	// Login session the token descends from; shared by all tokens rotated from it
+	FamilyID uuid.UUID `json:"family_id"`
+	// When this token was exchanged for its successor (NULL if never used to refresh)
+	RotatedAt pgtype.Timestamptz `json:"rotated_at"`
+}
+
+// Audit log of security-relevant account events
+type SecurityEvent struct {
+	// Unique security event identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to the affected user
+	UserID uuid.UUID `json:"user_id"`
+	// What happened
+	EventType SecurityEventType `json:"event_type"`
+	// Event-specific context, such as the revoked token family
+	Details []byte `json:"details"`
+	// User agent of the client that triggered the event
+	UserAgent *string `json:"user_agent"`
+	// IP address of the client that triggered the event
+	IpAddress *netip.Addr `json:"ip_address"`
+	// When the event was recorded
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // Stores basic user account information
@@ -177,6 +489,8 @@ type User struct {
 	LastLogin pgtype.Timestamptz `json:"last_login"`
 	// Whether the user account is active (not suspended/disabled)
 	IsActive bool `json:"is_active"`
+	// Whether the user can access admin endpoints
+	IsAdmin bool `json:"is_admin"`
 }
 
 // User optimization preferences and weights
@@ -190,3 +504,65 @@ type UserSetting struct {
 	// Last settings update timestamp
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Outbox of webhook events to deliver
+type WebhookDelivery struct {
+	// Unique delivery identifier, sent with every attempt
+	ID uuid.UUID `json:"id"`
+	// Reference to the receiving subscription
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	// Lifecycle event being reported
+	Event string `json:"event"`
+	// Reference to the execution the event is about
+	ExecutionID uuid.UUID `json:"execution_id"`
+	// JSON body sent to the endpoint
+	Payload []byte `json:"payload"`
+	// Whether the delivery is pending, delivered or failed
+	Status WebhookDeliveryStatus `json:"status"`
+	// Number of attempts made so far
+	Attempts int32 `json:"attempts"`
+	// When the next attempt is due, or until when a dispatcher holds it
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	// Error of the latest failed attempt
+	LastError *string `json:"last_error"`
+	// When the endpoint acknowledged the event
+	DeliveredAt null.Time `json:"delivered_at"`
+	// When the event was enqueued
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// History of HTTP requests made for webhook deliveries
+type WebhookDeliveryAttempt struct {
+	// Unique attempt identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to the delivery attempted
+	DeliveryID uuid.UUID `json:"delivery_id"`
+	// HTTP status of the response (NULL if none was received)
+	StatusCode *int32 `json:"status_code"`
+	// Why the attempt failed (NULL on success)
+	Error *string `json:"error"`
+	// How long the request took, in milliseconds
+	DurationMs int32 `json:"duration_ms"`
+	// When the request was made
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+// Endpoints notified of execution lifecycle events
+type WebhookSubscription struct {
+	// Unique subscription identifier
+	ID uuid.UUID `json:"id"`
+	// Reference to the user whose executions are reported
+	OwnerID uuid.UUID `json:"owner_id"`
+	// Endpoint that receives POSTed events
+	Url string `json:"url"`
+	// Key for the HMAC-SHA256 payload signature (kept in plain text to sign with)
+	Secret string `json:"secret"`
+	// Events to deliver (empty for all)
+	Events []string `json:"events"`
+	// Whether new events are enqueued for this subscription
+	IsActive bool `json:"is_active"`
+	// Subscription creation timestamp
+	CreatedAt time.Time `json:"created_at"`
+	// Last subscription update timestamp
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExecutionEventsChannel is the NOTIFY channel carrying execution status changes,
+// sent by the notify_execution_status trigger, and log lines, sent by executors
+const ExecutionEventsChannel = "execution_events"
+
+// Kinds of execution notification
+const (
+	ExecutionNotificationStatus = "status"
+	ExecutionNotificationLog    = "log"
+)
+
+// MaxNotificationPayload is the largest payload Postgres accepts in NOTIFY
+const MaxNotificationPayload = 8000
+
+// ErrNotificationTooLarge is returned when a notification exceeds MaxNotificationPayload
+var ErrNotificationTooLarge = errors.New("notification payload too large")
+
+// ExecutionNotification is the JSON payload of an ExecutionEventsChannel notification
+type ExecutionNotification struct {
+	Type        string          `json:"type"`
+	ExecutionID uuid.UUID       `json:"execution_id"`
+	JobID       uuid.UUID       `json:"job_id"`
+	OwnerID     uuid.UUID       `json:"owner_id"`
+	Status      ExecutionStatus `json:"status,omitempty"` // Set on status notifications
+	Lines       []string        `json:"lines,omitempty"`  // Set on log notifications
+	At          time.Time       `json:"at"`
+}
+
+// NotifyExecutionEvent publishes a notification on ExecutionEventsChannel. It is
+// delivered to listeners when the current transaction, if any, commits.
+func (q *Queries) NotifyExecutionEvent(ctx context.Context, notification ExecutionNotification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	if len(payload) > MaxNotificationPayload {
+		return ErrNotificationTooLarge
+	}
+
+	_, err = q.db.Exec(ctx, "SELECT pg_notify($1, $2)", ExecutionEventsChannel, string(payload))
+	return err
+}
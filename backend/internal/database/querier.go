@@ -11,44 +11,89 @@ import (
 )
 
 type Querier interface {
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimPendingExecutions(ctx context.Context, arg ClaimPendingExecutionsParams) ([]Execution, error)
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateExecution(ctx context.Context, arg CreateExecutionParams) (Execution, error)
+	CreateExecutionEvent(ctx context.Context, arg CreateExecutionEventParams) error
 	CreateJob(ctx context.Context, arg CreateJobParams) (Job, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSecurityEvent(ctx context.Context, arg CreateSecurityEventParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
 	DeactivateUser(ctx context.Context, id uuid.UUID) error
 	DeleteExecution(ctx context.Context, id uuid.UUID) error
 	DeleteExpiredRefreshTokens(ctx context.Context) error
 	DeleteJob(ctx context.Context, arg DeleteJobParams) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteWebhookSubscription(ctx context.Context, arg DeleteWebhookSubscriptionParams) (int64, error)
+	EnsureUserSettings(ctx context.Context, userID uuid.UUID) error
+	FireJobSchedule(ctx context.Context, arg FireJobScheduleParams) (int64, error)
+	GetAbandonedRunningExecutions(ctx context.Context, arg GetAbandonedRunningExecutionsParams) ([]Execution, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetDueJobSchedules(ctx context.Context, arg GetDueJobSchedulesParams) ([]Job, error)
 	GetExecution(ctx context.Context, id uuid.UUID) (Execution, error)
+	GetExecutionEvents(ctx context.Context, executionID uuid.UUID) ([]ExecutionEvent, error)
 	GetExecutionStats(ctx context.Context, jobID uuid.UUID) (GetExecutionStatsRow, error)
 	GetExecutionsByJobID(ctx context.Context, jobID uuid.UUID) ([]Execution, error)
 	GetExecutionsByJobIDWithLimit(ctx context.Context, arg GetExecutionsByJobIDWithLimitParams) ([]Execution, error)
 	GetExecutionsByStatus(ctx context.Context, status ExecutionStatus) ([]Execution, error)
 	GetJob(ctx context.Context, arg GetJobParams) (Job, error)
+	GetJobByID(ctx context.Context, id uuid.UUID) (Job, error)
 	GetJobCount(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	GetJobSchedulingInfo(ctx context.Context, id uuid.UUID) (GetJobSchedulingInfoRow, error)
 	GetJobsByOwner(ctx context.Context, ownerID uuid.UUID) ([]Job, error)
 	GetJobsByOwnerWithLimit(ctx context.Context, arg GetJobsByOwnerWithLimitParams) ([]Job, error)
+	GetOrphanedExecutionsDue(ctx context.Context, arg GetOrphanedExecutionsDueParams) ([]Execution, error)
 	GetPendingExecutions(ctx context.Context) ([]Execution, error)
 	GetRecentJobs(ctx context.Context, arg GetRecentJobsParams) ([]Job, error)
 	GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByEmailIncludeInactive(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
-	GetUserExecutionStats(ctx context.Context, ownerID uuid.UUID) (GetUserExecutionStatsRow, error)
+	GetUserExecutionStats(ctx context.Context, arg GetUserExecutionStatsParams) (GetUserExecutionStatsRow, error)
+	GetUserExecutionStatsByBucket(ctx context.Context, arg GetUserExecutionStatsByBucketParams) ([]GetUserExecutionStatsByBucketRow, error)
 	GetUserRefreshTokens(ctx context.Context, userID uuid.UUID) ([]RefreshToken, error)
+	GetUserSavings(ctx context.Context, ownerID uuid.UUID) (GetUserSavingsRow, error)
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (GetUserSettingsRow, error)
+	GetWebhookDelivery(ctx context.Context, arg GetWebhookDeliveryParams) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, arg GetWebhookSubscriptionParams) (WebhookSubscription, error)
+	GetWebhookSubscriptionByID(ctx context.Context, id uuid.UUID) (WebhookSubscription, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error
+	LeaseDueExecutions(ctx context.Context, arg LeaseDueExecutionsParams) ([]Execution, error)
+	ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]ApiKey, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]WebhookDeliveryAttempt, error)
+	ListWebhookSubscriptions(ctx context.Context, ownerID uuid.UUID) ([]WebhookSubscription, error)
+	MarkExecutionOrphaned(ctx context.Context, arg MarkExecutionOrphanedParams) (int64, error)
+	RecordTeardownFailure(ctx context.Context, arg RecordTeardownFailureParams) error
+	ReleaseExpiredLeases(ctx context.Context, actor ExecutionActor) (int64, error)
+	RenewExecutionLease(ctx context.Context, arg RenewExecutionLeaseParams) (int64, error)
+	ResolveOrphanedExecution(ctx context.Context, arg ResolveOrphanedExecutionParams) (int64, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
 	RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	RevokeOtherUserRefreshTokens(ctx context.Context, arg RevokeOtherUserRefreshTokensParams) (int64, error)
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokenFamily(ctx context.Context, arg RevokeUserRefreshTokenFamilyParams) (int64, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID) error
+	UpdateAPIKeyLastUsed(ctx context.Context, arg UpdateAPIKeyLastUsedParams) error
 	UpdateExecutionComplete(ctx context.Context, arg UpdateExecutionCompleteParams) (Execution, error)
 	UpdateExecutionCostEstimate(ctx context.Context, arg UpdateExecutionCostEstimateParams) (Execution, error)
+	UpdateExecutionInstance(ctx context.Context, arg UpdateExecutionInstanceParams) error
 	UpdateExecutionScheduling(ctx context.Context, arg UpdateExecutionSchedulingParams) (Execution, error)
 	UpdateExecutionStart(ctx context.Context, arg UpdateExecutionStartParams) (Execution, error)
-	UpdateExecutionStatus(ctx context.Context, arg UpdateExecutionStatusParams) (Execution, error)
 	UpdateJob(ctx context.Context, arg UpdateJobParams) (Job, error)
 	UpdateRefreshTokenLastUsed(ctx context.Context, id uuid.UUID) error
 	UpdateUserEmailVerified(ctx context.Context, arg UpdateUserEmailVerifiedParams) error
 	UpdateUserLastLogin(ctx context.Context, id uuid.UUID) error
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) error
+	UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) (UpsertUserSettingsRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
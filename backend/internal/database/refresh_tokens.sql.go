@@ -19,10 +19,11 @@ INSERT INTO refresh_tokens (
     token_hash,
     expires_at,
     user_agent,
-    ip_address
+    ip_address,
+    family_id
 ) VALUES (
-    $1, $2, $3, $4, $5
-) RETURNING id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address, family_id, rotated_at
 `
 
 type CreateRefreshTokenParams struct {
@@ -31,6 +32,7 @@ type CreateRefreshTokenParams struct {
 	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
 	UserAgent *string            `json:"user_agent"`
 	IpAddress *netip.Addr        `json:"ip_address"`
+	FamilyID  uuid.UUID          `json:"family_id"`
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
@@ -40,6 +42,7 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		arg.ExpiresAt,
 		arg.UserAgent,
 		arg.IpAddress,
+		arg.FamilyID,
 	)
 	var i RefreshToken
 	err := row.Scan(
@@ -52,22 +55,31 @@ func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshToken
 		&i.IsRevoked,
 		&i.UserAgent,
 		&i.IpAddress,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const deleteExpiredRefreshTokens = `-- name: DeleteExpiredRefreshTokens :exec
-DELETE FROM refresh_tokens 
-WHERE expires_at < now() OR is_revoked = TRUE
+DELETE FROM refresh_tokens t
+WHERE t.expires_at < now()
+  AND NOT EXISTS (
+    SELECT 1 FROM refresh_tokens f
+    WHERE f.family_id = t.family_id
+      AND f.expires_at >= now()
+  )
 `
 
+// Deletes expired tokens once no token in their family is still valid, so rotated
+// tokens stay available for reuse detection for as long as the session lasts.
 func (q *Queries) DeleteExpiredRefreshTokens(ctx context.Context) error {
 	_, err := q.db.Exec(ctx, deleteExpiredRefreshTokens)
 	return err
 }
 
 const getRefreshToken = `-- name: GetRefreshToken :one
-SELECT id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address FROM refresh_tokens 
+SELECT id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address, family_id, rotated_at FROM refresh_tokens 
 WHERE token_hash = $1 
   AND is_revoked = FALSE 
   AND expires_at > now()
@@ -86,12 +98,41 @@ func (q *Queries) GetRefreshToken(ctx context.Context, tokenHash string) (Refres
 		&i.IsRevoked,
 		&i.UserAgent,
 		&i.IpAddress,
+		&i.FamilyID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address, family_id, rotated_at FROM refresh_tokens 
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+// Loads a token whatever its state and locks it, so concurrent refreshes with
+// the same token are serialised and the later one is detected as reuse.
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenForUpdate, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsed,
+		&i.IsRevoked,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.FamilyID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
 const getUserRefreshTokens = `-- name: GetUserRefreshTokens :many
-SELECT id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address FROM refresh_tokens 
+SELECT id, user_id, token_hash, expires_at, created_at, last_used, is_revoked, user_agent, ip_address, family_id, rotated_at FROM refresh_tokens 
 WHERE user_id = $1 
   AND is_revoked = FALSE 
   AND expires_at > now()
@@ -117,6 +158,8 @@ func (q *Queries) GetUserRefreshTokens(ctx context.Context, userID uuid.UUID) ([
 			&i.IsRevoked,
 			&i.UserAgent,
 			&i.IpAddress,
+			&i.FamilyID,
+			&i.RotatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -139,6 +182,28 @@ func (q *Queries) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UU
 	return err
 }
 
+const revokeOtherUserRefreshTokens = `-- name: RevokeOtherUserRefreshTokens :execrows
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE user_id = $1 
+  AND family_id <> $2 
+  AND is_revoked = FALSE
+`
+
+type RevokeOtherUserRefreshTokensParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	FamilyID uuid.UUID `json:"family_id"`
+}
+
+// Signs a user out of every session except one.
+func (q *Queries) RevokeOtherUserRefreshTokens(ctx context.Context, arg RevokeOtherUserRefreshTokensParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeOtherUserRefreshTokens, arg.UserID, arg.FamilyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const revokeRefreshToken = `-- name: RevokeRefreshToken :exec
 UPDATE refresh_tokens 
 SET is_revoked = TRUE
@@ -150,6 +215,52 @@ func (q *Queries) RevokeRefreshToken(ctx context.Context, tokenHash string) erro
 	return err
 }
 
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE family_id = $1
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, familyID)
+	return err
+}
+
+const revokeUserRefreshTokenFamily = `-- name: RevokeUserRefreshTokenFamily :execrows
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE user_id = $1 
+  AND family_id = $2 
+  AND is_revoked = FALSE
+`
+
+type RevokeUserRefreshTokenFamilyParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	FamilyID uuid.UUID `json:"family_id"`
+}
+
+func (q *Queries) RevokeUserRefreshTokenFamily(ctx context.Context, arg RevokeUserRefreshTokenFamilyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserRefreshTokenFamily, arg.UserID, arg.FamilyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens 
+SET 
+    is_revoked = TRUE,
+    rotated_at = now(),
+    last_used = now()
+WHERE id = $1
+`
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, rotateRefreshToken, id)
+	return err
+}
+
 const updateRefreshTokenLastUsed = `-- name: UpdateRefreshTokenLastUsed :exec
 UPDATE refresh_tokens 
 SET last_used = now()
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sustainabilityReport aggregates a user's finished executions per UTC month of
+// completion, region and job. Savings only count executions with both a baseline
+// and an actual figure; intensity_energy_kwh is the energy with known emissions,
+// against which the average intensity is weighted.
+const sustainabilityReport = `
+SELECT 
+    date_trunc('month', e.completed_at, 'UTC')::timestamptz as month,
+    COALESCE(e.cloud_region, '') as region,
+    j.id as job_id,
+    j.image_uri,
+    COUNT(*) as executions,
+    (COALESCE(SUM(EXTRACT(EPOCH FROM (e.completed_at - e.started_at))), 0) / 3600)::float8 as runtime_hours,
+    COALESCE(SUM(e.energy_kwh), 0)::float8 as energy_kwh,
+    COALESCE(SUM(e.energy_kwh) FILTER (WHERE e.carbon_emitted_kg IS NOT NULL), 0)::float8 as intensity_energy_kwh,
+    COALESCE(SUM(e.carbon_emitted_kg), 0)::float8 as emissions_kg,
+    COALESCE(SUM(e.baseline_carbon_kg - e.carbon_emitted_kg), 0)::float8 as emissions_savings_kg,
+    COALESCE(SUM(e.cost_actual_usd), 0)::float8 as cost_usd,
+    COALESCE(SUM(e.baseline_cost_usd - e.cost_actual_usd), 0)::float8 as cost_savings_usd
+FROM executions e
+JOIN jobs j ON e.job_id = j.id
+WHERE j.owner_id = $1
+  AND e.started_at IS NOT NULL
+  AND e.completed_at >= $2
+  AND e.completed_at < $3
+GROUP BY month, region, j.id, j.image_uri
+ORDER BY month, region, j.id
+`
+
+// SustainabilityReportParams selects the executions of one user completed in [From, To)
+type SustainabilityReportParams struct {
+	OwnerID uuid.UUID
+	From    time.Time
+	To      time.Time
+}
+
+// SustainabilityReportRow is the footprint of one job in one region and month
+type SustainabilityReportRow struct {
+	Month              time.Time
+	Region             string
+	JobID              uuid.UUID
+	ImageUri           string
+	Executions         int64
+	RuntimeHours       float64
+	EnergyKwh          float64
+	IntensityEnergyKwh float64 // Energy of the executions with known emissions
+	EmissionsKg        float64
+	EmissionsSavingsKg float64
+	CostUsd            float64
+	CostSavingsUsd     float64
+}
+
+// StreamSustainabilityReport calls fn for each row of a user's sustainability report
+// as it is read from the database. Unlike the generated :many queries it does not
+// buffer the result, so reports over long periods use constant memory. Iteration
+// stops at the first error returned by fn.
+func (q *Queries) StreamSustainabilityReport(ctx context.Context, arg SustainabilityReportParams, fn func(SustainabilityReportRow) error) error {
+	rows, err := q.db.Query(ctx, sustainabilityReport, arg.OwnerID, arg.From, arg.To)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i SustainabilityReportRow
+		if err := rows.Scan(
+			&i.Month,
+			&i.Region,
+			&i.JobID,
+			&i.ImageUri,
+			&i.Executions,
+			&i.RuntimeHours,
+			&i.EnergyKwh,
+			&i.IntensityEnergyKwh,
+			&i.EmissionsKg,
+			&i.EmissionsSavingsKg,
+			&i.CostUsd,
+			&i.CostSavingsUsd,
+		); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -19,7 +19,7 @@ INSERT INTO users (
     is_active
 ) VALUES (
     $1, $2, $3, $4
-) RETURNING id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active
+) RETURNING id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active, is_admin
 `
 
 type CreateUserParams struct {
@@ -46,6 +46,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.EmailVerified,
 		&i.LastLogin,
 		&i.IsActive,
+		&i.IsAdmin,
 	)
 	return i, err
 }
@@ -74,7 +75,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active FROM users 
+SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active, is_admin FROM users 
 WHERE email = $1 AND is_active = TRUE
 `
 
@@ -90,12 +91,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.EmailVerified,
 		&i.LastLogin,
 		&i.IsActive,
+		&i.IsAdmin,
 	)
 	return i, err
 }
 
 const getUserByEmailIncludeInactive = `-- name: GetUserByEmailIncludeInactive :one
-SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active FROM users 
+SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active, is_admin FROM users 
 WHERE email = $1
 `
 
@@ -111,12 +113,13 @@ func (q *Queries) GetUserByEmailIncludeInactive(ctx context.Context, email strin
 		&i.EmailVerified,
 		&i.LastLogin,
 		&i.IsActive,
+		&i.IsAdmin,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active FROM users 
+SELECT id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active, is_admin FROM users 
 WHERE id = $1 AND is_active = TRUE
 `
 
@@ -132,6 +135,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.EmailVerified,
 		&i.LastLogin,
 		&i.IsActive,
+		&i.IsAdmin,
 	)
 	return i, err
 }
@@ -173,7 +177,7 @@ SET
     hashed_password = $2,
     updated_at = now()
 WHERE id = $1 AND is_active = TRUE
-RETURNING id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active
+RETURNING id, email, hashed_password, created_at, updated_at, email_verified, last_login, is_active, is_admin
 `
 
 type UpdateUserPasswordParams struct {
@@ -193,6 +197,7 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 		&i.EmailVerified,
 		&i.LastLogin,
 		&i.IsActive,
+		&i.IsAdmin,
 	)
 	return i, err
 }
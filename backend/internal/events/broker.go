@@ -0,0 +1,161 @@
+// Package events fans execution notifications out to API clients. Every API
+// replica listens on the database's execution events channel, so a client sees
+// changes made by any scheduler, executor or replica.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+const (
+	// subscriptionBuffer is how many notifications a subscriber may fall behind by
+	// before it is dropped
+	subscriptionBuffer = 64
+	// retryDelay is how long the broker waits before listening again after its
+	// connection fails
+	retryDelay = 5 * time.Second
+)
+
+// Filter selects the notifications a subscriber receives
+type Filter struct {
+	OwnerID uuid.UUID // Owner of the executions' jobs
+	JobID   uuid.UUID // Restricts to one job, uuid.Nil for all of the owner's jobs
+}
+
+// Matches reports whether a notification passes the filter
+func (f Filter) Matches(n database.ExecutionNotification) bool {
+	if n.OwnerID != f.OwnerID {
+		return false
+	}
+	return f.JobID == uuid.Nil || n.JobID == f.JobID
+}
+
+// Subscription receives the notifications matching its filter. Events is closed
+// when the subscription is closed, or if the subscriber falls too far behind.
+type Subscription struct {
+	Events <-chan database.ExecutionNotification
+
+	events chan database.ExecutionNotification
+	filter Filter
+	broker *Broker
+}
+
+// Close stops delivery to the subscription
+func (s *Subscription) Close() {
+	s.broker.remove(s)
+}
+
+// Broker listens for execution notifications and delivers them to subscribers
+type Broker struct {
+	pool *pgxpool.Pool
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker creates a broker that listens through a connection from pool
+func NewBroker(pool *pgxpool.Pool) *Broker {
+	return &Broker{
+		pool:        pool,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for notifications matching filter
+func (b *Broker) Subscribe(filter Filter) *Subscription {
+	events := make(chan database.ExecutionNotification, subscriptionBuffer)
+	s := &Subscription{Events: events, events: events, filter: filter, broker: b}
+
+	b.mu.Lock()
+	b.subscribers[s] = struct{}{}
+	b.mu.Unlock()
+
+	return s
+}
+
+// Run listens for notifications until ctx is cancelled, listening again after
+// connection failures. Notifications sent while the broker is reconnecting are lost.
+func (b *Broker) Run(ctx context.Context) error {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("events: listener failed, retrying in %s: %v", retryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection and dispatches its notifications
+func (b *Broker) listen(ctx context.Context) error {
+	acquired, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// A listening connection must not be returned to the pool
+	conn := acquired.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{database.ExecutionEventsChannel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		b.Dispatch(notification.Payload)
+	}
+}
+
+// Dispatch decodes a notification payload and delivers it to matching subscribers.
+// Subscribers whose buffer is full are dropped rather than blocking the others.
+func (b *Broker) Dispatch(payload string) {
+	var n database.ExecutionNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		log.Printf("events: ignoring malformed notification: %v", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subscribers {
+		if !s.filter.Matches(n) {
+			continue
+		}
+		select {
+		case s.events <- n:
+		default:
+			delete(b.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+// remove unregisters a subscriber, closing its channel if still open
+func (b *Broker) remove(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func payload(t *testing.T, n database.ExecutionNotification) string {
+	encoded, err := json.Marshal(n)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestBroker_DispatchFiltersByOwnerAndJob(t *testing.T) {
+	broker := NewBroker(nil)
+	owner, jobA, jobB := uuid.New(), uuid.New(), uuid.New()
+
+	all := broker.Subscribe(Filter{OwnerID: owner})
+	defer all.Close()
+	onlyA := broker.Subscribe(Filter{OwnerID: owner, JobID: jobA})
+	defer onlyA.Close()
+	other := broker.Subscribe(Filter{OwnerID: uuid.New()})
+	defer other.Close()
+
+	broker.Dispatch(payload(t, database.ExecutionNotification{
+		Type:    database.ExecutionNotificationStatus,
+		OwnerID: owner,
+		JobID:   jobB,
+		Status:  database.ExecutionStatusRunning,
+		At:      time.Now(),
+	}))
+
+	// Postgres renders timestamps with a numeric offset
+	broker.Dispatch(`{"type": "log", "execution_id": "` + uuid.NewString() + `", "job_id": "` + jobA.String() +
+		`", "owner_id": "` + owner.String() + `", "lines": ["hello"], "at": "2025-01-01T12:00:00.123456+00:00"}`)
+
+	require.Len(t, all.Events, 2)
+	assert.Equal(t, database.ExecutionStatusRunning, (<-all.Events).Status)
+	assert.Equal(t, []string{"hello"}, (<-all.Events).Lines)
+
+	require.Len(t, onlyA.Events, 1)
+	assert.Equal(t, jobA, (<-onlyA.Events).JobID)
+
+	assert.Empty(t, other.Events)
+}
+
+func TestBroker_DropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker(nil)
+	owner := uuid.New()
+	s := broker.Subscribe(Filter{OwnerID: owner})
+
+	n := payload(t, database.ExecutionNotification{Type: database.ExecutionNotificationStatus, OwnerID: owner})
+	for i := 0; i <= subscriptionBuffer; i++ {
+		broker.Dispatch(n)
+	}
+
+	received := 0
+	for range s.Events {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received)
+
+	// Closing a dropped subscription is harmless
+	s.Close()
+}
+
+func TestBroker_IgnoresMalformedPayloads(t *testing.T) {
+	broker := NewBroker(nil)
+	s := broker.Subscribe(Filter{OwnerID: uuid.New()})
+	defer s.Close()
+
+	broker.Dispatch("not json")
+	assert.Empty(t, s.Events)
+}
@@ -0,0 +1,72 @@
+// Package executor runs scheduled executions on a compute backend and records
+// their lifecycle (start, exit code, logs) in the database.
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownInstance is returned when a backend has no record of an instance
+var ErrUnknownInstance = errors.New("executor: unknown instance")
+
+// Spec describes what to run and where
+type Spec struct {
+	ExecutionID uuid.UUID
+	Image       string
+	Env         map[string]string
+	Region      string
+	VMType      string
+	CPUCores    int       // Minimum vCPUs the workload needs, 0 if unspecified
+	MemoryMB    int       // Minimum memory the workload needs in MiB, 0 if unspecified
+	Output      io.Writer // Receives the workload's stdout and stderr, discarded if nil
+}
+
+// Instance identifies the compute provisioned for one execution
+type Instance struct {
+	ID          string
+	ExecutionID uuid.UUID // uuid.Nil if the owning execution is unknown
+	Region      string
+	VMType      string
+}
+
+// Result is the outcome of a finished workload
+type Result struct {
+	ExitCode int
+}
+
+// Backend provisions compute and runs job images on it. A backend is driven
+// through Provision, Start, Wait and Teardown for every execution; Teardown
+// must be safe to call more than once and after failed provisioning.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Provision allocates compute for the execution
+	Provision(ctx context.Context, spec Spec) (Instance, error)
+	// Start launches the job image on a provisioned instance
+	Start(ctx context.Context, instance Instance, spec Spec) error
+	// Wait blocks until the workload exits or ctx is cancelled
+	Wait(ctx context.Context, instance Instance) (Result, error)
+	// Teardown stops the workload if needed and releases the instance
+	Teardown(ctx context.Context, instance Instance) error
+}
+
+// Lister is implemented by backends that can enumerate the instances they hold.
+// The reconciler uses it to find instances whose execution has already finished
+// and the instances of executions abandoned by their runner.
+type Lister interface {
+	// List returns every instance created by this backend that still exists
+	List(ctx context.Context) ([]Instance, error)
+}
+
+// CompleteLister is a Lister that can see the instances of every executor replica,
+// such as those in a cloud account they share, rather than only its own host's.
+// An abandoned execution missing from its List has lost its instance; with any
+// other backend the reconciler tears the recorded instance down to be sure.
+type CompleteLister interface {
+	Lister
+	// ListsAllInstances reports whether List covers every replica's instances
+	ListsAllInstances() bool
+}
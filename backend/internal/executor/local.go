@@ -0,0 +1,287 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Local runtimes
+const (
+	RuntimeProcess = "process" // Runs the image URI as a shell command (development only)
+	RuntimeDocker  = "docker"
+	RuntimePodman  = "podman"
+)
+
+// instancePrefix namespaces local instance IDs (and container names)
+const instancePrefix = "veridian-"
+
+// outputWaitDelay bounds how long Wait keeps copying output after the workload
+// exits, in case a background process it left behind still holds its stdout
+const outputWaitDelay = 5 * time.Second
+
+// LocalBackend runs executions on the current machine, either as a subprocess or
+// through a local container runtime. It lets the whole execution lifecycle be
+// exercised without a cloud account.
+type LocalBackend struct {
+	runtime string
+
+	mu        sync.Mutex
+	instances map[string]*localInstance
+}
+
+// localInstance tracks the workload of one provisioned instance
+type localInstance struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// NewLocalBackend creates a local backend using the given runtime
+func NewLocalBackend(runtime string) (*LocalBackend, error) {
+	switch runtime {
+	case RuntimeProcess, RuntimeDocker, RuntimePodman:
+	default:
+		return nil, fmt.Errorf("executor: unknown local runtime %q", runtime)
+	}
+
+	return &LocalBackend{
+		runtime:   runtime,
+		instances: make(map[string]*localInstance),
+	}, nil
+}
+
+// Provision registers the instance; local compute needs no allocation
+func (b *LocalBackend) Provision(ctx context.Context, spec Spec) (Instance, error) {
+	instance := Instance{
+		ID:          instancePrefix + spec.ExecutionID.String(),
+		ExecutionID: spec.ExecutionID,
+		Region:      spec.Region,
+		VMType:      spec.VMType,
+	}
+
+	b.mu.Lock()
+	b.instances[instance.ID] = &localInstance{}
+	b.mu.Unlock()
+
+	return instance, nil
+}
+
+// Start launches the workload with stdout and stderr redirected to the spec's output
+func (b *LocalBackend) Start(ctx context.Context, instance Instance, spec Spec) error {
+	li, err := b.lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	cmd := b.command(instance, spec)
+	if spec.Output != nil {
+		cmd.Stdout = spec.Output
+		cmd.Stderr = spec.Output
+		cmd.WaitDelay = outputWaitDelay
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("executor: failed to start workload: %w", err)
+	}
+
+	b.mu.Lock()
+	li.cmd = cmd
+	li.done = make(chan struct{})
+	b.mu.Unlock()
+
+	go func() {
+		li.err = cmd.Wait()
+		close(li.done)
+	}()
+
+	return nil
+}
+
+// Wait blocks until the workload exits and reports its exit code
+func (b *LocalBackend) Wait(ctx context.Context, instance Instance) (Result, error) {
+	li, err := b.lookup(instance)
+	if err != nil {
+		return Result{}, err
+	}
+
+	done := b.doneChan(li)
+	if done == nil {
+		return Result{}, errors.New("executor: workload was not started")
+	}
+
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	case <-done:
+	}
+
+	var result Result
+
+	var exitErr *exec.ExitError
+	switch {
+	case li.err == nil, errors.Is(li.err, exec.ErrWaitDelay):
+		// ErrWaitDelay means the workload exited cleanly but left its output open
+		result.ExitCode = 0
+	case errors.As(li.err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return Result{}, fmt.Errorf("executor: workload failed: %w", li.err)
+	}
+
+	return result, nil
+}
+
+// Teardown kills the workload if it is still running
+func (b *LocalBackend) Teardown(ctx context.Context, instance Instance) error {
+	b.mu.Lock()
+	li, ok := b.instances[instance.ID]
+	delete(b.instances, instance.ID)
+	b.mu.Unlock()
+
+	if !ok {
+		// A container left behind by an earlier executor process
+		if b.runtime != RuntimeProcess {
+			return b.removeContainer(ctx, instance)
+		}
+		return nil
+	}
+
+	if done := b.doneChan(li); done != nil {
+		select {
+		case <-done:
+		default:
+			if err := b.kill(ctx, instance, li); err != nil {
+				return err
+			}
+			<-done
+		}
+	}
+
+	return nil
+}
+
+// List returns the instances tracked by this process and, for container runtimes,
+// any veridian containers still present in the runtime
+func (b *LocalBackend) List(ctx context.Context) ([]Instance, error) {
+	seen := make(map[string]bool)
+	var instances []Instance
+
+	b.mu.Lock()
+	for id := range b.instances {
+		seen[id] = true
+		instances = append(instances, parseInstanceID(id))
+	}
+	b.mu.Unlock()
+
+	if b.runtime == RuntimeProcess {
+		return instances, nil
+	}
+
+	out, err := exec.CommandContext(ctx, b.runtime, "ps", "--all", "--filter", "name="+instancePrefix, "--format", "{{.Names}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("executor: failed to list containers: %w", err)
+	}
+
+	for _, name := range strings.Fields(string(out)) {
+		if !strings.HasPrefix(name, instancePrefix) || seen[name] {
+			continue
+		}
+		seen[name] = true
+		instances = append(instances, parseInstanceID(name))
+	}
+
+	return instances, nil
+}
+
+// command builds the command that runs the spec's image
+func (b *LocalBackend) command(instance Instance, spec Spec) *exec.Cmd {
+	keys := make([]string, 0, len(spec.Env))
+	for key := range spec.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if b.runtime == RuntimeProcess {
+		cmd := exec.Command("sh", "-c", spec.Image)
+		cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + os.Getenv("HOME")}
+		for _, key := range keys {
+			cmd.Env = append(cmd.Env, key+"="+spec.Env[key])
+		}
+		return cmd
+	}
+
+	args := []string{"run", "--rm", "--name", instance.ID}
+	if spec.CPUCores > 0 {
+		args = append(args, "--cpus", strconv.Itoa(spec.CPUCores))
+	}
+	if spec.MemoryMB > 0 {
+		args = append(args, "--memory", strconv.Itoa(spec.MemoryMB)+"m")
+	}
+	for _, key := range keys {
+		args = append(args, "-e", key+"="+spec.Env[key])
+	}
+	args = append(args, spec.Image)
+
+	return exec.Command(b.runtime, args...)
+}
+
+// kill stops a running workload
+func (b *LocalBackend) kill(ctx context.Context, instance Instance, li *localInstance) error {
+	if b.runtime != RuntimeProcess {
+		// Killing the CLI client would leave the container running
+		return b.removeContainer(ctx, instance)
+	}
+
+	if err := li.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("executor: failed to kill workload: %w", err)
+	}
+	return nil
+}
+
+// removeContainer force-removes an instance's container. A container that no
+// longer exists counts as removed.
+func (b *LocalBackend) removeContainer(ctx context.Context, instance Instance) error {
+	out, err := exec.CommandContext(ctx, b.runtime, "rm", "-f", instance.ID).CombinedOutput()
+	if err != nil && !strings.Contains(strings.ToLower(string(out)), "no such container") {
+		return fmt.Errorf("executor: failed to remove container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// parseInstanceID recovers the execution ID embedded in a local instance ID
+func parseInstanceID(id string) Instance {
+	instance := Instance{ID: id}
+	if executionID, err := uuid.Parse(strings.TrimPrefix(id, instancePrefix)); err == nil {
+		instance.ExecutionID = executionID
+	}
+	return instance
+}
+
+// lookup returns the tracked state of an instance
+func (b *LocalBackend) lookup(instance Instance) (*localInstance, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	li, ok := b.instances[instance.ID]
+	if !ok {
+		return nil, ErrUnknownInstance
+	}
+	return li, nil
+}
+
+// doneChan returns the channel closed when the workload exits, or nil if it was never started
+func (b *LocalBackend) doneChan(li *localInstance) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return li.done
+}
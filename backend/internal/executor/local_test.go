@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runLocal(t *testing.T, backend *LocalBackend, spec Spec) Result {
+	t.Helper()
+	ctx := context.Background()
+
+	instance, err := backend.Provision(ctx, spec)
+	require.NoError(t, err)
+	defer backend.Teardown(ctx, instance)
+
+	require.NoError(t, backend.Start(ctx, instance, spec))
+
+	result, err := backend.Wait(ctx, instance)
+	require.NoError(t, err)
+	return result
+}
+
+func TestLocalBackend_ProcessLifecycle(t *testing.T) {
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	result := runLocal(t, backend, Spec{
+		ExecutionID: uuid.New(),
+		Image:       `echo "hello $TARGET"; echo oops >&2; exit 3`,
+		Env:         map[string]string{"TARGET": "world"},
+		Output:      &output,
+	})
+
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Contains(t, output.String(), "hello world")
+	assert.Contains(t, output.String(), "oops")
+}
+
+func TestLocalBackend_TeardownKillsRunningWorkload(t *testing.T) {
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	spec := Spec{ExecutionID: uuid.New(), Image: "sleep 30"}
+
+	instance, err := backend.Provision(ctx, spec)
+	require.NoError(t, err)
+	require.NoError(t, backend.Start(ctx, instance, spec))
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = backend.Wait(waitCtx, instance)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan error, 1)
+	go func() { done <- backend.Teardown(ctx, instance) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("teardown did not stop the workload")
+	}
+
+	// Teardown is idempotent and the instance is forgotten
+	assert.NoError(t, backend.Teardown(ctx, instance))
+	_, err = backend.Wait(ctx, instance)
+	assert.ErrorIs(t, err, ErrUnknownInstance)
+}
+
+func TestLocalBackend_ContainerResourceLimits(t *testing.T) {
+	backend, err := NewLocalBackend(RuntimeDocker)
+	require.NoError(t, err)
+
+	spec := Spec{ExecutionID: uuid.New(), Image: "busybox", CPUCores: 2, MemoryMB: 4096}
+	cmd := backend.command(Instance{ID: "veridian-test"}, spec)
+
+	assert.Equal(t, []string{"docker", "run", "--rm", "--name", "veridian-test", "--cpus", "2", "--memory", "4096m", "busybox"}, cmd.Args)
+}
+
+func TestNewLocalBackend_UnknownRuntime(t *testing.T) {
+	_, err := NewLocalBackend("kubernetes")
+	assert.Error(t, err)
+}
+
+func TestLocalBackend_ListTrackedInstances(t *testing.T) {
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	executionID := uuid.New()
+	instance, err := backend.Provision(ctx, Spec{ExecutionID: executionID})
+	require.NoError(t, err)
+
+	instances, err := backend.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, instance.ID, instances[0].ID)
+	assert.Equal(t, executionID, instances[0].ExecutionID)
+
+	require.NoError(t, backend.Teardown(ctx, instance))
+
+	instances, err = backend.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
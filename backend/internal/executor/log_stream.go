@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+const (
+	// logFlushInterval is how often buffered output lines are published
+	logFlushInterval = 500 * time.Millisecond
+	// maxLogBatchBytes publishes buffered lines early once they reach this size
+	maxLogBatchBytes = 2048
+	// maxLogBufferBytes bounds the lines waiting to be published; further lines
+	// are dropped until the publisher catches up
+	maxLogBufferBytes = 64 * 1024
+	// maxLogLineBytes truncates longer lines, keeping notifications under the
+	// Postgres payload limit even when every character needs escaping
+	maxLogLineBytes = 1024
+)
+
+// notifier publishes execution notifications
+type notifier interface {
+	NotifyExecutionEvent(ctx context.Context, notification database.ExecutionNotification) error
+}
+
+// logStream publishes a workload's output line by line for clients following the
+// execution live. Lines are batched so a chatty workload does not send one
+// notification per line, and published from a separate goroutine so a slow
+// database never blocks the workload's output. The stored log remains the
+// complete record; lines that cannot be published in time are dropped.
+type logStream struct {
+	ctx      context.Context
+	notifier notifier
+	template database.ExecutionNotification
+	now      func() time.Time
+
+	mu      sync.Mutex
+	partial []byte
+	lines   []string
+	size    int
+	dropped int
+
+	wake chan struct{} // Asks run to publish a full batch early
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newLogStream starts publishing output lines of an execution of job
+func newLogStream(ctx context.Context, n notifier, execution database.Execution, job database.Job, now func() time.Time) *logStream {
+	s := &logStream{
+		ctx:      ctx,
+		notifier: n,
+		template: database.ExecutionNotification{
+			Type:        database.ExecutionNotificationLog,
+			ExecutionID: execution.ID,
+			JobID:       job.ID,
+			OwnerID:     job.OwnerID,
+		},
+		now:  now,
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go s.run()
+	return s
+}
+
+// Write buffers complete lines of p, keeping any trailing partial line for later.
+// It never waits for lines to be published.
+func (s *logStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		s.add(string(bytes.TrimSuffix(s.partial[:i], []byte("\r"))))
+		s.partial = s.partial[i+1:]
+	}
+	// An unterminated line is published once it reaches the size limit
+	if len(s.partial) >= maxLogLineBytes {
+		s.add(string(s.partial))
+		s.partial = nil
+	}
+	full := s.size >= maxLogBatchBytes
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default: // A flush is already pending
+		}
+	}
+	return len(p), nil
+}
+
+// Close publishes the remaining output, including an unterminated last line
+func (s *logStream) Close() error {
+	close(s.stop)
+	<-s.done
+
+	s.mu.Lock()
+	if len(s.partial) > 0 {
+		s.add(string(s.partial))
+		s.partial = nil
+	}
+	s.mu.Unlock()
+
+	s.flush()
+	return nil
+}
+
+// run flushes buffered lines periodically, or early once a batch is full, until
+// the stream is closed
+func (s *logStream) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		s.flush()
+	}
+}
+
+// add buffers one line, truncated to maxLogLineBytes, or drops it if the buffer
+// is full. The caller holds mu.
+func (s *logStream) add(line string) {
+	if len(line) > maxLogLineBytes {
+		line = line[:maxLogLineBytes]
+	}
+	if s.size+len(line) > maxLogBufferBytes {
+		s.dropped++
+		return
+	}
+	s.lines = append(s.lines, line)
+	s.size += len(line)
+}
+
+// flush publishes the buffered lines. Only run, and Close once run has
+// returned, call it, so batches stay in order.
+func (s *logStream) flush() {
+	s.mu.Lock()
+	lines, dropped := s.lines, s.dropped
+	s.lines, s.size, s.dropped = nil, 0, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("executor: execution %s: publishing fell behind, dropped %d log line(s)", s.template.ExecutionID, dropped)
+	}
+	if len(lines) > 0 {
+		s.publish(lines)
+	}
+}
+
+// publish sends lines in one notification, splitting batches that are too large
+func (s *logStream) publish(lines []string) {
+	n := s.template
+	n.Lines = lines
+	n.At = s.now()
+
+	err := s.notifier.NotifyExecutionEvent(s.ctx, n)
+	if errors.Is(err, database.ErrNotificationTooLarge) && len(lines) > 1 {
+		s.publish(lines[:len(lines)/2])
+		s.publish(lines[len(lines)/2:])
+		return
+	}
+	if err != nil {
+		log.Printf("executor: execution %s: failed to publish %d log line(s): %v", n.ExecutionID, len(lines), err)
+	}
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// batchNotifier records published batches, rejecting those with more than limit lines
+type batchNotifier struct {
+	limit int
+
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (n *batchNotifier) NotifyExecutionEvent(ctx context.Context, notification database.ExecutionNotification) error {
+	if n.limit > 0 && len(notification.Lines) > n.limit {
+		return database.ErrNotificationTooLarge
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.batches = append(n.batches, notification.Lines)
+	return nil
+}
+
+func (n *batchNotifier) lines() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var lines []string
+	for _, batch := range n.batches {
+		lines = append(lines, batch...)
+	}
+	return lines
+}
+
+func newTestLogStream(n notifier) *logStream {
+	job := database.Job{ID: uuid.New(), OwnerID: uuid.New()}
+	execution := database.Execution{ID: uuid.New(), JobID: job.ID}
+	return newLogStream(context.Background(), n, execution, job, time.Now)
+}
+
+func TestLogStream_SplitsLines(t *testing.T) {
+	n := &batchNotifier{}
+	s := newTestLogStream(n)
+
+	_, err := s.Write([]byte("first\r\nsec"))
+	require.NoError(t, err)
+	_, err = s.Write([]byte("ond\nunterminated"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, []string{"first", "second", "unterminated"}, n.lines())
+}
+
+func TestLogStream_TruncatesLongLines(t *testing.T) {
+	n := &batchNotifier{}
+	s := newTestLogStream(n)
+
+	_, err := s.Write([]byte(strings.Repeat("x", 3*maxLogLineBytes) + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	for _, line := range n.lines() {
+		assert.LessOrEqual(t, len(line), maxLogLineBytes)
+	}
+}
+
+func TestLogStream_SplitsOversizedBatches(t *testing.T) {
+	n := &batchNotifier{limit: 2}
+	s := newTestLogStream(n)
+
+	_, err := s.Write([]byte("1\n2\n3\n4\n5\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, n.lines())
+	for _, batch := range n.batches {
+		assert.LessOrEqual(t, len(batch), 2)
+	}
+}
+
+// blockingNotifier holds every publish until released
+type blockingNotifier struct {
+	batchNotifier
+	publishing chan struct{}
+	release    chan struct{}
+}
+
+func (n *blockingNotifier) NotifyExecutionEvent(ctx context.Context, notification database.ExecutionNotification) error {
+	select {
+	case n.publishing <- struct{}{}:
+	default:
+	}
+	<-n.release
+	return n.batchNotifier.NotifyExecutionEvent(ctx, notification)
+}
+
+func TestLogStream_WriteDoesNotWaitForPublishing(t *testing.T) {
+	n := &blockingNotifier{publishing: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestLogStream(n)
+
+	// The first full batch stalls the publisher; output keeps flowing and the
+	// excess is dropped
+	line := strings.Repeat("x", 99) + "\n"
+	total := 4 * maxLogBufferBytes / len(line)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for i := 0; i < total; i++ {
+			_, err := s.Write([]byte(line))
+			assert.NoError(t, err)
+		}
+	}()
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write blocked on publishing")
+	}
+	select {
+	case <-n.publishing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("full batch was not published")
+	}
+
+	close(n.release)
+	require.NoError(t, s.Close())
+
+	published := len(n.lines())
+	assert.Less(t, published, total)
+	assert.GreaterOrEqual(t, published, maxLogBufferBytes/len(line))
+}
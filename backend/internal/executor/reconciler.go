@@ -0,0 +1,370 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// ReconcilerStore is the subset of database.Querier used by the reconciler
+type ReconcilerStore interface {
+	GetExecution(ctx context.Context, id uuid.UUID) (database.Execution, error)
+	MarkExecutionOrphaned(ctx context.Context, arg database.MarkExecutionOrphanedParams) (int64, error)
+	GetOrphanedExecutionsDue(ctx context.Context, arg database.GetOrphanedExecutionsDueParams) ([]database.Execution, error)
+	RecordTeardownFailure(ctx context.Context, arg database.RecordTeardownFailureParams) error
+	ResolveOrphanedExecution(ctx context.Context, arg database.ResolveOrphanedExecutionParams) (int64, error)
+	GetAbandonedRunningExecutions(ctx context.Context, arg database.GetAbandonedRunningExecutionsParams) ([]database.Execution, error)
+	UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error)
+}
+
+// ReconcilerConfig holds reconciler configuration
+type ReconcilerConfig struct {
+	Interval    time.Duration // How often provider resources are compared with executions
+	BatchSize   int32         // Maximum orphaned executions retried per pass
+	GracePeriod time.Duration // How long a finished execution's instance may linger, or an expired lease go unrenewed, before it is reconciled
+	BaseBackoff time.Duration // Delay before the first teardown retry, doubled after each failure
+	MaxBackoff  time.Duration // Upper bound on the delay between teardown retries
+}
+
+// Reconciler finds compute that outlived its execution, marks the execution
+// orphaned and retries the teardown with exponential backoff until it succeeds.
+// It also settles running executions whose runner stopped renewing their lease,
+// such as those of an executor replica that crashed.
+type Reconciler struct {
+	store   ReconcilerStore
+	backend Backend
+	config  ReconcilerConfig
+	now     func() time.Time
+}
+
+// NewReconciler creates a new reconciler. Leaked instances are only detected if
+// the backend implements Lister; abandoned executions are settled and orphaned
+// executions are retried either way.
+func NewReconciler(store ReconcilerStore, backend Backend, config ReconcilerConfig) *Reconciler {
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = time.Minute
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+
+	return &Reconciler{
+		store:   store,
+		backend: backend,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// Run reconciles on every interval until the context is cancelled
+func (r *Reconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Reconcile(ctx); err != nil {
+			log.Printf("reconciler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile runs one pass: leaked instances are marked orphaned, abandoned running
+// executions are settled, then due orphaned executions are torn down
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	var errs []error
+
+	// Instances by execution; nil when the backend cannot list them
+	var present map[uuid.UUID]Instance
+	if lister, ok := r.backend.(Lister); ok {
+		instances, err := lister.List(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list instances: %w", err))
+		} else {
+			present = make(map[uuid.UUID]Instance, len(instances))
+			for _, instance := range instances {
+				if instance.ExecutionID != uuid.Nil {
+					present[instance.ExecutionID] = instance
+				}
+			}
+			if err := r.detectLeaks(ctx, instances); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := r.reconcileRunning(ctx, present); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := r.retryTeardowns(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// detectLeaks compares the backend's instances with their execution rows
+func (r *Reconciler) detectLeaks(ctx context.Context, instances []Instance) error {
+	for _, instance := range instances {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if instance.ExecutionID == uuid.Nil {
+			log.Printf("reconciler: instance %s has no execution, tearing down", instance.ID)
+			r.teardownUntracked(ctx, instance)
+			continue
+		}
+
+		execution, err := r.store.GetExecution(ctx, instance.ExecutionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("reconciler: instance %s belongs to deleted execution %s, tearing down", instance.ID, instance.ExecutionID)
+			r.teardownUntracked(ctx, instance)
+			continue
+		}
+		if err != nil {
+			log.Printf("reconciler: instance %s: failed to load execution: %v", instance.ID, err)
+			continue
+		}
+
+		if !r.leaked(execution) {
+			continue
+		}
+
+		message := "instance still present after execution finished"
+		orphaned, err := transition(execution, database.ExecutionStatusOrphaned, fmt.Sprintf("instance %s still present after execution finished", instance.ID))
+		if err != nil {
+			log.Printf("reconciler: execution %s: %v", execution.ID, err)
+			continue
+		}
+
+		marked, err := r.store.MarkExecutionOrphaned(ctx, database.MarkExecutionOrphanedParams{
+			ID:                execution.ID,
+			FromStatus:        orphaned.From,
+			InstanceID:        &instance.ID,
+			NextTeardownAt:    null.TimeFrom(r.now()),
+			LastTeardownError: &message,
+			Actor:             orphaned.Actor,
+			Reason:            orphaned.Reason,
+		})
+		if err != nil {
+			log.Printf("reconciler: execution %s: failed to mark orphaned: %v", execution.ID, err)
+		} else if marked > 0 {
+			log.Printf("reconciler: execution %s: instance %s leaked, marked orphaned", execution.ID, instance.ID)
+		}
+	}
+
+	return nil
+}
+
+// leaked reports whether an execution has finished long enough ago that its
+// instance should already be gone
+func (r *Reconciler) leaked(execution database.Execution) bool {
+	switch execution.Status {
+	case database.ExecutionStatusCompletedSuccess, database.ExecutionStatusCompletedError:
+	default:
+		return false
+	}
+
+	if !execution.CompletedAt.Valid {
+		return true
+	}
+	return r.now().Sub(execution.CompletedAt.Time) >= r.config.GracePeriod
+}
+
+// reconcileRunning settles running executions whose runner stopped renewing its
+// lease more than the grace period ago, or that ran past their job's max runtime
+// without one. An execution whose instance is listed, or may exist somewhere this
+// backend cannot see, is marked orphaned so the instance is torn down; one whose
+// instance is known to be gone is failed. Executions of live runners, including
+// other replicas', are left alone.
+func (r *Reconciler) reconcileRunning(ctx context.Context, present map[uuid.UUID]Instance) error {
+	abandoned, err := r.store.GetAbandonedRunningExecutions(ctx, database.GetAbandonedRunningExecutionsParams{
+		Cutoff:    null.TimeFrom(r.now().Add(-r.config.GracePeriod)),
+		BatchSize: r.config.BatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load abandoned executions: %w", err)
+	}
+
+	// Only a backend that sees every replica's instances can tell that one is gone
+	complete := false
+	if lister, ok := r.backend.(CompleteLister); ok && present != nil {
+		complete = lister.ListsAllInstances()
+	}
+
+	for _, execution := range abandoned {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		switch instance, listed := present[execution.ID]; {
+		case listed:
+			r.orphanRunning(ctx, execution, instance.ID, "runner stopped while the execution was running")
+		case complete:
+			r.failRunning(ctx, execution, "runner stopped and the instance disappeared")
+		case execution.InstanceID != nil:
+			r.orphanRunning(ctx, execution, *execution.InstanceID, "runner stopped while the execution was running")
+		default:
+			r.failRunning(ctx, execution, "runner stopped before recording an instance")
+		}
+	}
+
+	return nil
+}
+
+// orphanRunning marks a running execution orphaned so its instance is torn down
+func (r *Reconciler) orphanRunning(ctx context.Context, execution database.Execution, instanceID, message string) {
+	orphaned, err := transition(execution, database.ExecutionStatusOrphaned, fmt.Sprintf("%s, tearing down instance %s", message, instanceID))
+	if err != nil {
+		log.Printf("reconciler: execution %s: %v", execution.ID, err)
+		return
+	}
+
+	marked, err := r.store.MarkExecutionOrphaned(ctx, database.MarkExecutionOrphanedParams{
+		ID:                execution.ID,
+		FromStatus:        orphaned.From,
+		InstanceID:        &instanceID,
+		NextTeardownAt:    null.TimeFrom(r.now()),
+		LastTeardownError: &message,
+		Actor:             orphaned.Actor,
+		Reason:            orphaned.Reason,
+	})
+	if err != nil {
+		log.Printf("reconciler: execution %s: failed to mark orphaned: %v", execution.ID, err)
+	} else if marked > 0 {
+		log.Printf("reconciler: execution %s: %s, marked orphaned", execution.ID, message)
+	}
+}
+
+// failRunning records a running execution that has no instance left as failed
+func (r *Reconciler) failRunning(ctx context.Context, execution database.Execution, message string) {
+	failed, err := transition(execution, database.ExecutionStatusCompletedError, message)
+	if err != nil {
+		log.Printf("reconciler: execution %s: %v", execution.ID, err)
+		return
+	}
+
+	_, err = r.store.UpdateExecutionComplete(ctx, database.UpdateExecutionCompleteParams{
+		ID:          execution.ID,
+		FromStatus:  failed.From,
+		Status:      failed.To,
+		CompletedAt: null.TimeFrom(r.now()),
+		Actor:       failed.Actor,
+		Reason:      failed.Reason,
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// The runner finished it in the meantime
+	case err != nil:
+		log.Printf("reconciler: execution %s: failed to mark failed: %v", execution.ID, err)
+	default:
+		log.Printf("reconciler: execution %s: %s, marked failed", execution.ID, message)
+	}
+}
+
+// teardownUntracked releases an instance that has no execution row to record against
+func (r *Reconciler) teardownUntracked(ctx context.Context, instance Instance) {
+	if err := r.backend.Teardown(ctx, instance); err != nil {
+		log.Printf("reconciler: failed to tear down %s: %v", instance.ID, err)
+	}
+}
+
+// retryTeardowns tears down orphaned executions whose next attempt is due
+func (r *Reconciler) retryTeardowns(ctx context.Context) error {
+	executions, err := r.store.GetOrphanedExecutionsDue(ctx, database.GetOrphanedExecutionsDueParams{
+		DueBefore: null.TimeFrom(r.now()),
+		BatchSize: r.config.BatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load orphaned executions: %w", err)
+	}
+
+	for _, execution := range executions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := r.retryTeardown(ctx, execution); err != nil {
+			log.Printf("reconciler: execution %s: %v", execution.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// retryTeardown attempts one teardown and records the outcome
+func (r *Reconciler) retryTeardown(ctx context.Context, execution database.Execution) error {
+	var teardownErr error
+	if execution.InstanceID == nil {
+		teardownErr = errors.New("no instance recorded for execution")
+	} else {
+		instance := Instance{ID: *execution.InstanceID, ExecutionID: execution.ID}
+		if execution.CloudRegion != nil {
+			instance.Region = *execution.CloudRegion
+		}
+		if execution.VmType != nil {
+			instance.VMType = *execution.VmType
+		}
+		teardownErr = r.backend.Teardown(ctx, instance)
+	}
+
+	if teardownErr == nil {
+		// Mirrors ResolveOrphanedExecution, which restores the recorded outcome
+		status := database.ExecutionStatusCompletedError
+		if execution.ExitCode.Valid && execution.ExitCode.Int64 == 0 {
+			status = database.ExecutionStatusCompletedSuccess
+		}
+		resolved, err := transition(execution, status, fmt.Sprintf("orphaned instance torn down after %d failed attempt(s)", execution.TeardownAttempts))
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.store.ResolveOrphanedExecution(ctx, database.ResolveOrphanedExecutionParams{
+			ID:     execution.ID,
+			Actor:  resolved.Actor,
+			Reason: resolved.Reason,
+		}); err != nil {
+			return fmt.Errorf("torn down but failed to resolve: %w", err)
+		}
+		log.Printf("reconciler: execution %s: orphaned instance torn down", execution.ID)
+		return nil
+	}
+
+	message := teardownErr.Error()
+	next := r.now().Add(r.backoff(execution.TeardownAttempts))
+	if err := r.store.RecordTeardownFailure(ctx, database.RecordTeardownFailureParams{
+		ID:                execution.ID,
+		NextTeardownAt:    null.TimeFrom(next),
+		LastTeardownError: &message,
+	}); err != nil {
+		return fmt.Errorf("teardown failed (%v) and failed to record failure: %w", teardownErr, err)
+	}
+
+	return fmt.Errorf("teardown failed, retrying at %s: %w", next.Format(time.RFC3339), teardownErr)
+}
+
+// backoff returns the delay before the next retry after the given number of failures
+func (r *Reconciler) backoff(attempts int32) time.Duration {
+	delay := r.config.BaseBackoff
+	for i := int32(0); i < attempts; i++ {
+		delay *= 2
+		if delay >= r.config.MaxBackoff {
+			return r.config.MaxBackoff
+		}
+	}
+	return delay
+}
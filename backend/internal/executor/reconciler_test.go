@@ -0,0 +1,311 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// reconcilerStore is an in-memory implementation of ReconcilerStore
+type reconcilerStore struct {
+	*mockStore
+	resolved []uuid.UUID
+}
+
+func (m *reconcilerStore) GetExecution(ctx context.Context, id uuid.UUID) (database.Execution, error) {
+	e, ok := m.executions[id]
+	if !ok {
+		return database.Execution{}, pgx.ErrNoRows
+	}
+	return e, nil
+}
+
+func (m *reconcilerStore) GetOrphanedExecutionsDue(ctx context.Context, arg database.GetOrphanedExecutionsDueParams) ([]database.Execution, error) {
+	var due []database.Execution
+	for _, e := range m.executions {
+		if e.Status == database.ExecutionStatusOrphaned && !e.NextTeardownAt.Time.After(arg.DueBefore.Time) {
+			due = append(due, e)
+		}
+	}
+	return due, nil
+}
+
+func (m *reconcilerStore) RecordTeardownFailure(ctx context.Context, arg database.RecordTeardownFailureParams) error {
+	e := m.executions[arg.ID]
+	e.TeardownAttempts++
+	e.NextTeardownAt = arg.NextTeardownAt
+	e.LastTeardownError = arg.LastTeardownError
+	m.executions[arg.ID] = e
+	return nil
+}
+
+func (m *reconcilerStore) ResolveOrphanedExecution(ctx context.Context, arg database.ResolveOrphanedExecutionParams) (int64, error) {
+	e := m.executions[arg.ID]
+	if e.Status != database.ExecutionStatusOrphaned {
+		return 0, nil
+	}
+	e.Status = database.ExecutionStatusCompletedError
+	if e.ExitCode.Valid && e.ExitCode.Int64 == 0 {
+		e.Status = database.ExecutionStatusCompletedSuccess
+	}
+	if !e.CompletedAt.Valid {
+		e.CompletedAt = null.TimeFrom(time.Now())
+	}
+	e.NextTeardownAt = null.Time{}
+	e.LastTeardownError = nil
+	m.executions[arg.ID] = e
+	m.resolved = append(m.resolved, arg.ID)
+	m.record(arg.ID, database.ExecutionStatusOrphaned, e.Status, arg.Actor, arg.Reason)
+	return 1, nil
+}
+
+func (m *reconcilerStore) GetAbandonedRunningExecutions(ctx context.Context, arg database.GetAbandonedRunningExecutionsParams) ([]database.Execution, error) {
+	var abandoned []database.Execution
+	for _, e := range m.executions {
+		if e.Status != database.ExecutionStatusRunning {
+			continue
+		}
+		if e.LeaseExpiresAt.Valid {
+			if e.LeaseExpiresAt.Time.Before(arg.Cutoff.Time) {
+				abandoned = append(abandoned, e)
+			}
+			continue
+		}
+		job := m.jobs[e.JobID]
+		if job.MaxRuntimeMinutes != nil && e.StartedAt.Time.Add(time.Duration(*job.MaxRuntimeMinutes)*time.Minute).Before(arg.Cutoff.Time) {
+			abandoned = append(abandoned, e)
+		}
+	}
+	return abandoned, nil
+}
+
+// fakeBackend is a CompleteLister whose teardowns fail for selected instances
+type fakeBackend struct {
+	LocalBackend
+	instances []Instance
+	complete  bool
+	stuck     map[string]bool
+	removed   []string
+}
+
+func (b *fakeBackend) List(ctx context.Context) ([]Instance, error) {
+	return b.instances, nil
+}
+
+func (b *fakeBackend) ListsAllInstances() bool {
+	return b.complete
+}
+
+func (b *fakeBackend) Teardown(ctx context.Context, instance Instance) error {
+	if b.stuck[instance.ID] {
+		return errors.New("instance is stuck")
+	}
+	b.removed = append(b.removed, instance.ID)
+	return nil
+}
+
+func newTestReconciler(store ReconcilerStore, backend Backend) *Reconciler {
+	r := NewReconciler(store, backend, ReconcilerConfig{
+		Interval:    time.Minute,
+		BatchSize:   10,
+		GracePeriod: 10 * time.Minute,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	})
+	r.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+	return r
+}
+
+func TestReconcile_TearsDownLeakedInstances(t *testing.T) {
+	store := &reconcilerStore{mockStore: newMockStore()}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	finished := database.Execution{
+		ID:          uuid.New(),
+		Status:      database.ExecutionStatusCompletedSuccess,
+		CompletedAt: null.TimeFrom(now.Add(-time.Hour)),
+		ExitCode:    null.IntFrom(0),
+	}
+	justFinished := database.Execution{
+		ID:          uuid.New(),
+		Status:      database.ExecutionStatusCompletedError,
+		CompletedAt: null.TimeFrom(now.Add(-time.Minute)),
+	}
+	running := database.Execution{ID: uuid.New(), Status: database.ExecutionStatusRunning}
+	for _, e := range []database.Execution{finished, justFinished, running} {
+		store.executions[e.ID] = e
+	}
+
+	backend := &fakeBackend{instances: []Instance{
+		{ID: "veridian-finished", ExecutionID: finished.ID},
+		{ID: "veridian-just-finished", ExecutionID: justFinished.ID},
+		{ID: "veridian-running", ExecutionID: running.ID},
+		{ID: "veridian-deleted", ExecutionID: uuid.New()},
+		{ID: "veridian-unknown"},
+	}}
+
+	r := newTestReconciler(store, backend)
+	require.NoError(t, r.Reconcile(context.Background()))
+
+	// Instances without an execution are removed immediately; leaked ones via the retry queue
+	assert.ElementsMatch(t, []string{"veridian-deleted", "veridian-unknown", "veridian-finished"}, backend.removed)
+
+	// The leak was torn down in the same pass, restoring the original outcome
+	assert.Equal(t, []uuid.UUID{finished.ID}, store.resolved)
+	assert.Equal(t, database.ExecutionStatusCompletedSuccess, store.executions[finished.ID].Status)
+	assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusOrphaned, database.ExecutionStatusCompletedSuccess}, store.transitionsOf(finished.ID))
+
+	// Within the grace period or still running: left alone
+	assert.Equal(t, database.ExecutionStatusCompletedError, store.executions[justFinished.ID].Status)
+	assert.Equal(t, database.ExecutionStatusRunning, store.executions[running.ID].Status)
+}
+
+func TestReconcile_BacksOffFailedTeardowns(t *testing.T) {
+	store := &reconcilerStore{mockStore: newMockStore()}
+	instanceID := "veridian-stuck"
+	orphaned := database.Execution{
+		ID:               uuid.New(),
+		Status:           database.ExecutionStatusOrphaned,
+		InstanceID:       &instanceID,
+		TeardownAttempts: 2,
+	}
+	store.executions[orphaned.ID] = orphaned
+
+	backend := &fakeBackend{stuck: map[string]bool{instanceID: true}}
+	r := newTestReconciler(store, backend)
+	require.NoError(t, r.Reconcile(context.Background()))
+
+	result := store.executions[orphaned.ID]
+	assert.Equal(t, database.ExecutionStatusOrphaned, result.Status)
+	assert.Equal(t, int32(3), result.TeardownAttempts)
+	assert.Equal(t, r.now().Add(4*time.Minute), result.NextTeardownAt.Time)
+	require.NotNil(t, result.LastTeardownError)
+	assert.Equal(t, "instance is stuck", *result.LastTeardownError)
+
+	// Not retried again before the backoff elapses
+	require.NoError(t, r.Reconcile(context.Background()))
+	assert.Equal(t, int32(3), store.executions[orphaned.ID].TeardownAttempts)
+}
+
+// runningExecution is an execution of job started an hour before the test reconciler's now
+func runningExecution(store *reconcilerStore, job database.Job, leaseOwner string, leaseExpiresIn time.Duration) database.Execution {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+	instanceID := instancePrefix + id.String()
+	execution := database.Execution{
+		ID:         id,
+		JobID:      job.ID,
+		Status:     database.ExecutionStatusRunning,
+		StartedAt:  null.TimeFrom(now.Add(-time.Hour)),
+		InstanceID: &instanceID,
+	}
+	if leaseOwner != "" {
+		execution.LeaseOwner = &leaseOwner
+		execution.LeaseExpiresAt = null.TimeFrom(now.Add(leaseExpiresIn))
+	}
+	store.jobs[job.ID] = job
+	store.executions[execution.ID] = execution
+	return execution
+}
+
+func TestReconcile_SettlesAbandonedExecutions(t *testing.T) {
+	store := &reconcilerStore{mockStore: newMockStore()}
+	maxRuntime := int32(30)
+	limited := database.Job{ID: uuid.New(), MaxRuntimeMinutes: &maxRuntime}
+	unlimited := database.Job{ID: uuid.New()}
+
+	live := runningExecution(store, unlimited, "worker-a", 5*time.Minute)
+	recentlyExpired := runningExecution(store, unlimited, "worker-a", -time.Minute)
+	listed := runningExecution(store, unlimited, "worker-b", -time.Hour)
+	unlisted := runningExecution(store, unlimited, "worker-b", -time.Hour)
+	unprovisioned := runningExecution(store, unlimited, "worker-b", -time.Hour)
+	unprovisioned.InstanceID = nil
+	store.executions[unprovisioned.ID] = unprovisioned
+	overdueWithoutLease := runningExecution(store, limited, "", 0)
+	withoutLease := runningExecution(store, unlimited, "", 0)
+
+	// The backend only sees this host's instances
+	backend := &fakeBackend{instances: []Instance{
+		{ID: *live.InstanceID, ExecutionID: live.ID},
+		{ID: *listed.InstanceID, ExecutionID: listed.ID},
+	}}
+
+	r := newTestReconciler(store, backend)
+	require.NoError(t, r.Reconcile(context.Background()))
+
+	// An instance that may still exist is torn down before the execution fails
+	for _, e := range []database.Execution{listed, unlisted, overdueWithoutLease} {
+		assert.Equal(t, database.ExecutionStatusCompletedError, store.executions[e.ID].Status)
+		assert.True(t, store.executions[e.ID].CompletedAt.Valid)
+		assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusOrphaned, database.ExecutionStatusCompletedError}, store.transitionsOf(e.ID))
+	}
+	assert.ElementsMatch(t, []string{*listed.InstanceID, *unlisted.InstanceID, *overdueWithoutLease.InstanceID}, backend.removed)
+
+	// Without an instance there is nothing to tear down
+	assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusCompletedError}, store.transitionsOf(unprovisioned.ID))
+
+	// Live leases, leases within the grace period and runs without a limit are left alone
+	for _, e := range []database.Execution{live, recentlyExpired, withoutLease} {
+		assert.Equal(t, database.ExecutionStatusRunning, store.executions[e.ID].Status)
+		assert.Empty(t, store.transitionsOf(e.ID))
+	}
+}
+
+func TestReconcile_FailsAbandonedExecutionsMissingFromCompleteList(t *testing.T) {
+	store := &reconcilerStore{mockStore: newMockStore()}
+	abandoned := runningExecution(store, database.Job{ID: uuid.New()}, "worker-a", -time.Hour)
+
+	// A backend that sees every replica's instances knows this one is gone
+	backend := &fakeBackend{complete: true}
+	r := newTestReconciler(store, backend)
+	require.NoError(t, r.Reconcile(context.Background()))
+
+	assert.Empty(t, backend.removed)
+	assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusCompletedError}, store.transitionsOf(abandoned.ID))
+}
+
+func TestReconcile_ReplicasLeaveEachOthersExecutionsAlone(t *testing.T) {
+	store := &reconcilerStore{mockStore: newMockStore()}
+	job := database.Job{ID: uuid.New()}
+
+	// Two live replicas, each seeing only its own instance, and one that crashed
+	onA := runningExecution(store, job, "worker-a", 5*time.Minute)
+	onB := runningExecution(store, job, "worker-b", 5*time.Minute)
+	crashed := runningExecution(store, job, "worker-c", -time.Hour)
+
+	backendA := &fakeBackend{instances: []Instance{{ID: *onA.InstanceID, ExecutionID: onA.ID}}}
+	backendB := &fakeBackend{instances: []Instance{{ID: *onB.InstanceID, ExecutionID: onB.ID}}}
+
+	for _, r := range []*Reconciler{newTestReconciler(store, backendA), newTestReconciler(store, backendB)} {
+		require.NoError(t, r.Reconcile(context.Background()))
+	}
+
+	for _, e := range []database.Execution{onA, onB} {
+		assert.Equal(t, database.ExecutionStatusRunning, store.executions[e.ID].Status)
+		assert.Empty(t, store.transitionsOf(e.ID))
+	}
+	assert.Empty(t, backendB.removed)
+
+	// The first reconciler settles the crashed replica's execution
+	assert.Equal(t, []string{*crashed.InstanceID}, backendA.removed)
+	assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusOrphaned, database.ExecutionStatusCompletedError}, store.transitionsOf(crashed.ID))
+}
+
+func TestReconciler_Backoff(t *testing.T) {
+	r := newTestReconciler(&reconcilerStore{mockStore: newMockStore()}, &fakeBackend{})
+
+	assert.Equal(t, time.Minute, r.backoff(0))
+	assert.Equal(t, 2*time.Minute, r.backoff(1))
+	assert.Equal(t, 32*time.Minute, r.backoff(5))
+	assert.Equal(t, time.Hour, r.backoff(6))
+	assert.Equal(t, time.Hour, r.backoff(100))
+}
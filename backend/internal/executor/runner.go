@@ -0,0 +1,572 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5"
+	"github.com/nouvadev/veridian/backend/internal/accounting"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+// Store is the subset of database.Querier used by the runner
+type Store interface {
+	ClaimDueExecutions(ctx context.Context, opts database.ClaimOptions) ([]database.Execution, error)
+	GetJobByID(ctx context.Context, id uuid.UUID) (database.Job, error)
+	RenewExecutionLease(ctx context.Context, arg database.RenewExecutionLeaseParams) (int64, error)
+	UpdateExecutionStart(ctx context.Context, arg database.UpdateExecutionStartParams) (database.Execution, error)
+	UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error)
+	UpdateExecutionInstance(ctx context.Context, arg database.UpdateExecutionInstanceParams) error
+	MarkExecutionOrphaned(ctx context.Context, arg database.MarkExecutionOrphanedParams) (int64, error)
+	NotifyExecutionEvent(ctx context.Context, notification database.ExecutionNotification) error
+}
+
+// ErrMaxRuntimeExceeded is returned when a workload runs longer than its job allows
+var ErrMaxRuntimeExceeded = errors.New("exceeded max runtime")
+
+// ErrLeaseLost is returned when the runner no longer holds an execution's lease by
+// the time its workload has started, so it may have been rescheduled elsewhere
+var ErrLeaseLost = errors.New("lease lost before the execution started")
+
+// Config holds runner configuration
+type Config struct {
+	WorkerID      string        // Lease owner identifier, unique per replica
+	PollInterval  time.Duration // How often due executions are polled
+	BatchSize     int32         // Maximum executions claimed per poll
+	LeaseDuration time.Duration // How long a claimed execution's lease lasts, renewed until it finishes
+	MaxConcurrent int           // Maximum executions running at once
+}
+
+// Runner claims scheduled executions once their planned start time has passed
+// and drives them through a backend
+type Runner struct {
+	store   Store
+	backend Backend
+	logs    logstore.Store
+	prices  *pricing.Estimator
+	energy  *accounting.Accountant
+	config  Config
+	now     func() time.Time
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewRunner creates a new runner. The log store, price estimator and accountant are
+// optional; without them, workload output, actual costs and energy use respectively
+// are not recorded.
+func NewRunner(store Store, backend Backend, logs logstore.Store, prices *pricing.Estimator, accountant *accounting.Accountant, config Config) *Runner {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 1
+	}
+
+	return &Runner{
+		store:   store,
+		backend: backend,
+		logs:    logs,
+		prices:  prices,
+		energy:  accountant,
+		config:  config,
+		now:     time.Now,
+		slots:   make(chan struct{}, config.MaxConcurrent),
+	}
+}
+
+// Run polls for due executions until the context is cancelled, then waits for
+// in-flight executions to be torn down
+func (r *Runner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		started, err := r.ProcessDue(ctx)
+		if err != nil {
+			log.Printf("executor: failed to process due executions: %v", err)
+		} else if started > 0 {
+			log.Printf("executor: started %d execution(s)", started)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.wg.Wait()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue claims as many due executions as there are free slots and runs each
+// in the background. It returns how many were launched.
+func (r *Runner) ProcessDue(ctx context.Context) (int, error) {
+	free := int32(cap(r.slots) - len(r.slots))
+	if free <= 0 {
+		return 0, nil
+	}
+
+	batch := r.config.BatchSize
+	if batch <= 0 || batch > free {
+		batch = free
+	}
+
+	executions, err := r.store.ClaimDueExecutions(ctx, database.ClaimOptions{
+		Owner:         r.config.WorkerID,
+		BatchSize:     batch,
+		LeaseDuration: r.config.LeaseDuration,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, execution := range executions {
+		r.slots <- struct{}{}
+		r.wg.Add(1)
+
+		go func(execution database.Execution) {
+			defer func() {
+				<-r.slots
+				r.wg.Done()
+			}()
+
+			if err := r.Execute(ctx, execution); err != nil {
+				log.Printf("executor: execution %s: %v", execution.ID, err)
+			}
+		}(execution)
+	}
+
+	return len(executions), nil
+}
+
+// Execute runs one execution to completion: provision, start, wait and tear down.
+// Failures before the workload exits, and workloads that outlive the job's max
+// runtime, mark the execution as completed_error. If ctx is cancelled while the
+// workload runs, the instance is torn down and the execution is left running for
+// the reconciler. If the lease was lost before the workload started, the instance
+// is torn down and ErrLeaseLost is returned without touching the execution.
+func (r *Runner) Execute(ctx context.Context, execution database.Execution) error {
+	job, err := r.store.GetJobByID(ctx, execution.JobID)
+	if err != nil {
+		return r.fail(ctx, &execution, fmt.Errorf("failed to load job: %w", err))
+	}
+
+	env, err := mergeEnv(job.EnvVars, execution.EnvVars)
+	if err != nil {
+		return r.fail(ctx, &execution, err)
+	}
+
+	// Output goes to the stored log and, line by line, to clients following the
+	// execution. Both are closed once the workload has exited, or after teardown if
+	// it failed.
+	output := r.createLog(ctx, execution)
+	stream := newLogStream(context.WithoutCancel(ctx), r.store, execution, job, r.now)
+	closeLog := sync.OnceValue(func() *string {
+		stream.Close()
+		return r.closeLog(execution, output)
+	})
+	defer closeLog()
+
+	var sink io.Writer = stream
+	if output != nil {
+		sink = io.MultiWriter(output, stream)
+	}
+
+	spec := Spec{
+		ExecutionID: execution.ID,
+		Image:       job.ImageUri,
+		Env:         env,
+		CPUCores:    int(job.CpuCores),
+		MemoryMB:    int(job.MemoryMb),
+		Output:      sink,
+	}
+	if execution.CloudRegion != nil {
+		spec.Region = *execution.CloudRegion
+	}
+	if execution.VmType != nil {
+		spec.VMType = *execution.VmType
+	}
+
+	// The lease is held until the execution finishes. Until it starts, that keeps it
+	// from being claimed again; once running, it tells reconcilers the runner is alive.
+	releaseLease := r.holdLease(ctx, execution)
+	defer releaseLease()
+
+	instance, err := r.backend.Provision(ctx, spec)
+	if err != nil {
+		return r.fail(ctx, &execution, fmt.Errorf("failed to provision: %w", err))
+	}
+
+	// Teardown must run even if the runner is shutting down. The closure sees the
+	// execution's latest status.
+	defer func() {
+		r.teardown(context.WithoutCancel(ctx), execution, instance)
+	}()
+
+	// The instance ID lets the reconciler find the instance if teardown fails
+	if err := r.store.UpdateExecutionInstance(ctx, database.UpdateExecutionInstanceParams{
+		ID:         execution.ID,
+		InstanceID: &instance.ID,
+	}); err != nil {
+		log.Printf("executor: execution %s: failed to record instance %s: %v", execution.ID, instance.ID, err)
+	}
+
+	if err := r.backend.Start(ctx, instance, spec); err != nil {
+		return r.fail(ctx, &execution, fmt.Errorf("failed to start: %w", err))
+	}
+
+	started, err := transition(execution, database.ExecutionStatusRunning, "started on instance "+instance.ID)
+	if err != nil {
+		return err
+	}
+
+	updated, err := r.store.UpdateExecutionStart(ctx, database.UpdateExecutionStartParams{
+		ID:        execution.ID,
+		StartedAt: null.TimeFrom(r.now()),
+		WorkerID:  r.config.WorkerID,
+		Actor:     started.Actor,
+		Reason:    started.Reason,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The deferred teardown stops the workload; whoever holds the lease now owns the execution
+		return ErrLeaseLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record start: %w", err)
+	}
+	execution = updated
+
+	result, err := r.wait(ctx, instance, maxRuntime(job))
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted while running: %w", err)
+		}
+		if errors.Is(err, ErrMaxRuntimeExceeded) {
+			// The deferred teardown stops the workload
+			return r.fail(ctx, &execution, err)
+		}
+		return r.fail(ctx, &execution, fmt.Errorf("failed waiting for workload: %w", err))
+	}
+
+	status := database.ExecutionStatusCompletedSuccess
+	if result.ExitCode != 0 {
+		status = database.ExecutionStatusCompletedError
+	}
+
+	completed, err := transition(execution, status, fmt.Sprintf("workload exited with code %d", result.ExitCode))
+	if err != nil {
+		return err
+	}
+
+	completedAt := r.now()
+	actual, baseline := r.account(ctx, execution, completedAt)
+	updated, err = r.store.UpdateExecutionComplete(ctx, database.UpdateExecutionCompleteParams{
+		ID:               execution.ID,
+		FromStatus:       completed.From,
+		Status:           completed.To,
+		CompletedAt:      null.TimeFrom(completedAt),
+		ExitCode:         null.IntFrom(int64(result.ExitCode)),
+		LogUri:           closeLog(),
+		CostActualUsd:    actual.CostUSD,
+		CarbonEmittedKg:  actual.CarbonKg,
+		EnergyKwh:        actual.EnergyKWh,
+		BaselineCostUsd:  baseline.CostUSD,
+		BaselineCarbonKg: baseline.CarbonKg,
+		Actor:            completed.Actor,
+		Reason:           completed.Reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record completion: %w", err)
+	}
+	execution = updated
+
+	return nil
+}
+
+// holdLease renews the runner's lease on an execution every third of the lease
+// duration until the returned function is called or ctx is cancelled. Renewal stops
+// early if the lease is no longer held: before the start, UpdateExecutionStart then
+// refuses to start the execution; after it, a reconciler has taken it over.
+func (r *Runner) holdLease(ctx context.Context, execution database.Execution) func() {
+	interval := r.config.LeaseDuration / 3
+	if interval <= 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			renewed, err := r.store.RenewExecutionLease(ctx, database.RenewExecutionLeaseParams{
+				ID:           execution.ID,
+				LeaseOwner:   r.config.WorkerID,
+				LeaseSeconds: int32(r.config.LeaseDuration / time.Second),
+			})
+			if err != nil {
+				log.Printf("executor: execution %s: failed to renew lease: %v", execution.ID, err)
+				continue
+			}
+			if renewed == 0 {
+				log.Printf("executor: execution %s: lease no longer held, stopped renewing", execution.ID)
+				return
+			}
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		close(stop)
+		<-done
+	})
+}
+
+// createLog opens the log the workload's output is streamed to. Without a log store,
+// or if the log cannot be created, output is discarded and nil is returned.
+func (r *Runner) createLog(ctx context.Context, execution database.Execution) io.WriteCloser {
+	if r.logs == nil {
+		return nil
+	}
+
+	// Stores that upload on close must be able to do so during shutdown
+	output, err := r.logs.Create(context.WithoutCancel(ctx), logstore.Key(execution.ID))
+	if err != nil {
+		log.Printf("executor: execution %s: failed to create log, output will be discarded: %v", execution.ID, err)
+		return nil
+	}
+	return output
+}
+
+// closeLog completes an execution's log and returns its URI, or nil if there is no
+// complete log to point to
+func (r *Runner) closeLog(execution database.Execution, output io.WriteCloser) *string {
+	if output == nil {
+		return nil
+	}
+
+	if err := output.Close(); err != nil {
+		log.Printf("executor: execution %s: failed to store log: %v", execution.ID, err)
+		return nil
+	}
+
+	uri := r.logs.URI(logstore.Key(execution.ID))
+	return &uri
+}
+
+// wait blocks until the workload exits. With a positive limit it gives up once the
+// workload has run that long and returns ErrMaxRuntimeExceeded.
+func (r *Runner) wait(ctx context.Context, instance Instance, limit time.Duration) (Result, error) {
+	if limit <= 0 {
+		return r.backend.Wait(ctx, instance)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	result, err := r.backend.Wait(waitCtx, instance)
+	if err != nil && ctx.Err() == nil && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		return Result{}, fmt.Errorf("%w of %s", ErrMaxRuntimeExceeded, limit)
+	}
+	return result, err
+}
+
+// maxRuntime returns how long a job's executions may run, 0 for no limit
+func maxRuntime(job database.Job) time.Duration {
+	if job.MaxRuntimeMinutes == nil {
+		return 0
+	}
+	return time.Duration(*job.MaxRuntimeMinutes) * time.Minute
+}
+
+// teardown releases an execution's instance. If that fails the execution is marked
+// orphaned so the reconciler retries the teardown.
+func (r *Runner) teardown(ctx context.Context, execution database.Execution, instance Instance) {
+	err := r.backend.Teardown(ctx, instance)
+	if err == nil {
+		return
+	}
+
+	log.Printf("executor: execution %s: failed to tear down %s: %v", execution.ID, instance.ID, err)
+
+	message := err.Error()
+	orphaned, err := transition(execution, database.ExecutionStatusOrphaned, fmt.Sprintf("teardown of instance %s failed: %s", instance.ID, message))
+	if err != nil {
+		// Failed before running; nothing was recorded that the reconciler could resume
+		log.Printf("executor: execution %s: cannot mark orphaned: %v", execution.ID, err)
+		return
+	}
+
+	if _, err := r.store.MarkExecutionOrphaned(ctx, database.MarkExecutionOrphanedParams{
+		ID:                execution.ID,
+		FromStatus:        orphaned.From,
+		InstanceID:        &instance.ID,
+		NextTeardownAt:    null.TimeFrom(r.now()),
+		LastTeardownError: &message,
+		Actor:             orphaned.Actor,
+		Reason:            orphaned.Reason,
+	}); err != nil {
+		log.Printf("executor: execution %s: failed to mark orphaned: %v", execution.ID, err)
+	}
+}
+
+// fail marks an execution as completed_error, updating it in place, and returns cause
+func (r *Runner) fail(ctx context.Context, execution *database.Execution, cause error) error {
+	failed, err := transition(*execution, database.ExecutionStatusCompletedError, cause.Error())
+	if err != nil {
+		return fmt.Errorf("%w (and cannot record failure: %v)", cause, err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	completedAt := r.now()
+	actual, baseline := r.account(ctx, *execution, completedAt)
+	updated, err := r.store.UpdateExecutionComplete(ctx, database.UpdateExecutionCompleteParams{
+		ID:               execution.ID,
+		FromStatus:       failed.From,
+		Status:           failed.To,
+		CompletedAt:      null.TimeFrom(completedAt),
+		CostActualUsd:    actual.CostUSD,
+		CarbonEmittedKg:  actual.CarbonKg,
+		EnergyKwh:        actual.EnergyKWh,
+		BaselineCostUsd:  baseline.CostUSD,
+		BaselineCarbonKg: baseline.CarbonKg,
+		Actor:            failed.Actor,
+		Reason:           failed.Reason,
+	})
+	if err != nil {
+		return fmt.Errorf("%w (and failed to record failure: %v)", cause, err)
+	}
+	*execution = updated
+
+	return cause
+}
+
+// usage is what running a VM type in a region over an interval cost and emitted
+type usage struct {
+	CostUSD   null.Float
+	EnergyKWh null.Float
+	CarbonKg  null.Float
+}
+
+// account measures the time an execution ran, from started_at until completedAt, and
+// the same runtime in its baseline placement: the default region, started at
+// submission time. Executions that never started have neither.
+func (r *Runner) account(ctx context.Context, execution database.Execution, completedAt time.Time) (actual, baseline usage) {
+	if !execution.StartedAt.Valid {
+		return usage{}, usage{}
+	}
+	startedAt := execution.StartedAt.Time
+
+	if execution.CloudRegion != nil && execution.VmType != nil {
+		actual = r.measure(ctx, execution, *execution.CloudRegion, *execution.VmType, startedAt, completedAt)
+	}
+	if execution.BaselineRegion != nil && execution.BaselineVmType != nil && execution.BaselineStartAt.Valid {
+		from := execution.BaselineStartAt.Time
+		baseline = r.measure(ctx, execution, *execution.BaselineRegion, *execution.BaselineVmType, from, from.Add(completedAt.Sub(startedAt)))
+	}
+
+	return actual, baseline
+}
+
+// measure prices running a VM type in a region over [from, to] and estimates the
+// energy drawn and the carbon emitted producing it, using the grid intensity over
+// that interval. Figures that cannot be computed, such as prices missing from the
+// catalogue or intensity without data, are left unset.
+func (r *Runner) measure(ctx context.Context, execution database.Execution, region, vmType string, from, to time.Time) usage {
+	var u usage
+
+	if r.prices != nil {
+		cost, err := r.prices.Actual(region, vmType, from, to)
+		if err != nil {
+			log.Printf("executor: execution %s: no cost for %s in %s: %v", execution.ID, vmType, region, err)
+		} else {
+			u.CostUSD = null.FloatFrom(cost)
+		}
+	}
+
+	if r.energy == nil {
+		return u
+	}
+
+	kwh, err := r.energy.Energy(region, vmType, to.Sub(from))
+	if err != nil {
+		log.Printf("executor: execution %s: no energy estimate for %s in %s: %v", execution.ID, vmType, region, err)
+		return u
+	}
+	u.EnergyKWh = null.FloatFrom(kwh)
+
+	kg, err := r.energy.Emissions(ctx, region, kwh, from, to)
+	if err != nil {
+		if !errors.Is(err, accounting.ErrNoProvider) {
+			log.Printf("executor: execution %s: no emissions estimate for %s: %v", execution.ID, region, err)
+		}
+		return u
+	}
+	u.CarbonKg = null.FloatFrom(kg)
+
+	return u
+}
+
+// transition builds an executor transition of execution to status and checks it
+// against the execution state machine before anything is written
+func transition(execution database.Execution, to database.ExecutionStatus, reason string) (database.Transition, error) {
+	t := database.Transition{
+		ExecutionID: execution.ID,
+		From:        execution.Status,
+		To:          to,
+		Actor:       database.ExecutionActorExecutor,
+		Reason:      reason,
+	}
+	return t, t.Validate()
+}
+
+// mergeEnv combines the job's environment variables with per-run overrides.
+// Non-string JSON values are converted to their JSON text.
+func mergeEnv(jobEnv, overrides []byte) (map[string]string, error) {
+	env := make(map[string]string)
+
+	for _, source := range [][]byte{jobEnv, overrides} {
+		if len(source) == 0 {
+			continue
+		}
+
+		var values map[string]interface{}
+		if err := json.Unmarshal(source, &values); err != nil {
+			return nil, fmt.Errorf("invalid environment variables: %w", err)
+		}
+
+		for key, value := range values {
+			switch v := value.(type) {
+			case string:
+				env[key] = v
+			case nil:
+				env[key] = ""
+			default:
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid environment variable %s: %w", key, err)
+				}
+				env[key] = string(encoded)
+			}
+		}
+	}
+
+	return env, nil
+}
@@ -0,0 +1,586 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/accounting"
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+// mockStore is an in-memory implementation of Store
+type mockStore struct {
+	jobs       map[uuid.UUID]database.Job
+	executions map[uuid.UUID]database.Execution
+	events     []database.CreateExecutionEventParams
+
+	// Log notifications and lease renewals happen on background goroutines
+	mu            sync.Mutex
+	notifications []database.ExecutionNotification
+	renewals      int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		jobs:       make(map[uuid.UUID]database.Job),
+		executions: make(map[uuid.UUID]database.Execution),
+	}
+}
+
+func (m *mockStore) ClaimDueExecutions(ctx context.Context, opts database.ClaimOptions) ([]database.Execution, error) {
+	return nil, nil
+}
+
+func (m *mockStore) GetJobByID(ctx context.Context, id uuid.UUID) (database.Job, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return database.Job{}, errors.New("job not found")
+	}
+	return job, nil
+}
+
+func (m *mockStore) RenewExecutionLease(ctx context.Context, arg database.RenewExecutionLeaseParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.executions[arg.ID]
+	running := e.Status == database.ExecutionStatusRunning && e.LeaseOwner != nil && *e.LeaseOwner == arg.LeaseOwner
+	if !running && !m.holdsLease(e, arg.LeaseOwner) {
+		return 0, nil
+	}
+	e.LeaseExpiresAt = null.TimeFrom(time.Now().Add(time.Duration(arg.LeaseSeconds) * time.Second))
+	m.executions[arg.ID] = e
+	m.renewals++
+	return 1, nil
+}
+
+func (m *mockStore) UpdateExecutionStart(ctx context.Context, arg database.UpdateExecutionStartParams) (database.Execution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.executions[arg.ID]
+	if !m.holdsLease(e, arg.WorkerID) {
+		return database.Execution{}, pgx.ErrNoRows
+	}
+	e.Status = database.ExecutionStatusRunning
+	e.StartedAt = arg.StartedAt
+	m.executions[arg.ID] = e
+	m.record(arg.ID, database.ExecutionStatusEvaluating, e.Status, arg.Actor, arg.Reason)
+	return e, nil
+}
+
+func (m *mockStore) UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.executions[arg.ID]
+	if e.Status != arg.FromStatus {
+		return database.Execution{}, pgx.ErrNoRows
+	}
+	e.Status = arg.Status
+	e.CompletedAt = arg.CompletedAt
+	e.LeaseOwner = nil
+	e.LeaseExpiresAt = null.Time{}
+	e.ExitCode = arg.ExitCode
+	e.LogUri = arg.LogUri
+	e.CostActualUsd = arg.CostActualUsd
+	e.CarbonEmittedKg = arg.CarbonEmittedKg
+	e.EnergyKwh = arg.EnergyKwh
+	e.BaselineCostUsd = arg.BaselineCostUsd
+	e.BaselineCarbonKg = arg.BaselineCarbonKg
+	m.executions[arg.ID] = e
+	m.record(arg.ID, arg.FromStatus, arg.Status, arg.Actor, arg.Reason)
+	return e, nil
+}
+
+func (m *mockStore) NotifyExecutionEvent(ctx context.Context, notification database.ExecutionNotification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+// logLines returns the output lines published for an execution
+func (m *mockStore) logLines(executionID uuid.UUID) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var lines []string
+	for _, n := range m.notifications {
+		if n.ExecutionID == executionID {
+			lines = append(lines, n.Lines...)
+		}
+	}
+	return lines
+}
+
+func (m *mockStore) UpdateExecutionInstance(ctx context.Context, arg database.UpdateExecutionInstanceParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := m.executions[arg.ID]
+	e.InstanceID = arg.InstanceID
+	m.executions[arg.ID] = e
+	return nil
+}
+
+func (m *mockStore) MarkExecutionOrphaned(ctx context.Context, arg database.MarkExecutionOrphanedParams) (int64, error) {
+	e, ok := m.executions[arg.ID]
+	if !ok || e.Status != arg.FromStatus {
+		return 0, nil
+	}
+	e.Status = database.ExecutionStatusOrphaned
+	e.InstanceID = arg.InstanceID
+	e.LeaseOwner = nil
+	e.LeaseExpiresAt = null.Time{}
+	e.NextTeardownAt = arg.NextTeardownAt
+	e.LastTeardownError = arg.LastTeardownError
+	m.executions[arg.ID] = e
+	m.record(arg.ID, arg.FromStatus, e.Status, arg.Actor, arg.Reason)
+	return 1, nil
+}
+
+// record stores a transition, as the status-changing queries do
+// holdsLease reports whether owner holds an unexpired lease on an evaluating execution
+func (m *mockStore) holdsLease(e database.Execution, owner string) bool {
+	return e.Status == database.ExecutionStatusEvaluating &&
+		e.LeaseOwner != nil && *e.LeaseOwner == owner &&
+		e.LeaseExpiresAt.Time.After(time.Now())
+}
+
+func (m *mockStore) record(id uuid.UUID, from, to database.ExecutionStatus, actor database.ExecutionActor, reason string) {
+	m.events = append(m.events, database.Transition{ExecutionID: id, From: from, To: to, Actor: actor, Reason: reason}.Event())
+}
+
+// transitionsOf returns the statuses an execution moved through, in order
+func (m *mockStore) transitionsOf(id uuid.UUID) []database.ExecutionStatus {
+	var statuses []database.ExecutionStatus
+	for _, event := range m.events {
+		if event.ExecutionID == id {
+			statuses = append(statuses, event.ToStatus)
+		}
+	}
+	return statuses
+}
+
+// failingBackend fails to provision
+type failingBackend struct{ LocalBackend }
+
+func (b *failingBackend) Provision(ctx context.Context, spec Spec) (Instance, error) {
+	return Instance{}, errors.New("no capacity")
+}
+
+func newTestRunner(t *testing.T, store Store, backend Backend) *Runner {
+	logs, err := logstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	r := NewRunner(store, backend, logs, nil, nil, Config{WorkerID: "test-executor", PollInterval: time.Second, BatchSize: 5, LeaseDuration: time.Minute})
+	r.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+	return r
+}
+
+func seedExecution(store *mockStore, image, jobEnv, runEnv string) database.Execution {
+	region := "westeurope"
+	job := database.Job{ID: uuid.New(), ImageUri: image, EnvVars: []byte(jobEnv)}
+	owner := "test-executor"
+	execution := database.Execution{
+		ID:             uuid.New(),
+		JobID:          job.ID,
+		Status:         database.ExecutionStatusEvaluating,
+		CloudRegion:    &region,
+		LeaseOwner:     &owner,
+		LeaseExpiresAt: null.TimeFrom(time.Now().Add(time.Minute)),
+	}
+	if runEnv != "" {
+		execution.EnvVars = []byte(runEnv)
+	}
+	store.jobs[job.ID] = job
+	store.executions[execution.ID] = execution
+	return execution
+}
+
+func TestExecute_RecordsLifecycle(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		image    string
+		status   database.ExecutionStatus
+		exitCode int64
+	}{
+		{"success", `test "$MODE" = nightly`, database.ExecutionStatusCompletedSuccess, 0},
+		{"failure", `test "$MODE" = hourly`, database.ExecutionStatusCompletedError, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The run override wins over the job definition
+			execution := seedExecution(store, tt.image, `{"MODE": "hourly"}`, `{"MODE": "nightly"}`)
+
+			r := newTestRunner(t, store, backend)
+			require.NoError(t, r.Execute(context.Background(), execution))
+
+			result := store.executions[execution.ID]
+			assert.Equal(t, tt.status, result.Status)
+			assert.True(t, result.StartedAt.Valid)
+			assert.True(t, result.CompletedAt.Valid)
+			assert.Equal(t, tt.exitCode, result.ExitCode.Int64)
+			require.NotNil(t, result.LogUri)
+			require.NotNil(t, result.InstanceID)
+			assert.Equal(t, "veridian-"+execution.ID.String(), *result.InstanceID)
+			assert.Equal(t, []database.ExecutionStatus{database.ExecutionStatusRunning, tt.status}, store.transitionsOf(execution.ID))
+		})
+	}
+}
+
+func TestExecute_StoresOutput(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	execution := seedExecution(store, `echo "hello $MODE"; echo oops >&2`, `{"MODE": "nightly"}`, "")
+
+	r := newTestRunner(t, store, backend)
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	key := logstore.Key(execution.ID)
+	result := store.executions[execution.ID]
+	require.NotNil(t, result.LogUri)
+	assert.Equal(t, r.logs.URI(key), *result.LogUri)
+
+	object, err := r.logs.Open(context.Background(), key)
+	require.NoError(t, err)
+	defer object.Close()
+
+	output, err := io.ReadAll(object)
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "hello nightly")
+	assert.Contains(t, string(output), "oops")
+
+	// Lines are also published for clients following the execution live
+	assert.ElementsMatch(t, []string{"hello nightly", "oops"}, store.logLines(execution.ID))
+}
+
+func TestExecute_RecordsActualCost(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	vmType := "Standard_D2s_v5"
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.VmType = &vmType
+	store.executions[execution.ID] = execution
+
+	catalogue, err := pricing.LoadJSON(strings.NewReader(`{"version": "test", "prices": [
+		{"region": "westeurope", "vm_type": "Standard_D2s_v5", "on_demand": 0.096}
+	]}`))
+	require.NoError(t, err)
+
+	r := newTestRunner(t, store, backend)
+	r.prices, err = pricing.NewEstimator(catalogue, pricing.OnDemand)
+	require.NoError(t, err)
+
+	// The workload runs for 30 minutes between start and completion
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now := clock
+		clock = clock.Add(30 * time.Minute)
+		return now
+	}
+
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusCompletedSuccess, result.Status)
+	assert.Equal(t, 0.048, result.CostActualUsd.Float64)
+}
+
+// flatProvider reports the same intensity at every point in time
+type flatProvider float64
+
+func (p flatProvider) Current(ctx context.Context, region string) (carbon.Intensity, error) {
+	return carbon.Intensity{Region: region, GramsPerKWh: float64(p)}, nil
+}
+
+func (p flatProvider) Forecast(ctx context.Context, region string, from, to time.Time) ([]carbon.Intensity, error) {
+	return []carbon.Intensity{{Region: region, Timestamp: from, GramsPerKWh: float64(p)}}, nil
+}
+
+// testAccountant draws 8 W, including a PUE of 1.5, on a Standard_D2s_v5
+func testAccountant(t *testing.T, provider carbon.Provider) *accounting.Accountant {
+	model, err := accounting.LoadJSON(strings.NewReader(`{
+		"version": "test", "utilisation": 0.5, "default_pue": 1.5,
+		"default_profile": {"idle_watts_per_vcpu": 1, "max_watts_per_vcpu": 3, "memory_watts_per_gb": 0.5}
+	}`))
+	require.NoError(t, err)
+	return accounting.NewAccountant(model, pricing.Default(), provider)
+}
+
+func TestExecute_RecordsEnergyAndEmissions(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	vmType := "Standard_D2s_v5"
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.VmType = &vmType
+	store.executions[execution.ID] = execution
+
+	r := newTestRunner(t, store, backend)
+	r.energy = testAccountant(t, flatProvider(200))
+
+	// The workload runs for 2 hours between start and completion
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now := clock
+		clock = clock.Add(2 * time.Hour)
+		return now
+	}
+
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	// 2 vCPU at 2 W plus 8 GB at 0.5 W is 8 W, times a PUE of 1.5, for 2 hours
+	result := store.executions[execution.ID]
+	assert.Equal(t, 0.024, result.EnergyKwh.Float64)
+	assert.Equal(t, 0.0048, result.CarbonEmittedKg.Float64)
+}
+
+func TestExecute_RecordsBaseline(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	// Deferred from 08:00 to the cleaner grid at 12:00, and moved to a cheaper region
+	vmType, baselineRegion := "Standard_D2s_v5", "westeurope"
+	region := "northeurope"
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.CloudRegion = &region
+	execution.VmType = &vmType
+	execution.BaselineRegion = &baselineRegion
+	execution.BaselineVmType = &vmType
+	execution.BaselineStartAt = null.TimeFrom(time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC))
+	store.executions[execution.ID] = execution
+
+	provider, err := carbon.LoadCSV(strings.NewReader(`region,timestamp,g_co2_per_kwh
+westeurope,2025-01-01T08:00:00Z,400
+northeurope,2025-01-01T12:00:00Z,100
+`))
+	require.NoError(t, err)
+
+	r := newTestRunner(t, store, backend)
+	r.prices, err = pricing.NewEstimator(pricing.Default(), pricing.OnDemand)
+	require.NoError(t, err)
+	r.energy = testAccountant(t, provider)
+
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now := clock
+		clock = clock.Add(2 * time.Hour)
+		return now
+	}
+
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	// The same two hour runtime is measured from 08:00 in the default region
+	result := store.executions[execution.ID]
+	assert.Equal(t, 0.1766, result.CostActualUsd.Float64)
+	assert.Equal(t, 0.192, result.BaselineCostUsd.Float64)
+	assert.Equal(t, 0.0024, result.CarbonEmittedKg.Float64)
+	assert.Equal(t, 0.0096, result.BaselineCarbonKg.Float64)
+}
+
+func TestExecute_ProvisionFailureMarksError(t *testing.T) {
+	store := newMockStore()
+	execution := seedExecution(store, "true", `{}`, "")
+
+	r := newTestRunner(t, store, &failingBackend{})
+	err := r.Execute(context.Background(), execution)
+	assert.Error(t, err)
+
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusCompletedError, result.Status)
+	assert.False(t, result.StartedAt.Valid)
+	assert.False(t, result.ExitCode.Valid)
+
+	require.Len(t, store.events, 1)
+	assert.Equal(t, database.ExecutionStatusEvaluating, store.events[0].FromStatus.ExecutionStatus)
+	assert.Equal(t, database.ExecutionActorExecutor, store.events[0].Actor)
+	assert.Equal(t, "failed to provision: no capacity", store.events[0].Reason)
+}
+
+func TestExecute_RejectsIllegalTransition(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	// An execution that already finished must not be run again
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.Status = database.ExecutionStatusCompletedSuccess
+	store.executions[execution.ID] = execution
+
+	r := newTestRunner(t, store, backend)
+	err = r.Execute(context.Background(), execution)
+	assert.ErrorIs(t, err, database.ErrIllegalTransition)
+
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusCompletedSuccess, result.Status)
+	assert.False(t, result.StartedAt.Valid)
+	assert.Empty(t, store.events)
+}
+
+// slowBackend runs workloads locally after a slow provisioning step
+type slowBackend struct {
+	*LocalBackend
+	delay time.Duration
+}
+
+func (b *slowBackend) Provision(ctx context.Context, spec Spec) (Instance, error) {
+	time.Sleep(b.delay)
+	return b.LocalBackend.Provision(ctx, spec)
+}
+
+func TestExecute_RenewsLeaseWhileProvisioning(t *testing.T) {
+	store := newMockStore()
+	local, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	// Provisioning outlasts the lease the execution was claimed with
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.LeaseExpiresAt = null.TimeFrom(time.Now().Add(600 * time.Millisecond))
+	store.executions[execution.ID] = execution
+
+	r := newTestRunner(t, store, &slowBackend{local, 900 * time.Millisecond})
+	r.config.LeaseDuration = 1500 * time.Millisecond
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	assert.Positive(t, store.renewals)
+	assert.Equal(t, database.ExecutionStatusCompletedSuccess, store.executions[execution.ID].Status)
+}
+
+func TestExecute_RefusesToStartWithoutLease(t *testing.T) {
+	store := newMockStore()
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	// The lease expired and another executor claimed the execution
+	other := "other-executor"
+	execution := seedExecution(store, "true", `{}`, "")
+	execution.LeaseOwner = &other
+	store.executions[execution.ID] = execution
+
+	r := newTestRunner(t, store, backend)
+	err = r.Execute(context.Background(), execution)
+	assert.ErrorIs(t, err, ErrLeaseLost)
+
+	// The other executor's claim is untouched and this instance is gone
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusEvaluating, result.Status)
+	assert.Equal(t, &other, result.LeaseOwner)
+	assert.Empty(t, store.events)
+
+	instances, err := backend.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
+
+// stuckBackend runs workloads locally but cannot tear them down
+type stuckBackend struct{ *LocalBackend }
+
+func (b *stuckBackend) Teardown(ctx context.Context, instance Instance) error {
+	return errors.New("instance is stuck")
+}
+
+func TestExecute_TeardownFailureMarksOrphaned(t *testing.T) {
+	store := newMockStore()
+	local, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+	execution := seedExecution(store, "true", `{}`, "")
+
+	r := newTestRunner(t, store, &stuckBackend{local})
+	require.NoError(t, r.Execute(context.Background(), execution))
+
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusOrphaned, result.Status)
+	assert.Equal(t, int64(0), result.ExitCode.Int64)
+	require.NotNil(t, result.LastTeardownError)
+	assert.Equal(t, "instance is stuck", *result.LastTeardownError)
+	assert.Equal(t, r.now(), result.NextTeardownAt.Time)
+	assert.Equal(t, []database.ExecutionStatus{
+		database.ExecutionStatusRunning,
+		database.ExecutionStatusCompletedSuccess,
+		database.ExecutionStatusOrphaned,
+	}, store.transitionsOf(execution.ID))
+}
+
+// unrecordedStore fails to record completions
+type unrecordedStore struct{ *mockStore }
+
+func (m *unrecordedStore) UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error) {
+	return database.Execution{}, errors.New("connection reset")
+}
+
+func TestExecute_TeardownFailureAfterUnrecordedCompletion(t *testing.T) {
+	store := newMockStore()
+	local, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+	execution := seedExecution(store, "true", `{}`, "")
+
+	r := newTestRunner(t, &unrecordedStore{store}, &stuckBackend{local})
+	err = r.Execute(context.Background(), execution)
+	assert.ErrorContains(t, err, "failed to record completion")
+
+	// The failed teardown is still recorded so the reconciler retries it
+	result := store.executions[execution.ID]
+	assert.Equal(t, database.ExecutionStatusOrphaned, result.Status)
+	require.NotNil(t, result.InstanceID)
+	assert.Equal(t, "veridian-"+execution.ID.String(), *result.InstanceID)
+	assert.Equal(t, []database.ExecutionStatus{
+		database.ExecutionStatusRunning,
+		database.ExecutionStatusOrphaned,
+	}, store.transitionsOf(execution.ID))
+}
+
+func TestExecute_StopsAtMaxRuntime(t *testing.T) {
+	backend, err := NewLocalBackend(RuntimeProcess)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	spec := Spec{ExecutionID: uuid.New(), Image: "sleep 30"}
+	instance, err := backend.Provision(ctx, spec)
+	require.NoError(t, err)
+	defer backend.Teardown(ctx, instance)
+	require.NoError(t, backend.Start(ctx, instance, spec))
+
+	r := newTestRunner(t, newMockStore(), backend)
+	_, err = r.wait(ctx, instance, 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrMaxRuntimeExceeded)
+
+	limit := int32(90)
+	assert.Equal(t, 90*time.Minute, maxRuntime(database.Job{MaxRuntimeMinutes: &limit}))
+	assert.Zero(t, maxRuntime(database.Job{}))
+}
+
+func TestMergeEnv(t *testing.T) {
+	env, err := mergeEnv([]byte(`{"A": "1", "B": 2, "C": true}`), []byte(`{"A": "override"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "override", "B": "2", "C": "true"}, env)
+
+	_, err = mergeEnv([]byte(`not json`), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// GetOrphanedExecutions handles GET /admin/executions/orphaned
+func GetOrphanedExecutions(c *gin.Context, app *app.App) {
+	executions, err := app.Queries.GetExecutionsByStatus(c.Request.Context(), database.ExecutionStatusOrphaned)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch orphaned executions",
+		})
+		return
+	}
+
+	apiExecutions := make([]models.OrphanedExecution, len(executions))
+	for i, execution := range executions {
+		apiExecutions[i] = toAPIOrphanedExecution(execution)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"executions": apiExecutions,
+	})
+}
+
+// toAPIOrphanedExecution adds the reconciler's teardown state to an execution
+func toAPIOrphanedExecution(execution database.Execution) models.OrphanedExecution {
+	return models.OrphanedExecution{
+		Execution:         toAPIExecution(execution),
+		InstanceID:        execution.InstanceID,
+		TeardownAttempts:  execution.TeardownAttempts,
+		NextTeardownAt:    execution.NextTeardownAt.Ptr(),
+		LastTeardownError: execution.LastTeardownError,
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestToAPIOrphanedExecution(t *testing.T) {
+	instanceID := "veridian-1234"
+	lastError := "instance is stuck"
+	nextTeardown := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	execution := database.Execution{
+		ID:                uuid.New(),
+		JobID:             uuid.New(),
+		Status:            database.ExecutionStatusOrphaned,
+		InstanceID:        &instanceID,
+		TeardownAttempts:  3,
+		NextTeardownAt:    null.TimeFrom(nextTeardown),
+		LastTeardownError: &lastError,
+	}
+
+	body, err := json.Marshal(toAPIOrphanedExecution(execution))
+	require.NoError(t, err)
+
+	// Execution fields are flattened alongside the teardown state
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, execution.ID.String(), decoded["id"])
+	assert.Equal(t, "orphaned", decoded["status"])
+	assert.Equal(t, instanceID, decoded["instance_id"])
+	assert.Equal(t, 3.0, decoded["teardown_attempts"])
+	assert.Equal(t, "2025-01-01T12:00:00Z", decoded["next_teardown_at"])
+	assert.Equal(t, lastError, decoded["last_teardown_error"])
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/auth"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// CreateAPIKeyHandler handles POST /auth/api-keys. The response is the only time
+// the key is shown; only its hash is stored.
+func CreateAPIKeyHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid API key scopes",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var expiresAt pgtype.Timestamptz
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid API key expiry",
+				"details": "expires_at must be in the future",
+			})
+			return
+		}
+		expiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate API key",
+		})
+		return
+	}
+
+	apiKey, err := app.Queries.CreateAPIKey(c.Request.Context(), database.CreateAPIKeyParams{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create API key",
+		})
+		return
+	}
+
+	response := toAPIKey(apiKey)
+	response.Key = key
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetAPIKeysHandler handles GET /auth/api-keys, listing the caller's active and
+// expired keys, newest first. Revoked keys are not listed.
+func GetAPIKeysHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	apiKeys, err := app.Queries.ListUserAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch API keys",
+		})
+		return
+	}
+
+	response := make([]models.APIKey, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		response[i] = toAPIKey(apiKey)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": response,
+	})
+}
+
+// RevokeAPIKeyHandler handles DELETE /auth/api-keys/:id. The key stops working
+// immediately.
+func RevokeAPIKeyHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	apiKeyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid API key ID format",
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	revoked, err := app.Queries.RevokeAPIKey(c.Request.Context(), database.RevokeAPIKeyParams{
+		ID:     apiKeyID,
+		UserID: userID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke API key",
+		})
+		return
+	}
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "API key not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// normalizeAPIKeyScopes validates requested scopes and removes duplicates
+func normalizeAPIKeyScopes(requested []string) ([]string, error) {
+	scopes := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		if !auth.Scope(scope).Valid() {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// toAPIKey converts a database API key to its API representation, without the key
+func toAPIKey(apiKey database.ApiKey) models.APIKey {
+	response := models.APIKey{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if response.Scopes == nil {
+		response.Scopes = []string{}
+	}
+	if apiKey.ExpiresAt.Valid {
+		response.ExpiresAt = &apiKey.ExpiresAt.Time
+	}
+	if apiKey.LastUsedAt.Valid {
+		response.LastUsedAt = &apiKey.LastUsedAt.Time
+	}
+	if apiKey.LastUsedIp != nil {
+		ip := apiKey.LastUsedIp.String()
+		response.LastUsedIP = &ip
+	}
+	return response
+}
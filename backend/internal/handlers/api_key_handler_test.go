@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestNormalizeAPIKeyScopes(t *testing.T) {
+	scopes, err := normalizeAPIKeyScopes([]string{"jobs:write", "jobs:read", "jobs:write"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jobs:write", "jobs:read"}, scopes)
+
+	_, err = normalizeAPIKeyScopes([]string{"jobs:read", "admin"})
+	assert.ErrorContains(t, err, `unknown scope "admin"`)
+}
+
+func TestToAPIKey(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ip := netip.MustParseAddr("192.0.2.10")
+
+	apiKey := toAPIKey(database.ApiKey{
+		ID:         uuid.New(),
+		Name:       "ci",
+		Prefix:     "vrd_0123abcd",
+		KeyHash:    "hash",
+		Scopes:     []string{"jobs:write"},
+		ExpiresAt:  pgtype.Timestamptz{Time: now.Add(24 * time.Hour), Valid: true},
+		LastUsedAt: pgtype.Timestamptz{Time: now, Valid: true},
+		LastUsedIp: &ip,
+		CreatedAt:  now.Add(-time.Hour),
+	})
+
+	assert.Equal(t, "vrd_0123abcd", apiKey.Prefix)
+	assert.Empty(t, apiKey.Key)
+	require.NotNil(t, apiKey.ExpiresAt)
+	assert.Equal(t, now.Add(24*time.Hour), *apiKey.ExpiresAt)
+	require.NotNil(t, apiKey.LastUsedAt)
+	assert.Equal(t, now, *apiKey.LastUsedAt)
+	require.NotNil(t, apiKey.LastUsedIP)
+	assert.Equal(t, "192.0.2.10", *apiKey.LastUsedIP)
+
+	// Keys that never expire and were never used
+	apiKey = toAPIKey(database.ApiKey{ID: uuid.New(), Scopes: []string{"jobs:read"}})
+	assert.Nil(t, apiKey.ExpiresAt)
+	assert.Nil(t, apiKey.LastUsedAt)
+	assert.Nil(t, apiKey.LastUsedIP)
+}
+
+func TestCreateAPIKeyHandler_InvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing scopes", `{"name":"ci"}`},
+		{"unknown scope", `{"name":"ci","scopes":["jobs:delete"]}`},
+		{"past expiry", `{"name":"ci","scopes":["jobs:read"],"expires_at":"2020-01-01T00:00:00Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/api-keys", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", uuid.New())
+
+			CreateAPIKeyHandler(c, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
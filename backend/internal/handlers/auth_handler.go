@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"log"
 	"net/http"
 	"net/netip"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/nouvadev/veridian/backend/internal/app"
 	"github.com/nouvadev/veridian/backend/internal/auth"
@@ -61,7 +65,7 @@ func RegisterHandler(c *gin.Context, app *app.App) {
 	user, err := app.Queries.CreateUser(ctx, database.CreateUserParams{
 		Email:          req.Email,
 		HashedPassword: hashedPassword,
-		EmailVerified:  false, // Will be verified later via email
+		EmailVerified:  false, // Verified through the emailed link
 		IsActive:       true,
 	})
 	if err != nil {
@@ -71,9 +75,16 @@ func RegisterHandler(c *gin.Context, app *app.App) {
 		return
 	}
 
-	// Generate tokens
+	// Registration succeeds even if the email cannot be sent; the user can
+	// request another from POST /api/v1/auth/resend-verification
+	if err := sendVerificationEmail(ctx, app, user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
+	// Generate tokens; each login starts a new session (refresh token family)
+	sessionID := uuid.New()
 	jwtManager := app.JWTManager
-	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email)
+	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email, sessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to generate access token",
@@ -100,6 +111,7 @@ func RegisterHandler(c *gin.Context, app *app.App) {
 		ExpiresAt: pgtype.Timestamptz{Time: refreshExpiresAt, Valid: true},
 		UserAgent: &userAgent,
 		IpAddress: parseIPToNetip(c.ClientIP()),
+		FamilyID:  sessionID,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -162,9 +174,10 @@ func LoginHandler(c *gin.Context, app *app.App) {
 		return
 	}
 
-	// Generate tokens
+	// Generate tokens; each login starts a new session (refresh token family)
+	sessionID := uuid.New()
 	jwtManager := app.JWTManager
-	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email)
+	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email, sessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to generate access token",
@@ -191,6 +204,7 @@ func LoginHandler(c *gin.Context, app *app.App) {
 		ExpiresAt: pgtype.Timestamptz{Time: refreshExpiresAt, Valid: true},
 		UserAgent: &userAgent,
 		IpAddress: parseIPToNetip(c.ClientIP()),
+		FamilyID:  sessionID,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -244,12 +258,51 @@ func RefreshTokenHandler(c *gin.Context, app *app.App) {
 		return
 	}
 
-	// Check if refresh token exists in database
+	// Look up the refresh token whatever its state, locking it so concurrent
+	// refreshes with the same token cannot both rotate it
 	tokenHash := sha256.Sum256([]byte(req.RefreshToken))
 	tokenHashStr := hex.EncodeToString(tokenHash[:])
 
-	storedToken, err := app.Queries.GetRefreshToken(ctx, tokenHashStr)
+	tx, err := app.DB.Begin(ctx)
 	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to refresh token",
+		})
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	queries := app.Queries.WithTx(tx)
+
+	storedToken, err := queries.GetRefreshTokenForUpdate(ctx, tokenHashStr)
+	if err != nil || storedToken.UserID != userID {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired refresh token",
+		})
+		return
+	}
+
+	// A rotated token is never presented again by its legitimate holder, so
+	// either it or its successor has been stolen. Revoke the whole family.
+	if storedToken.RotatedAt.Valid {
+		err := revokeReusedTokenFamily(ctx, queries, c, storedToken)
+		if err == nil {
+			err = tx.Commit(ctx)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to refresh token",
+			})
+			return
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Refresh token reuse detected. Please log in again.",
+		})
+		return
+	}
+
+	if storedToken.IsRevoked || !storedToken.ExpiresAt.Time.After(time.Now()) {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "Invalid or expired refresh token",
 		})
@@ -257,7 +310,7 @@ func RefreshTokenHandler(c *gin.Context, app *app.App) {
 	}
 
 	// Get user details
-	user, err := app.Queries.GetUserByID(ctx, userID)
+	user, err := queries.GetUserByID(ctx, userID)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "User not found",
@@ -266,7 +319,7 @@ func RefreshTokenHandler(c *gin.Context, app *app.App) {
 	}
 
 	// Generate new access token
-	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email)
+	accessToken, expiresAt, err := jwtManager.GenerateAccessToken(user.ID, user.Email, storedToken.FamilyID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to generate access token",
@@ -283,20 +336,26 @@ func RefreshTokenHandler(c *gin.Context, app *app.App) {
 		return
 	}
 
-	// Revoke old refresh token
-	app.Queries.RevokeRefreshToken(ctx, tokenHashStr)
+	// Retire the old refresh token and store its successor in the same family;
+	// neither change is kept unless both succeed
+	if err := queries.RotateRefreshToken(ctx, storedToken.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to rotate refresh token",
+		})
+		return
+	}
 
-	// Store new refresh token
 	newTokenHash := sha256.Sum256([]byte(newRefreshToken))
 	newTokenHashStr := hex.EncodeToString(newTokenHash[:])
 
 	userAgent := c.Request.UserAgent()
-	_, err = app.Queries.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+	_, err = queries.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
 		UserID:    user.ID,
 		TokenHash: newTokenHashStr,
 		ExpiresAt: pgtype.Timestamptz{Time: newRefreshExpiresAt, Valid: true},
 		UserAgent: &userAgent,
 		IpAddress: parseIPToNetip(c.ClientIP()),
+		FamilyID:  storedToken.FamilyID,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -305,8 +364,12 @@ func RefreshTokenHandler(c *gin.Context, app *app.App) {
 		return
 	}
 
-	// Update last used timestamp for tracking
-	app.Queries.UpdateRefreshTokenLastUsed(ctx, storedToken.ID)
+	if err := tx.Commit(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to store refresh token",
+		})
+		return
+	}
 
 	response := models.RefreshTokenResponse{
 		AccessToken:  accessToken,
@@ -449,6 +512,32 @@ func ChangePasswordHandler(c *gin.Context, app *app.App) {
 
 // Helper functions
 
+// revokeReusedTokenFamily revokes every token descended from the same login as a
+// replayed token and records the reuse as a security event
+func revokeReusedTokenFamily(ctx context.Context, queries *database.Queries, c *gin.Context, token database.RefreshToken) error {
+	if err := queries.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"family_id":  token.FamilyID,
+		"token_id":   token.ID,
+		"rotated_at": token.RotatedAt.Time,
+	})
+	if err != nil {
+		return err
+	}
+
+	userAgent := c.Request.UserAgent()
+	return queries.CreateSecurityEvent(ctx, database.CreateSecurityEventParams{
+		UserID:    token.UserID,
+		EventType: database.SecurityEventTypeRefreshTokenReuse,
+		Details:   details,
+		UserAgent: &userAgent,
+		IpAddress: parseIPToNetip(c.ClientIP()),
+	})
+}
+
 // parseIPToNetip parses IP address string to netip.Addr pointer for database storage
 func parseIPToNetip(ipStr string) *netip.Addr {
 	if ipStr == "" {
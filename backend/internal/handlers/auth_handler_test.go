@@ -355,7 +355,7 @@ func TestJWTTokenFlow(t *testing.T) {
 	// Test access token flow
 	t.Run("Access token flow", func(t *testing.T) {
 		// Generate token
-		token, expiresAt, err := jwtManager.GenerateAccessToken(userID, email)
+		token, expiresAt, err := jwtManager.GenerateAccessToken(userID, email, uuid.New())
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 		assert.True(t, expiresAt.After(time.Now()))
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/events"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// sseHeartbeatInterval keeps idle streams open through proxies
+const sseHeartbeatInterval = 15 * time.Second
+
+// StreamExecutionEvents handles GET /events. It streams Server-Sent Events for
+// executions of the caller's jobs, or of one job with ?job_id=: "status" events
+// when an execution changes status and "log" events with output lines of running
+// executions. The stream ends if the client falls too far behind; clients should
+// reconnect and refetch current state.
+func StreamExecutionEvents(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	filter := events.Filter{OwnerID: ownerID}
+	if value := c.Query("job_id"); value != "" {
+		jobID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid job ID format",
+			})
+			return
+		}
+
+		if _, err := app.Queries.GetJob(c.Request.Context(), database.GetJobParams{
+			ID:      jobID,
+			OwnerID: ownerID,
+		}); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Job not found",
+			})
+			return
+		}
+		filter.JobID = jobID
+	}
+
+	subscription := app.Events.Subscribe(filter)
+	defer subscription.Close()
+
+	streamEvents(c, subscription.Events, sseHeartbeatInterval)
+}
+
+// streamEvents writes notifications as Server-Sent Events until the client
+// disconnects or the channel is closed
+func streamEvents(c *gin.Context, notifications <-chan database.ExecutionNotification, heartbeat time.Duration) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": keepalive\n\n")
+		case n, ok := <-notifications:
+			if !ok {
+				return
+			}
+			c.SSEvent(n.Type, toExecutionUpdate(n))
+		}
+		c.Writer.Flush()
+	}
+}
+
+// toExecutionUpdate converts a notification to its API representation
+func toExecutionUpdate(n database.ExecutionNotification) models.ExecutionUpdate {
+	update := models.ExecutionUpdate{
+		ExecutionID: n.ExecutionID,
+		JobID:       n.JobID,
+		Lines:       n.Lines,
+		At:          n.At,
+	}
+	if n.Status != "" {
+		status := string(n.Status)
+		update.Status = &status
+	}
+	return update
+}
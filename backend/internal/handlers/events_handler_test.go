@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestStreamEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil).WithContext(ctx)
+
+	executionID, jobID := uuid.New(), uuid.New()
+	notifications := make(chan database.ExecutionNotification, 2)
+	notifications <- database.ExecutionNotification{
+		Type:        database.ExecutionNotificationStatus,
+		ExecutionID: executionID,
+		JobID:       jobID,
+		OwnerID:     uuid.New(),
+		Status:      database.ExecutionStatusRunning,
+		At:          time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	notifications <- database.ExecutionNotification{
+		Type:        database.ExecutionNotificationLog,
+		ExecutionID: executionID,
+		JobID:       jobID,
+		Lines:       []string{"hello"},
+	}
+	close(notifications)
+
+	streamEvents(c, notifications, time.Hour)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+
+	body := w.Body.String()
+	assert.Contains(t, body, "event:status\n")
+	assert.Contains(t, body, `"status":"running"`)
+	assert.Contains(t, body, "event:log\n")
+	assert.Contains(t, body, `"lines":["hello"]`)
+	// Owners are not exposed
+	assert.NotContains(t, body, "owner_id")
+}
+
+func TestStreamEvents_StopsWhenClientDisconnects(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		streamEvents(c, make(chan database.ExecutionNotification), time.Hour)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not stop after the client disconnected")
+	}
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+const (
+	defaultExecutionPageSize = 20
+	maxExecutionPageSize     = 100
+)
+
+// GetJobExecutions handles GET /jobs/:id/executions
+func GetJobExecutions(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	limit, offset, err := parsePagination(c, defaultExecutionPageSize, maxExecutionPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid pagination parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, ok := loadOwnedJob(c, app)
+	if !ok {
+		return
+	}
+
+	executions, err := app.Queries.GetExecutionsByJobIDWithLimit(c.Request.Context(), database.GetExecutionsByJobIDWithLimitParams{
+		JobID:  job.ID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch executions",
+		})
+		return
+	}
+
+	apiExecutions := make([]models.Execution, len(executions))
+	for i, execution := range executions {
+		apiExecutions[i] = toAPIExecution(execution)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"executions": apiExecutions,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// GetJobExecution handles GET /jobs/:id/executions/:execution_id
+func GetJobExecution(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	execution, ok := loadOwnedExecution(c, app)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toAPIExecution(execution))
+}
+
+// GetJobExecutionStats handles GET /jobs/:id/executions/stats
+func GetJobExecutionStats(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	job, ok := loadOwnedJob(c, app)
+	if !ok {
+		return
+	}
+
+	stats, err := app.Queries.GetExecutionStats(c.Request.Context(), job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch execution stats",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ExecutionStats{
+		TotalExecutions:      stats.TotalExecutions,
+		SuccessfulExecutions: stats.SuccessfulExecutions,
+		FailedExecutions:     stats.FailedExecutions,
+		PendingExecutions:    stats.PendingExecutions,
+		RunningExecutions:    stats.RunningExecutions,
+		AvgCostUSD:           stats.AvgCost,
+		TotalCostUSD:         stats.TotalCost,
+		TotalCarbonKg:        stats.TotalCarbon,
+	})
+}
+
+// RunJob handles POST /jobs/:id/run
+func RunJob(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	// The body is optional; an empty request runs the job as defined
+	var req models.RunJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	job, ok := loadOwnedJob(c, app)
+	if !ok {
+		return
+	}
+
+	params := database.CreateExecutionParams{
+		JobID:  job.ID,
+		Status: database.ExecutionStatusPending,
+	}
+	if req.EnvVars != nil {
+		params.EnvVars = convertEnvVarsToJSON(req.EnvVars)
+	}
+	if req.DelayToleranceHours != nil {
+		hours := int32(*req.DelayToleranceHours)
+		params.DelayToleranceHours = &hours
+	}
+
+	execution, err := createExecution(c.Request.Context(), app, params, "run requested by user")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to submit run",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, toAPIExecution(execution))
+}
+
+// GetJobExecutionEvents handles GET /jobs/:id/executions/:execution_id/events
+func GetJobExecutionEvents(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	execution, ok := loadOwnedExecution(c, app)
+	if !ok {
+		return
+	}
+
+	events, err := app.Queries.GetExecutionEvents(c.Request.Context(), execution.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch execution events",
+		})
+		return
+	}
+
+	apiEvents := make([]models.ExecutionEvent, len(events))
+	for i, event := range events {
+		apiEvents[i] = toAPIExecutionEvent(event)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": apiEvents,
+	})
+}
+
+// createExecution inserts a pending execution together with the event recording
+// its creation by the user
+func createExecution(ctx context.Context, app *app.App, params database.CreateExecutionParams, reason string) (database.Execution, error) {
+	tx, err := app.DB.Begin(ctx)
+	if err != nil {
+		return database.Execution{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := app.Queries.WithTx(tx)
+
+	execution, err := queries.CreateExecution(ctx, params)
+	if err != nil {
+		return database.Execution{}, err
+	}
+
+	created := database.Transition{
+		ExecutionID: execution.ID,
+		To:          execution.Status,
+		Actor:       database.ExecutionActorUser,
+		Reason:      reason,
+	}
+	if err := created.Validate(); err != nil {
+		return database.Execution{}, err
+	}
+	if err := queries.CreateExecutionEvent(ctx, created.Event()); err != nil {
+		return database.Execution{}, err
+	}
+
+	return execution, tx.Commit(ctx)
+}
+
+// loadOwnedJob resolves the :id path parameter to a job owned by the authenticated
+// user. On failure it writes the error response and returns false.
+func loadOwnedJob(c *gin.Context, app *app.App) (database.Job, bool) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID format",
+		})
+		return database.Job{}, false
+	}
+
+	// Get authenticated user ID from JWT token
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return database.Job{}, false
+	}
+
+	job, err := app.Queries.GetJob(c.Request.Context(), database.GetJobParams{
+		ID:      jobID,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Job not found",
+		})
+		return database.Job{}, false
+	}
+
+	return job, true
+}
+
+// loadOwnedExecution resolves the :execution_id path parameter to an execution of
+// the job named by :id, which must be owned by the authenticated user. On failure
+// it writes the error response and returns false.
+func loadOwnedExecution(c *gin.Context, app *app.App) (database.Execution, bool) {
+	executionID, err := uuid.Parse(c.Param("execution_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid execution ID format",
+		})
+		return database.Execution{}, false
+	}
+
+	job, ok := loadOwnedJob(c, app)
+	if !ok {
+		return database.Execution{}, false
+	}
+
+	// Executions of other jobs are reported as missing rather than forbidden
+	execution, err := app.Queries.GetExecution(c.Request.Context(), executionID)
+	if err != nil || execution.JobID != job.ID {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Execution not found",
+		})
+		return database.Execution{}, false
+	}
+
+	return execution, true
+}
+
+// parsePagination reads limit and offset query parameters
+func parsePagination(c *gin.Context, defaultLimit, maxLimit int) (int32, int32, error) {
+	limit := defaultLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+		limit = parsed
+	}
+
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+		offset = parsed
+	}
+
+	return int32(limit), int32(offset), nil
+}
+
+// toAPIExecution converts a database execution to its API representation
+func toAPIExecution(execution database.Execution) models.Execution {
+	apiExecution := models.Execution{
+		ID:                  execution.ID,
+		JobID:               execution.JobID,
+		Status:              string(execution.Status),
+		ChosenAt:            execution.ChosenAt.Ptr(),
+		PlannedStartAt:      execution.PlannedStartAt.Ptr(),
+		CloudRegion:         execution.CloudRegion,
+		VMType:              execution.VmType,
+		StartedAt:           execution.StartedAt.Ptr(),
+		CompletedAt:         execution.CompletedAt.Ptr(),
+		ExitCode:            execution.ExitCode.Ptr(),
+		CostEstimateUSD:     execution.CostEstimateUsd.Ptr(),
+		CostActualUSD:       execution.CostActualUsd.Ptr(),
+		CarbonIntensityGKwh: execution.CarbonIntensityGKwh.Ptr(),
+		CarbonEmittedKg:     execution.CarbonEmittedKg.Ptr(),
+		EnergyKWh:           execution.EnergyKwh.Ptr(),
+		DelayToleranceHours: execution.DelayToleranceHours,
+		CreatedAt:           execution.CreatedAt,
+	}
+
+	if len(execution.EnvVars) > 0 {
+		apiExecution.EnvVars = convertJSONToEnvVars(execution.EnvVars)
+	}
+
+	return apiExecution
+}
+
+// toAPIExecutionEvent converts a database execution event to its API representation
+func toAPIExecutionEvent(event database.ExecutionEvent) models.ExecutionEvent {
+	apiEvent := models.ExecutionEvent{
+		ID:        event.ID,
+		ToStatus:  string(event.ToStatus),
+		Actor:     string(event.Actor),
+		Reason:    event.Reason,
+		CreatedAt: event.CreatedAt,
+	}
+
+	if event.FromStatus.Valid {
+		from := string(event.FromStatus.ExecutionStatus)
+		apiEvent.FromStatus = &from
+	}
+
+	return apiEvent
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedLimit  int32
+		expectedOffset int32
+		expectError    bool
+	}{
+		{"defaults", "", 20, 0, false},
+		{"explicit", "?limit=50&offset=100", 50, 100, false},
+		{"limit too large", "?limit=101", 0, 0, true},
+		{"zero limit", "?limit=0", 0, 0, true},
+		{"negative offset", "?offset=-1", 0, 0, true},
+		{"not a number", "?limit=all", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/executions"+tt.query, nil)
+
+			limit, offset, err := parsePagination(c, defaultExecutionPageSize, maxExecutionPageSize)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedLimit, limit)
+			assert.Equal(t, tt.expectedOffset, offset)
+		})
+	}
+}
+
+func TestRunJobRequest_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/run/validate", func(c *gin.Context) {
+		var req models.RunJobRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Validation passed"})
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"empty body", ``, http.StatusOK},
+		{"no overrides", `{}`, http.StatusOK},
+		{"malformed", `{"env_vars":`, http.StatusBadRequest},
+		{"env overrides", `{"env_vars": {"BATCH": "2025-01"}}`, http.StatusOK},
+		{"run immediately", `{"delay_tolerance_hours": 0}`, http.StatusOK},
+		{"tolerance too large", `{"delay_tolerance_hours": 169}`, http.StatusBadRequest},
+		{"negative tolerance", `{"delay_tolerance_hours": -1}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/run/validate", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestToAPIExecution(t *testing.T) {
+	region := "swedencentral"
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	execution := database.Execution{
+		ID:              uuid.New(),
+		JobID:           uuid.New(),
+		Status:          database.ExecutionStatusRunning,
+		CloudRegion:     &region,
+		StartedAt:       null.TimeFrom(started),
+		CostEstimateUsd: null.FloatFrom(0.42),
+		CreatedAt:       started.Add(-time.Hour),
+	}
+
+	result := toAPIExecution(execution)
+
+	assert.Equal(t, execution.ID, result.ID)
+	assert.Equal(t, "running", result.Status)
+	assert.Equal(t, &region, result.CloudRegion)
+	require.NotNil(t, result.StartedAt)
+	assert.Equal(t, started, *result.StartedAt)
+	require.NotNil(t, result.CostEstimateUSD)
+	assert.Equal(t, 0.42, *result.CostEstimateUSD)
+
+	// Unset nullable columns are omitted
+	assert.Nil(t, result.CompletedAt)
+	assert.Nil(t, result.ExitCode)
+	assert.Nil(t, result.CarbonEmittedKg)
+	assert.Nil(t, result.EnvVars)
+	assert.Nil(t, result.DelayToleranceHours)
+}
+
+func TestToAPIExecutionEvent(t *testing.T) {
+	created := database.ExecutionEvent{
+		ID:        uuid.New(),
+		ToStatus:  database.ExecutionStatusPending,
+		Actor:     database.ExecutionActorUser,
+		Reason:    "run requested by user",
+		CreatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	result := toAPIExecutionEvent(created)
+	assert.Equal(t, created.ID, result.ID)
+	assert.Nil(t, result.FromStatus)
+	assert.Equal(t, "pending", result.ToStatus)
+	assert.Equal(t, "user", result.Actor)
+	assert.Equal(t, "run requested by user", result.Reason)
+
+	started := database.ExecutionEvent{
+		FromStatus: database.NullExecutionStatus{ExecutionStatus: database.ExecutionStatusEvaluating, Valid: true},
+		ToStatus:   database.ExecutionStatusRunning,
+		Actor:      database.ExecutionActorExecutor,
+	}
+
+	result = toAPIExecutionEvent(started)
+	require.NotNil(t, result.FromStatus)
+	assert.Equal(t, "evaluating", *result.FromStatus)
+	assert.Equal(t, "executor", result.Actor)
+}
@@ -2,14 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
 	"github.com/nouvadev/veridian/backend/internal/app"
 	"github.com/nouvadev/veridian/backend/internal/database"
 	"github.com/nouvadev/veridian/backend/internal/middleware"
 	"github.com/nouvadev/veridian/backend/internal/models"
+	"github.com/nouvadev/veridian/backend/internal/schedule"
+)
+
+const (
+	defaultNextRuns = 5
+	maxNextRuns     = 50
 )
 
 // CreateJob handles POST /jobs
@@ -28,6 +38,24 @@ func CreateJob(c *gin.Context, app *app.App) {
 		return
 	}
 
+	sched, err := req.ParseSchedule()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resources, err := req.Resources()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid resource requirements",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Get authenticated user ID from JWT token
 	ownerID, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
@@ -41,10 +69,18 @@ func CreateJob(c *gin.Context, app *app.App) {
 
 	// Create job in database using SQLC
 	params := database.CreateJobParams{
-		OwnerID:             ownerID,
-		ImageUri:            req.ImageURI,
-		EnvVars:             convertEnvVarsToJSON(req.EnvVars),
-		DelayToleranceHours: int32(req.DelayToleranceHours),
+		OwnerID:                ownerID,
+		ImageUri:               req.ImageURI,
+		EnvVars:                convertEnvVarsToJSON(req.EnvVars),
+		DelayToleranceHours:    int32(req.DelayToleranceHours),
+		CronSchedule:           req.CronSchedule,
+		Timezone:               scheduleTimezone(sched),
+		NextRunAt:              firstRunAt(sched, time.Now()),
+		CpuCores:               int32(resources.CPUCores),
+		MemoryMb:               int32(resources.MemoryMB),
+		DiskGb:                 int32(resources.DiskGB),
+		ExpectedRuntimeMinutes: minutesParam(resources.ExpectedRuntimeMinutes),
+		MaxRuntimeMinutes:      minutesParam(resources.MaxRuntimeMinutes),
 	}
 
 	job, err := app.Queries.CreateJob(ctx, params)
@@ -61,6 +97,10 @@ func CreateJob(c *gin.Context, app *app.App) {
 		ImageURI:            job.ImageUri,
 		EnvVars:             convertJSONToEnvVars(job.EnvVars),
 		DelayToleranceHours: int(job.DelayToleranceHours),
+		CronSchedule:        job.CronSchedule,
+		Timezone:            job.Timezone,
+		NextRunAt:           job.NextRunAt.Ptr(),
+		JobResources:        jobResources(job),
 		CreatedAt:           job.CreatedAt,
 		UpdatedAt:           job.UpdatedAt,
 	}
@@ -102,6 +142,10 @@ func GetJobs(c *gin.Context, app *app.App) {
 			ImageURI:            job.ImageUri,
 			EnvVars:             convertJSONToEnvVars(job.EnvVars),
 			DelayToleranceHours: int(job.DelayToleranceHours),
+			CronSchedule:        job.CronSchedule,
+			Timezone:            job.Timezone,
+			NextRunAt:           job.NextRunAt.Ptr(),
+			JobResources:        jobResources(job),
 			CreatedAt:           job.CreatedAt,
 			UpdatedAt:           job.UpdatedAt,
 		}
@@ -127,6 +171,17 @@ func GetJob(c *gin.Context, app *app.App) {
 		return
 	}
 
+	nextRuns := defaultNextRuns
+	if value := c.Query("next_runs"); value != "" {
+		nextRuns, err = strconv.Atoi(value)
+		if err != nil || nextRuns < 0 || nextRuns > maxNextRuns {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("next_runs must be between 0 and %d", maxNextRuns),
+			})
+			return
+		}
+	}
+
 	// Get authenticated user ID from JWT token
 	ownerID, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
@@ -155,8 +210,13 @@ func GetJob(c *gin.Context, app *app.App) {
 		ImageURI:            job.ImageUri,
 		EnvVars:             convertJSONToEnvVars(job.EnvVars),
 		DelayToleranceHours: int(job.DelayToleranceHours),
+		CronSchedule:        job.CronSchedule,
+		Timezone:            job.Timezone,
+		NextRunAt:           job.NextRunAt.Ptr(),
+		JobResources:        jobResources(job),
 		CreatedAt:           job.CreatedAt,
 		UpdatedAt:           job.UpdatedAt,
+		NextRuns:            upcomingRuns(job, nextRuns, time.Now()),
 	}
 
 	c.JSON(http.StatusOK, apiJob)
@@ -187,6 +247,24 @@ func UpdateJob(c *gin.Context, app *app.App) {
 		return
 	}
 
+	sched, err := req.ParseSchedule()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid schedule",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	resources, err := req.Resources()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid resource requirements",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Get authenticated user ID from JWT token
 	ownerID, exists := middleware.GetUserIDFromContext(c)
 	if !exists {
@@ -198,12 +276,33 @@ func UpdateJob(c *gin.Context, app *app.App) {
 
 	ctx := c.Request.Context()
 
+	existing, err := app.Queries.GetJob(ctx, database.GetJobParams{
+		ID:      jobID,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Job not found or permission denied",
+		})
+		return
+	}
+
+	timezone := scheduleTimezone(sched)
 	params := database.UpdateJobParams{
-		ID:                  jobID,
-		OwnerID:             ownerID,
-		ImageUri:            req.ImageURI,
-		EnvVars:             convertEnvVarsToJSON(req.EnvVars),
-		DelayToleranceHours: int32(req.DelayToleranceHours),
+		ID:                     jobID,
+		OwnerID:                ownerID,
+		ImageUri:               req.ImageURI,
+		EnvVars:                convertEnvVarsToJSON(req.EnvVars),
+		DelayToleranceHours:    int32(req.DelayToleranceHours),
+		CronSchedule:           req.CronSchedule,
+		Timezone:               timezone,
+		Reschedule:             scheduleChanged(existing, req.CronSchedule, timezone),
+		NextRunAt:              firstRunAt(sched, time.Now()),
+		CpuCores:               int32(resources.CPUCores),
+		MemoryMb:               int32(resources.MemoryMB),
+		DiskGb:                 int32(resources.DiskGB),
+		ExpectedRuntimeMinutes: minutesParam(resources.ExpectedRuntimeMinutes),
+		MaxRuntimeMinutes:      minutesParam(resources.MaxRuntimeMinutes),
 	}
 
 	job, err := app.Queries.UpdateJob(ctx, params)
@@ -220,6 +319,10 @@ func UpdateJob(c *gin.Context, app *app.App) {
 		ImageURI:            job.ImageUri,
 		EnvVars:             convertJSONToEnvVars(job.EnvVars),
 		DelayToleranceHours: int(job.DelayToleranceHours),
+		CronSchedule:        job.CronSchedule,
+		Timezone:            job.Timezone,
+		NextRunAt:           job.NextRunAt.Ptr(),
+		JobResources:        jobResources(job),
 		CreatedAt:           job.CreatedAt,
 		UpdatedAt:           job.UpdatedAt,
 	}
@@ -267,6 +370,84 @@ func DeleteJob(c *gin.Context, app *app.App) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// scheduleTimezone returns the timezone stored for a job
+func scheduleTimezone(sched *schedule.Schedule) string {
+	if sched == nil {
+		return schedule.DefaultTimezone
+	}
+	return sched.Location().String()
+}
+
+// firstRunAt returns the first fire time of a recurring job after now
+func firstRunAt(sched *schedule.Schedule, now time.Time) null.Time {
+	if sched == nil {
+		return null.Time{}
+	}
+	next := sched.Next(now)
+	return null.NewTime(next, !next.IsZero())
+}
+
+// scheduleChanged reports whether an update changes when a job fires, so its
+// next_run_at has to be recomputed
+func scheduleChanged(job database.Job, cronSchedule *string, timezone string) bool {
+	if (job.CronSchedule == nil) != (cronSchedule == nil) {
+		return true
+	}
+	if cronSchedule == nil {
+		return false
+	}
+	return *job.CronSchedule != *cronSchedule || job.Timezone != timezone
+}
+
+// upcomingRuns returns the next n fire times of a recurring job, starting with next_run_at
+func upcomingRuns(job database.Job, n int, now time.Time) []time.Time {
+	if job.CronSchedule == nil || !job.NextRunAt.Valid || n == 0 {
+		return nil
+	}
+
+	sched, err := schedule.Parse(*job.CronSchedule, job.Timezone)
+	if err != nil {
+		return nil
+	}
+
+	// next_run_at may be slightly in the past until the scheduler fires it
+	runs := []time.Time{job.NextRunAt.Time}
+	after := job.NextRunAt.Time
+	if now.After(after) {
+		after = now
+	}
+	return append(runs, sched.NextN(after, n-1)...)
+}
+
+// jobResources returns a job's resource requirements in API form
+func jobResources(job database.Job) models.JobResources {
+	return models.JobResources{
+		CPUCores:               int(job.CpuCores),
+		MemoryMB:               int(job.MemoryMb),
+		DiskGB:                 int(job.DiskGb),
+		ExpectedRuntimeMinutes: minutesResponse(job.ExpectedRuntimeMinutes),
+		MaxRuntimeMinutes:      minutesResponse(job.MaxRuntimeMinutes),
+	}
+}
+
+// minutesParam converts an optional runtime for the database
+func minutesParam(minutes *int) *int32 {
+	if minutes == nil {
+		return nil
+	}
+	value := int32(*minutes)
+	return &value
+}
+
+// minutesResponse converts an optional runtime for the API
+func minutesResponse(minutes *int32) *int {
+	if minutes == nil {
+		return nil
+	}
+	value := int(*minutes)
+	return &value
+}
+
 // Helper functions for converting between JSON and map[string]interface{}
 func convertEnvVarsToJSON(envVars map[string]interface{}) []byte {
 	if envVars == nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -50,6 +51,9 @@ func (m *MockQuerier) CreateJob(ctx context.Context, arg database.CreateJobParam
 		ImageUri:            arg.ImageUri,
 		EnvVars:             arg.EnvVars,
 		DelayToleranceHours: arg.DelayToleranceHours,
+		CronSchedule:        arg.CronSchedule,
+		Timezone:            arg.Timezone,
+		NextRunAt:           arg.NextRunAt,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
@@ -99,6 +103,11 @@ func (m *MockQuerier) UpdateJob(ctx context.Context, arg database.UpdateJobParam
 	job.ImageUri = arg.ImageUri
 	job.EnvVars = arg.EnvVars
 	job.DelayToleranceHours = arg.DelayToleranceHours
+	job.CronSchedule = arg.CronSchedule
+	job.Timezone = arg.Timezone
+	if arg.Reschedule {
+		job.NextRunAt = arg.NextRunAt
+	}
 	job.UpdatedAt = time.Now()
 
 	m.jobs[arg.ID] = job
@@ -140,15 +149,42 @@ func (m *MockQuerier) DeleteJob(ctx context.Context, arg database.DeleteJobParam
 }
 
 // Stub implementations for other required methods to satisfy database.Querier interface
+func (m *MockQuerier) ClaimDueWebhookDeliveries(ctx context.Context, arg database.ClaimDueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *MockQuerier) ClaimPendingExecutions(ctx context.Context, arg database.ClaimPendingExecutionsParams) ([]database.Execution, error) {
+	return []database.Execution{}, nil
+}
+func (m *MockQuerier) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (database.PasswordResetToken, error) {
+	return database.PasswordResetToken{}, nil
+}
+func (m *MockQuerier) CreateAPIKey(ctx context.Context, arg database.CreateAPIKeyParams) (database.ApiKey, error) {
+	return database.ApiKey{}, nil
+}
 func (m *MockQuerier) CreateExecution(ctx context.Context, arg database.CreateExecutionParams) (database.Execution, error) {
 	return database.Execution{}, nil
 }
+func (m *MockQuerier) CreateExecutionEvent(ctx context.Context, arg database.CreateExecutionEventParams) error {
+	return nil
+}
+func (m *MockQuerier) CreatePasswordResetToken(ctx context.Context, arg database.CreatePasswordResetTokenParams) (database.PasswordResetToken, error) {
+	return database.PasswordResetToken{}, nil
+}
 func (m *MockQuerier) CreateRefreshToken(ctx context.Context, arg database.CreateRefreshTokenParams) (database.RefreshToken, error) {
 	return database.RefreshToken{}, nil
 }
+func (m *MockQuerier) CreateSecurityEvent(ctx context.Context, arg database.CreateSecurityEventParams) error {
+	return nil
+}
 func (m *MockQuerier) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
 	return database.User{}, nil
 }
+func (m *MockQuerier) CreateWebhookDeliveryAttempt(ctx context.Context, arg database.CreateWebhookDeliveryAttemptParams) error {
+	return nil
+}
+func (m *MockQuerier) CreateWebhookSubscription(ctx context.Context, arg database.CreateWebhookSubscriptionParams) (database.WebhookSubscription, error) {
+	return database.WebhookSubscription{}, nil
+}
 func (m *MockQuerier) DeactivateUser(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
@@ -161,9 +197,30 @@ func (m *MockQuerier) DeleteExpiredRefreshTokens(ctx context.Context) error {
 func (m *MockQuerier) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
+func (m *MockQuerier) DeleteWebhookSubscription(ctx context.Context, arg database.DeleteWebhookSubscriptionParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) EnsureUserSettings(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+func (m *MockQuerier) FireJobSchedule(ctx context.Context, arg database.FireJobScheduleParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) GetAbandonedRunningExecutions(ctx context.Context, arg database.GetAbandonedRunningExecutionsParams) ([]database.Execution, error) {
+	return []database.Execution{}, nil
+}
+func (m *MockQuerier) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (database.ApiKey, error) {
+	return database.ApiKey{}, nil
+}
+func (m *MockQuerier) GetDueJobSchedules(ctx context.Context, arg database.GetDueJobSchedulesParams) ([]database.Job, error) {
+	return []database.Job{}, nil
+}
 func (m *MockQuerier) GetExecution(ctx context.Context, id uuid.UUID) (database.Execution, error) {
 	return database.Execution{}, nil
 }
+func (m *MockQuerier) GetExecutionEvents(ctx context.Context, executionID uuid.UUID) ([]database.ExecutionEvent, error) {
+	return []database.ExecutionEvent{}, nil
+}
 func (m *MockQuerier) GetExecutionStats(ctx context.Context, jobID uuid.UUID) (database.GetExecutionStatsRow, error) {
 	return database.GetExecutionStatsRow{}, nil
 }
@@ -176,12 +233,21 @@ func (m *MockQuerier) GetExecutionsByJobIDWithLimit(ctx context.Context, arg dat
 func (m *MockQuerier) GetExecutionsByStatus(ctx context.Context, status database.ExecutionStatus) ([]database.Execution, error) {
 	return []database.Execution{}, nil
 }
+func (m *MockQuerier) GetJobByID(ctx context.Context, id uuid.UUID) (database.Job, error) {
+	return database.Job{}, nil
+}
 func (m *MockQuerier) GetJobCount(ctx context.Context, ownerID uuid.UUID) (int64, error) {
 	return 0, nil
 }
+func (m *MockQuerier) GetJobSchedulingInfo(ctx context.Context, id uuid.UUID) (database.GetJobSchedulingInfoRow, error) {
+	return database.GetJobSchedulingInfoRow{}, nil
+}
 func (m *MockQuerier) GetJobsByOwnerWithLimit(ctx context.Context, arg database.GetJobsByOwnerWithLimitParams) ([]database.Job, error) {
 	return []database.Job{}, nil
 }
+func (m *MockQuerier) GetOrphanedExecutionsDue(ctx context.Context, arg database.GetOrphanedExecutionsDueParams) ([]database.Execution, error) {
+	return []database.Execution{}, nil
+}
 func (m *MockQuerier) GetPendingExecutions(ctx context.Context) ([]database.Execution, error) {
 	return []database.Execution{}, nil
 }
@@ -191,6 +257,9 @@ func (m *MockQuerier) GetRecentJobs(ctx context.Context, arg database.GetRecentJ
 func (m *MockQuerier) GetRefreshToken(ctx context.Context, tokenHash string) (database.RefreshToken, error) {
 	return database.RefreshToken{}, nil
 }
+func (m *MockQuerier) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (database.RefreshToken, error) {
+	return database.RefreshToken{}, nil
+}
 func (m *MockQuerier) GetUserByEmail(ctx context.Context, email string) (database.User, error) {
 	return database.User{}, nil
 }
@@ -200,33 +269,102 @@ func (m *MockQuerier) GetUserByEmailIncludeInactive(ctx context.Context, email s
 func (m *MockQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
 	return database.User{}, nil
 }
-func (m *MockQuerier) GetUserExecutionStats(ctx context.Context, ownerID uuid.UUID) (database.GetUserExecutionStatsRow, error) {
+func (m *MockQuerier) GetUserExecutionStats(ctx context.Context, arg database.GetUserExecutionStatsParams) (database.GetUserExecutionStatsRow, error) {
 	return database.GetUserExecutionStatsRow{}, nil
 }
+func (m *MockQuerier) GetUserExecutionStatsByBucket(ctx context.Context, arg database.GetUserExecutionStatsByBucketParams) ([]database.GetUserExecutionStatsByBucketRow, error) {
+	return nil, nil
+}
 func (m *MockQuerier) GetUserRefreshTokens(ctx context.Context, userID uuid.UUID) ([]database.RefreshToken, error) {
 	return []database.RefreshToken{}, nil
 }
+func (m *MockQuerier) GetUserSavings(ctx context.Context, ownerID uuid.UUID) (database.GetUserSavingsRow, error) {
+	return database.GetUserSavingsRow{}, nil
+}
+func (m *MockQuerier) GetUserSettings(ctx context.Context, userID uuid.UUID) (database.GetUserSettingsRow, error) {
+	return database.GetUserSettingsRow{}, nil
+}
+func (m *MockQuerier) GetWebhookDelivery(ctx context.Context, arg database.GetWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	return database.WebhookDelivery{}, nil
+}
+func (m *MockQuerier) GetWebhookSubscription(ctx context.Context, arg database.GetWebhookSubscriptionParams) (database.WebhookSubscription, error) {
+	return database.WebhookSubscription{}, nil
+}
+func (m *MockQuerier) GetWebhookSubscriptionByID(ctx context.Context, id uuid.UUID) (database.WebhookSubscription, error) {
+	return database.WebhookSubscription{}, nil
+}
+func (m *MockQuerier) InvalidateUserPasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+func (m *MockQuerier) LeaseDueExecutions(ctx context.Context, arg database.LeaseDueExecutionsParams) ([]database.Execution, error) {
+	return []database.Execution{}, nil
+}
+func (m *MockQuerier) ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+	return nil, nil
+}
+func (m *MockQuerier) ListWebhookDeliveries(ctx context.Context, arg database.ListWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *MockQuerier) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID uuid.UUID) ([]database.WebhookDeliveryAttempt, error) {
+	return nil, nil
+}
+func (m *MockQuerier) ListWebhookSubscriptions(ctx context.Context, ownerID uuid.UUID) ([]database.WebhookSubscription, error) {
+	return nil, nil
+}
+func (m *MockQuerier) MarkExecutionOrphaned(ctx context.Context, arg database.MarkExecutionOrphanedParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) RecordTeardownFailure(ctx context.Context, arg database.RecordTeardownFailureParams) error {
+	return nil
+}
+func (m *MockQuerier) ReleaseExpiredLeases(ctx context.Context, actor database.ExecutionActor) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) RenewExecutionLease(ctx context.Context, arg database.RenewExecutionLeaseParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) ResolveOrphanedExecution(ctx context.Context, arg database.ResolveOrphanedExecutionParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) RevokeAPIKey(ctx context.Context, arg database.RevokeAPIKeyParams) (int64, error) {
+	return 0, nil
+}
 func (m *MockQuerier) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
 	return nil
 }
+func (m *MockQuerier) RevokeOtherUserRefreshTokens(ctx context.Context, arg database.RevokeOtherUserRefreshTokensParams) (int64, error) {
+	return 0, nil
+}
 func (m *MockQuerier) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
 	return nil
 }
+func (m *MockQuerier) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	return nil
+}
+func (m *MockQuerier) RevokeUserRefreshTokenFamily(ctx context.Context, arg database.RevokeUserRefreshTokenFamilyParams) (int64, error) {
+	return 0, nil
+}
+func (m *MockQuerier) RotateRefreshToken(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+func (m *MockQuerier) UpdateAPIKeyLastUsed(ctx context.Context, arg database.UpdateAPIKeyLastUsedParams) error {
+	return nil
+}
 func (m *MockQuerier) UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error) {
 	return database.Execution{}, nil
 }
 func (m *MockQuerier) UpdateExecutionCostEstimate(ctx context.Context, arg database.UpdateExecutionCostEstimateParams) (database.Execution, error) {
 	return database.Execution{}, nil
 }
+func (m *MockQuerier) UpdateExecutionInstance(ctx context.Context, arg database.UpdateExecutionInstanceParams) error {
+	return nil
+}
 func (m *MockQuerier) UpdateExecutionScheduling(ctx context.Context, arg database.UpdateExecutionSchedulingParams) (database.Execution, error) {
 	return database.Execution{}, nil
 }
 func (m *MockQuerier) UpdateExecutionStart(ctx context.Context, arg database.UpdateExecutionStartParams) (database.Execution, error) {
 	return database.Execution{}, nil
 }
-func (m *MockQuerier) UpdateExecutionStatus(ctx context.Context, arg database.UpdateExecutionStatusParams) (database.Execution, error) {
-	return database.Execution{}, nil
-}
 func (m *MockQuerier) UpdateRefreshTokenLastUsed(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
@@ -239,6 +377,12 @@ func (m *MockQuerier) UpdateUserLastLogin(ctx context.Context, id uuid.UUID) err
 func (m *MockQuerier) UpdateUserPassword(ctx context.Context, arg database.UpdateUserPasswordParams) (database.User, error) {
 	return database.User{}, nil
 }
+func (m *MockQuerier) UpdateWebhookDeliveryAttempt(ctx context.Context, arg database.UpdateWebhookDeliveryAttemptParams) error {
+	return nil
+}
+func (m *MockQuerier) UpsertUserSettings(ctx context.Context, arg database.UpsertUserSettingsParams) (database.UpsertUserSettingsRow, error) {
+	return database.UpsertUserSettingsRow{}, nil
+}
 
 // createTestJobHandler creates a job handler that directly handles the request without complex app struct
 func createTestJobHandler(querier database.Querier) gin.HandlerFunc {
@@ -382,6 +526,87 @@ func createTestGetJobHandler(querier database.Querier) gin.HandlerFunc {
 	}
 }
 
+func createTestUpdateJobHandler(querier database.Querier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		jobID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid job ID format",
+			})
+			return
+		}
+
+		var req models.CreateJobRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		sched, err := req.ParseSchedule()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid schedule",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		existing, err := querier.GetJob(ctx, database.GetJobParams{
+			ID:      jobID,
+			OwnerID: userID.(uuid.UUID),
+		})
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Job not found or permission denied",
+			})
+			return
+		}
+
+		timezone := scheduleTimezone(sched)
+		job, err := querier.UpdateJob(ctx, database.UpdateJobParams{
+			ID:                  jobID,
+			OwnerID:             userID.(uuid.UUID),
+			ImageUri:            req.ImageURI,
+			EnvVars:             convertEnvVarsToJSON(req.EnvVars),
+			DelayToleranceHours: int32(req.DelayToleranceHours),
+			CronSchedule:        req.CronSchedule,
+			Timezone:            timezone,
+			Reschedule:          scheduleChanged(existing, req.CronSchedule, timezone),
+			NextRunAt:           firstRunAt(sched, time.Now()),
+		})
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Job not found or permission denied",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.Job{
+			ID:                  job.ID,
+			OwnerID:             job.OwnerID,
+			ImageURI:            job.ImageUri,
+			EnvVars:             convertJSONToEnvVars(job.EnvVars),
+			DelayToleranceHours: int(job.DelayToleranceHours),
+			CreatedAt:           job.CreatedAt,
+			UpdatedAt:           job.UpdatedAt,
+		})
+	}
+}
+
 // Test CreateJob success
 func TestCreateJob_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -713,3 +938,151 @@ func TestConvertJSONToEnvVars(t *testing.T) {
 	result = convertJSONToEnvVars([]byte("invalid json"))
 	assert.Equal(t, make(map[string]interface{}), result)
 }
+
+// Test schedule validation on CreateJobRequest
+func TestCreateJobRequest_ParseSchedule(t *testing.T) {
+	nightly := "0 2 * * *"
+	invalid := "every night at two"
+
+	tests := []struct {
+		name        string
+		req         models.CreateJobRequest
+		expectError bool
+		recurring   bool
+	}{
+		{"on demand", models.CreateJobRequest{}, false, false},
+		{"nightly in UTC", models.CreateJobRequest{CronSchedule: &nightly}, false, true},
+		{"nightly in Amsterdam", models.CreateJobRequest{CronSchedule: &nightly, Timezone: "Europe/Amsterdam"}, false, true},
+		{"invalid expression", models.CreateJobRequest{CronSchedule: &invalid}, true, false},
+		{"invalid timezone", models.CreateJobRequest{CronSchedule: &nightly, Timezone: "Europe/Atlantis"}, true, false},
+		{"timezone without schedule", models.CreateJobRequest{Timezone: "Europe/Amsterdam"}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := tt.req.ParseSchedule()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.recurring, sched != nil)
+		})
+	}
+}
+
+// Test resource requirement defaults and validation on CreateJobRequest
+func TestCreateJobRequest_Resources(t *testing.T) {
+	thirty, sixty := 30, 60
+
+	resources, err := (&models.CreateJobRequest{}).Resources()
+	require.NoError(t, err)
+	assert.Equal(t, models.JobResources{
+		CPUCores: models.DefaultCPUCores,
+		MemoryMB: models.DefaultMemoryMB,
+		DiskGB:   models.DefaultDiskGB,
+	}, resources)
+
+	req := models.CreateJobRequest{CPUCores: 4, MemoryMB: 16384, DiskGB: 100, ExpectedRuntimeMinutes: &thirty, MaxRuntimeMinutes: &sixty}
+	resources, err = req.Resources()
+	require.NoError(t, err)
+	assert.Equal(t, 4, resources.CPUCores)
+	assert.Equal(t, 16384, resources.MemoryMB)
+	assert.Equal(t, 100, resources.DiskGB)
+	assert.Equal(t, &thirty, resources.ExpectedRuntimeMinutes)
+	assert.Equal(t, &sixty, resources.MaxRuntimeMinutes)
+
+	// Expected to outlive its own limit
+	req = models.CreateJobRequest{ExpectedRuntimeMinutes: &sixty, MaxRuntimeMinutes: &thirty}
+	_, err = req.Resources()
+	assert.Error(t, err)
+}
+
+// Test upcoming fire times shown on GET /jobs/:id
+func TestUpcomingRuns(t *testing.T) {
+	nightly := "0 2 * * *"
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	next := time.Date(2025, 1, 2, 2, 0, 0, 0, time.UTC)
+
+	job := database.Job{
+		CronSchedule: &nightly,
+		Timezone:     "UTC",
+		NextRunAt:    null.TimeFrom(next),
+	}
+
+	runs := upcomingRuns(job, 3, now)
+	require.Len(t, runs, 3)
+	assert.Equal(t, next, runs[0])
+	assert.Equal(t, next.Add(24*time.Hour), runs[1])
+	assert.Equal(t, next.Add(48*time.Hour), runs[2])
+
+	assert.Empty(t, upcomingRuns(job, 0, now))
+	assert.Empty(t, upcomingRuns(database.Job{Timezone: "UTC"}, 3, now))
+}
+
+// Test that PUT /jobs/:id only recomputes next_run_at when the schedule changes
+func TestScheduleChanged(t *testing.T) {
+	nightly := "0 2 * * *"
+	hourly := "0 * * * *"
+	job := database.Job{CronSchedule: &nightly, Timezone: "Europe/Berlin"}
+
+	assert.False(t, scheduleChanged(job, &nightly, "Europe/Berlin"))
+	assert.True(t, scheduleChanged(job, &hourly, "Europe/Berlin"))
+	assert.True(t, scheduleChanged(job, &nightly, "UTC"))
+	assert.True(t, scheduleChanged(job, nil, "UTC"))
+
+	oneOff := database.Job{Timezone: "UTC"}
+	assert.False(t, scheduleChanged(oneOff, nil, "UTC"))
+	assert.True(t, scheduleChanged(oneOff, &nightly, "UTC"))
+}
+
+// Test that PUT /jobs/:id keeps next_run_at unless the schedule changes
+func TestUpdateJob_KeepsNextRunAtUnlessScheduleChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockQuerier := NewMockQuerier()
+	testUserID := uuid.New()
+	nightly := "0 2 * * *"
+	hourly := "0 * * * *"
+	stored := time.Date(2020, 1, 2, 2, 0, 0, 0, time.UTC)
+
+	job, err := mockQuerier.CreateJob(context.Background(), database.CreateJobParams{
+		OwnerID:      testUserID,
+		ImageUri:     "alpine:latest",
+		CronSchedule: &nightly,
+		Timezone:     "UTC",
+		NextRunAt:    null.TimeFrom(stored),
+	})
+	require.NoError(t, err)
+
+	update := func(reqBody models.CreateJobRequest) database.Job {
+		jsonBody, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest("PUT", "/jobs/"+job.ID.String(), bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: job.ID.String()}}
+		c.Set("user_id", testUserID)
+
+		createTestUpdateJobHandler(mockQuerier)(c)
+		require.Equal(t, http.StatusOK, w.Code)
+		return mockQuerier.jobs[job.ID]
+	}
+
+	// Changing only the image keeps the pending occurrence
+	updated := update(models.CreateJobRequest{ImageURI: "alpine:3.20", DelayToleranceHours: 24, CronSchedule: &nightly})
+	assert.Equal(t, "alpine:3.20", updated.ImageUri)
+	assert.Equal(t, stored, updated.NextRunAt.Time)
+
+	// Changing the cron schedule recomputes it
+	updated = update(models.CreateJobRequest{ImageURI: "alpine:3.20", DelayToleranceHours: 24, CronSchedule: &hourly})
+	assert.True(t, updated.NextRunAt.Time.After(time.Now()))
+
+	// Changing the timezone recomputes it
+	mockQuerier.jobs[job.ID] = job
+	updated = update(models.CreateJobRequest{ImageURI: "alpine:latest", DelayToleranceHours: 24, CronSchedule: &nightly, Timezone: "Europe/Berlin"})
+	assert.Equal(t, "Europe/Berlin", updated.Timezone)
+	assert.True(t, updated.NextRunAt.Time.After(time.Now()))
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+)
+
+const (
+	maxTailLines = 10000
+	// maxTailBytes bounds how much of the end of a log is read to find the tail
+	maxTailBytes = 1 << 20
+)
+
+// GetExecutionLogs handles GET /executions/:id/logs. The log is served as plain
+// text with support for byte ranges; ?tail=N returns only the last N lines.
+// Logs are readable while the execution runs when the log store allows it.
+func GetExecutionLogs(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	executionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid execution ID format",
+		})
+		return
+	}
+
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	tail := 0
+	if value := c.Query("tail"); value != "" {
+		tail, err = strconv.Atoi(value)
+		if err != nil || tail < 1 || tail > maxTailLines {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid tail parameter",
+				"details": fmt.Sprintf("tail must be between 1 and %d", maxTailLines),
+			})
+			return
+		}
+	}
+
+	// Executions of other users' jobs are reported as missing rather than forbidden
+	execution, err := app.Queries.GetExecution(c.Request.Context(), executionID)
+	if err == nil {
+		_, err = app.Queries.GetJob(c.Request.Context(), database.GetJobParams{
+			ID:      execution.JobID,
+			OwnerID: ownerID,
+		})
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Execution not found",
+		})
+		return
+	}
+
+	if app.Logs == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Log storage is not configured",
+		})
+		return
+	}
+
+	object, err := app.Logs.Open(c.Request.Context(), logstore.Key(execution.ID))
+	if errors.Is(err, logstore.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Logs not found",
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open logs for execution %s: %v", execution.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to open logs",
+		})
+		return
+	}
+	defer object.Close()
+
+	serveLog(c, object, tail)
+}
+
+// serveLog writes a log as plain text: its last tail lines, or the whole log with
+// Range and conditional request handling when tail is 0
+func serveLog(c *gin.Context, object logstore.Object, tail int) {
+	if tail == 0 {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		http.ServeContent(c.Writer, c.Request, "", object.ModTime(), object)
+		return
+	}
+
+	content, err := readTail(object, tail)
+	if err != nil {
+		log.Printf("Failed to read logs for execution %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to read logs",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", content)
+}
+
+// readTail returns the last n lines of a log, looking no further back than
+// maxTailBytes from its end
+func readTail(object logstore.Object, n int) ([]byte, error) {
+	start := object.Size() - maxTailBytes
+	if start < 0 {
+		start = 0
+	}
+
+	if _, err := object.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(io.LimitReader(object, object.Size()-start))
+	if err != nil {
+		return nil, err
+	}
+
+	// The window may begin mid-line
+	if start > 0 {
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			content = content[i+1:]
+		}
+	}
+
+	return lastLines(content, n), nil
+}
+
+// lastLines returns the suffix of content holding its last n lines. A trailing
+// newline does not start a new line.
+func lastLines(content []byte, n int) []byte {
+	end := len(content)
+	if end > 0 && content[end-1] == '\n' {
+		end--
+	}
+
+	for i := end - 1; i >= 0; i-- {
+		if content[i] == '\n' {
+			n--
+			if n == 0 {
+				return content[i+1:]
+			}
+		}
+	}
+	return content
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/logstore"
+)
+
+func openTestLog(t *testing.T, content string) logstore.Object {
+	store, err := logstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	w, err := store.Create(context.Background(), "executions/test.log")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	object, err := store.Open(context.Background(), "executions/test.log")
+	require.NoError(t, err)
+	t.Cleanup(func() { object.Close() })
+	return object
+}
+
+func TestServeLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	content := "line 1\nline 2\nline 3\n"
+
+	tests := []struct {
+		name        string
+		rangeHeader string
+		tail        int
+		wantStatus  int
+		wantBody    string
+	}{
+		{"whole log", "", 0, http.StatusOK, content},
+		{"byte range", "bytes=7-12", 0, http.StatusPartialContent, "line 2"},
+		{"open range", "bytes=14-", 0, http.StatusPartialContent, "line 3\n"},
+		{"unsatisfiable range", "bytes=100-", 0, http.StatusRequestedRangeNotSatisfiable, ""},
+		{"tail", "", 2, http.StatusOK, "line 2\nline 3\n"},
+		{"tail longer than log", "", 10, http.StatusOK, content},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/executions/x/logs", nil)
+			if tt.rangeHeader != "" {
+				c.Request.Header.Set("Range", tt.rangeHeader)
+			}
+
+			serveLog(c, openTestLog(t, content), tt.tail)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+			assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+		})
+	}
+}
+
+// brokenLog is a log whose reads fail
+type brokenLog struct{ logstore.Object }
+
+func (brokenLog) Read(p []byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func TestServeLog_ReadFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/executions/x/logs?tail=5", nil)
+
+	serveLog(c, brokenLog{openTestLog(t, "line 1\n")}, 5)
+
+	// The error is reported as JSON without leaking its cause
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "application/json"))
+	assert.JSONEq(t, `{"error": "Failed to read logs"}`, w.Body.String())
+}
+
+func TestReadTail_LimitsWindow(t *testing.T) {
+	// A log longer than the tail window: the partial line at the window start is dropped
+	long := strings.Repeat("x", maxTailBytes) + "\nlast\n"
+
+	content, err := readTail(openTestLog(t, long), maxTailLines)
+	require.NoError(t, err)
+	assert.Equal(t, "last\n", string(content))
+}
+
+func TestLastLines(t *testing.T) {
+	assert.Equal(t, "c\n", string(lastLines([]byte("a\nb\nc\n"), 1)))
+	assert.Equal(t, "b\nc", string(lastLines([]byte("a\nb\nc"), 2)))
+	assert.Equal(t, "", string(lastLines(nil, 3)))
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/auth"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/mailer"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// passwordResetTimeout bounds the work done for a reset request after the
+// response has been sent
+const passwordResetTimeout = time.Minute
+
+// ForgotPasswordHandler handles POST /auth/forgot-password. The response is the
+// same whether or not an account uses the email, and is sent before the lookup
+// so its timing does not tell either.
+func ForgotPasswordHandler(c *gin.Context, app *app.App) {
+	var req models.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), passwordResetTimeout)
+	ipAddress := parseIPToNetip(c.ClientIP())
+	go func() {
+		defer cancel()
+		if err := requestPasswordReset(ctx, app, req.Email, ipAddress); err != nil {
+			log.Printf("Failed to process password reset request: %v", err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPasswordHandler handles POST /auth/reset-password. A successful reset signs
+// the user out of every session.
+func ResetPasswordHandler(c *gin.Context, app *app.App) {
+	var req models.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Validate new password strength
+	passwordManager := auth.NewPasswordManager()
+	if err := passwordManager.ValidatePasswordStrength(req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Hash new password
+	hashedPassword, err := passwordManager.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process new password",
+		})
+		return
+	}
+
+	err = resetPassword(c.Request.Context(), app, auth.HashToken(req.Token), hashedPassword)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or expired reset token",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reset password",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully. Please log in again.",
+	})
+}
+
+// requestPasswordReset emails a reset link to the active account using email, if
+// there is one. Links sent earlier stop working.
+func requestPasswordReset(ctx context.Context, app *app.App, email string, ipAddress *netip.Addr) error {
+	user, err := app.Queries.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	tx, err := app.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := app.Queries.WithTx(tx)
+
+	if err := queries.InvalidateUserPasswordResetTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	_, err = queries.CreatePasswordResetToken(ctx, database.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(token),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(app.Accounts.PasswordResetTTL), Valid: true},
+		IpAddress: ipAddress,
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	msg, err := passwordResetEmail(user.Email, app.Accounts.ResetPasswordURL, token, app.Accounts.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	return app.Mailer.Send(ctx, msg)
+}
+
+// resetPassword consumes the reset token stored under tokenHash and sets the
+// owner's password, revoking their refresh tokens. It returns pgx.ErrNoRows if
+// the token is unknown, used or expired, or the account is deactivated.
+func resetPassword(ctx context.Context, app *app.App, tokenHash, hashedPassword string) error {
+	tx, err := app.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := app.Queries.WithTx(tx)
+
+	token, err := queries.ConsumePasswordResetToken(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	if _, err := queries.UpdateUserPassword(ctx, database.UpdateUserPasswordParams{
+		ID:             token.UserID,
+		HashedPassword: hashedPassword,
+	}); err != nil {
+		return err
+	}
+
+	// Other outstanding links must not undo this reset
+	if err := queries.InvalidateUserPasswordResetTokens(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	// Revoke all existing refresh tokens for security
+	if err := queries.RevokeAllUserRefreshTokens(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	// Following the emailed link proves control of the address
+	if err := queries.UpdateUserEmailVerified(ctx, database.UpdateUserEmailVerifiedParams{
+		ID:            token.UserID,
+		EmailVerified: true,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// passwordResetEmail composes the email carrying a reset link
+func passwordResetEmail(to, resetURL, token string, ttl time.Duration) (mailer.Message, error) {
+	link, err := linkWithToken(resetURL, token)
+	if err != nil {
+		return mailer.Message{}, err
+	}
+
+	return mailer.Message{
+		To:      to,
+		Subject: "Reset your Veridian password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your Veridian account. "+
+			"To choose a new password, open the link below:\n\n%s\n\n"+
+			"The link can be used once and expires in %s. If you did not ask for a reset, "+
+			"you can ignore this email; your password has not been changed.\n",
+			link, ttl),
+	}, nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordResetEmail(t *testing.T) {
+	msg, err := passwordResetEmail("ada@example.com", "https://app.example.com/reset-password", "tok_123", 30*time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ada@example.com", msg.To)
+	assert.Contains(t, msg.Body, "https://app.example.com/reset-password?token=tok_123")
+	assert.Contains(t, msg.Body, "30m0s")
+
+	_, err = passwordResetEmail("ada@example.com", "://bad", "tok_123", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestLinkWithToken(t *testing.T) {
+	link, err := linkWithToken("https://app.example.com/reset?lang=en", "a+b/c")
+	require.NoError(t, err)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	assert.Equal(t, "en", u.Query().Get("lang"))
+	assert.Equal(t, "a+b/c", u.Query().Get("token"))
+}
+
+func TestResetPasswordHandler_RejectsInvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testApp, _ := newVerificationTestApp()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing token", `{"new_password":"Str0ng!Passw0rd"}`},
+		{"short password", `{"token":"abc","new_password":"short"}`},
+		{"weak password", `{"token":"abc","new_password":"alllowercase"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/auth/reset-password", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			ResetPasswordHandler(c, testApp)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
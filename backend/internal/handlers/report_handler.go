@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/report"
+)
+
+const defaultReportRange = 365 * 24 * time.Hour
+
+// GetSustainabilityReport handles GET /reports/sustainability. The report covers
+// executions completed in the from/to range (default: the last 365 days) and is
+// streamed as CSV (default) or JSON.
+func GetSustainabilityReport(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	from, to, err := parseTimeRange(c, time.Now(), defaultReportRange)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid report query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	format, err := report.ParseFormat(c.DefaultQuery("format", string(report.FormatCSV)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid report query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", reportDisposition(from, to, format))
+	c.Status(http.StatusOK)
+
+	period := report.Period{From: from, To: to}
+	if err := report.Generate(c.Request.Context(), app.Queries, ownerID, period, format, c.Writer); err != nil {
+		// The response has started, so the truncated body is all the client sees
+		_ = c.Error(fmt.Errorf("sustainability report for user %s: %w", ownerID, err))
+		c.Abort()
+	}
+}
+
+// reportDisposition names the report download after its period
+func reportDisposition(from, to time.Time, format report.Format) string {
+	return fmt.Sprintf(`attachment; filename="sustainability-report-%s-%s.%s"`, from.Format("20060102"), to.Format("20060102"), format)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guregu/null/v6"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// GetJobExecutionSavings handles GET /jobs/:id/executions/:execution_id/savings
+func GetJobExecutionSavings(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	execution, ok := loadOwnedExecution(c, app)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toExecutionSavings(execution))
+}
+
+// GetUserSavings handles GET /savings
+func GetUserSavings(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	totals, err := app.Queries.GetUserSavings(c.Request.Context(), ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch savings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserSavings(totals))
+}
+
+// toExecutionSavings compares an execution's actual cost and carbon with its baseline
+func toExecutionSavings(execution database.Execution) models.ExecutionSavings {
+	return models.ExecutionSavings{
+		ExecutionID: execution.ID,
+		Actual: models.Footprint{
+			Region:   execution.CloudRegion,
+			VMType:   execution.VmType,
+			StartAt:  execution.StartedAt.Ptr(),
+			CostUSD:  execution.CostActualUsd.Ptr(),
+			CarbonKg: execution.CarbonEmittedKg.Ptr(),
+		},
+		Baseline: models.Footprint{
+			Region:   execution.BaselineRegion,
+			VMType:   execution.BaselineVmType,
+			StartAt:  execution.BaselineStartAt.Ptr(),
+			CostUSD:  execution.BaselineCostUsd.Ptr(),
+			CarbonKg: execution.BaselineCarbonKg.Ptr(),
+		},
+		CostSavingsUSD:  saving(execution.BaselineCostUsd, execution.CostActualUsd),
+		CarbonSavingsKg: saving(execution.BaselineCarbonKg, execution.CarbonEmittedKg),
+	}
+}
+
+// toUserSavings converts savings totals to their API representation
+func toUserSavings(totals database.GetUserSavingsRow) models.UserSavings {
+	return models.UserSavings{
+		Executions:       totals.Executions,
+		BaselineCostUSD:  totals.BaselineCost,
+		ActualCostUSD:    totals.ActualCost,
+		CostSavingsUSD:   roundSaving(totals.BaselineCost - totals.ActualCost),
+		BaselineCarbonKg: totals.BaselineCarbon,
+		ActualCarbonKg:   totals.ActualCarbon,
+		CarbonSavingsKg:  roundSaving(totals.BaselineCarbon - totals.ActualCarbon),
+	}
+}
+
+// saving returns baseline minus actual, or nil unless both are known
+func saving(baseline, actual null.Float) *float64 {
+	if !baseline.Valid || !actual.Valid {
+		return nil
+	}
+	value := roundSaving(baseline.Float64 - actual.Float64)
+	return &value
+}
+
+// roundSaving drops float noise beyond the six decimal places stored for costs and emissions
+func roundSaving(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestToExecutionSavings(t *testing.T) {
+	region, baselineRegion, vmType := "swedencentral", "westeurope", "Standard_D2s_v5"
+	submitted := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	execution := database.Execution{
+		ID:               uuid.New(),
+		CloudRegion:      &region,
+		VmType:           &vmType,
+		StartedAt:        null.TimeFrom(submitted.Add(4 * time.Hour)),
+		CostActualUsd:    null.FloatFrom(0.2),
+		CarbonEmittedKg:  null.FloatFrom(0.0011),
+		BaselineRegion:   &baselineRegion,
+		BaselineVmType:   &vmType,
+		BaselineStartAt:  null.TimeFrom(submitted),
+		BaselineCostUsd:  null.FloatFrom(0.192),
+		BaselineCarbonKg: null.FloatFrom(0.0096),
+	}
+
+	result := toExecutionSavings(execution)
+
+	assert.Equal(t, execution.ID, result.ExecutionID)
+	assert.Equal(t, &region, result.Actual.Region)
+	assert.Equal(t, &baselineRegion, result.Baseline.Region)
+	require.NotNil(t, result.Baseline.StartAt)
+	assert.Equal(t, submitted, *result.Baseline.StartAt)
+
+	// Savings are rounded and may be negative when the baseline was cheaper
+	require.NotNil(t, result.CostSavingsUSD)
+	assert.Equal(t, -0.008, *result.CostSavingsUSD)
+	require.NotNil(t, result.CarbonSavingsKg)
+	assert.Equal(t, 0.0085, *result.CarbonSavingsKg)
+
+	// Without a baseline figure there is nothing to compare
+	execution.BaselineCarbonKg = null.Float{}
+	assert.Nil(t, toExecutionSavings(execution).CarbonSavingsKg)
+}
+
+func TestToUserSavings(t *testing.T) {
+	result := toUserSavings(database.GetUserSavingsRow{
+		Executions:     3,
+		BaselineCost:   0.9,
+		ActualCost:     0.7,
+		BaselineCarbon: 0.03,
+		ActualCarbon:   0.01,
+	})
+
+	assert.Equal(t, int64(3), result.Executions)
+	assert.Equal(t, 0.2, result.CostSavingsUSD)
+	assert.Equal(t, 0.02, result.CarbonSavingsKg)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// GetSessionsHandler handles GET /auth/sessions, listing the devices and clients
+// signed in to the caller's account, most recently active first
+func GetSessionsHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	currentID, _ := middleware.GetSessionIDFromContext(c)
+
+	tokens, err := app.Queries.GetUserRefreshTokens(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": toAPISessions(tokens, currentID),
+	})
+}
+
+// RevokeSessionHandler handles DELETE /auth/sessions/:id. The session cannot be
+// refreshed afterwards; access tokens already issued to it stay valid until they
+// expire.
+func RevokeSessionHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid session ID format",
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+
+	revoked, err := app.Queries.RevokeUserRefreshTokenFamily(c.Request.Context(), database.RevokeUserRefreshTokenFamilyParams{
+		UserID:   userID,
+		FamilyID: sessionID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke session",
+		})
+		return
+	}
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Session not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessionsHandler handles POST /auth/sessions/revoke-others, signing
+// the caller out everywhere except the session making the request
+func RevokeOtherSessionsHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	currentID, ok := middleware.GetSessionIDFromContext(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Current session is unknown. Please log in again.",
+		})
+		return
+	}
+
+	if _, err := app.Queries.RevokeOtherUserRefreshTokens(c.Request.Context(), database.RevokeOtherUserRefreshTokensParams{
+		UserID:   userID,
+		FamilyID: currentID,
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Signed out of all other sessions",
+	})
+}
+
+// toAPISessions converts active refresh tokens to sessions. A session normally
+// has one active token; if concurrent refreshes left more, the newest is used.
+func toAPISessions(tokens []database.RefreshToken, currentID uuid.UUID) []models.Session {
+	sessions := make([]models.Session, 0, len(tokens))
+	seen := make(map[uuid.UUID]bool, len(tokens))
+	for _, token := range tokens {
+		if seen[token.FamilyID] {
+			continue
+		}
+		seen[token.FamilyID] = true
+
+		session := models.Session{
+			ID:           token.FamilyID,
+			UserAgent:    token.UserAgent,
+			LastActiveAt: token.CreatedAt,
+			ExpiresAt:    token.ExpiresAt.Time,
+			Current:      token.FamilyID == currentID,
+		}
+		if token.IpAddress != nil {
+			ip := token.IpAddress.String()
+			session.IPAddress = &ip
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestToAPISessions(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	current, other := uuid.New(), uuid.New()
+	userAgent := "Mozilla/5.0"
+	ip := netip.MustParseAddr("192.0.2.10")
+
+	// Newest first, as returned by GetUserRefreshTokens
+	tokens := []database.RefreshToken{
+		{FamilyID: current, CreatedAt: now, ExpiresAt: pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true}, UserAgent: &userAgent, IpAddress: &ip},
+		{FamilyID: other, CreatedAt: now.Add(-time.Hour)},
+		{FamilyID: current, CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	sessions := toAPISessions(tokens, current)
+	require.Len(t, sessions, 2)
+
+	assert.Equal(t, current, sessions[0].ID)
+	assert.True(t, sessions[0].Current)
+	assert.Equal(t, now, sessions[0].LastActiveAt)
+	assert.Equal(t, now.Add(time.Hour), sessions[0].ExpiresAt)
+	assert.Equal(t, &userAgent, sessions[0].UserAgent)
+	require.NotNil(t, sessions[0].IPAddress)
+	assert.Equal(t, "192.0.2.10", *sessions[0].IPAddress)
+
+	assert.Equal(t, other, sessions[1].ID)
+	assert.False(t, sessions[1].Current)
+	assert.Nil(t, sessions[1].IPAddress)
+}
+
+func TestRevokeOtherSessionsHandler_RequiresSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/sessions/revoke-others", nil)
+	c.Set("user_id", uuid.New())
+	c.Set("session_id", uuid.Nil) // Token issued before sessions were tracked
+
+	RevokeOtherSessionsHandler(c, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// pgCheckViolation is the Postgres SQLSTATE for a failed CHECK constraint
+const pgCheckViolation = "23514"
+
+// GetSettings handles GET /settings
+func GetSettings(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	// Get authenticated user ID from JWT token
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Users get default weights the first time their settings are read
+	if err := app.Queries.EnsureUserSettings(ctx, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch settings",
+		})
+		return
+	}
+
+	settings, err := app.Queries.GetUserSettings(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UserSettings{
+		CostWeight:   settings.CostWeight,
+		CarbonWeight: settings.CarbonWeight,
+		UpdatedAt:    settings.UpdatedAt,
+	})
+}
+
+// UpdateSettings handles PUT /settings
+func UpdateSettings(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	var req models.UpdateSettingsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Get authenticated user ID from JWT token
+	userID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	settings, err := app.Queries.UpsertUserSettings(ctx, database.UpsertUserSettingsParams{
+		UserID:       userID,
+		CostWeight:   *req.CostWeight,
+		CarbonWeight: *req.CarbonWeight,
+	})
+	if err != nil {
+		if isCheckViolation(err) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid settings",
+				"details": "cost_weight and carbon_weight must each be between 0 and 1 and sum to 1",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UserSettings{
+		CostWeight:   settings.CostWeight,
+		CarbonWeight: settings.CarbonWeight,
+		UpdatedAt:    settings.UpdatedAt,
+	})
+}
+
+// isCheckViolation reports whether err is a Postgres CHECK constraint failure
+func isCheckViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgCheckViolation
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+func TestUpdateSettingsRequest_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/settings/validate", func(c *gin.Context) {
+		var req models.UpdateSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Validation passed"})
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"balanced", `{"cost_weight": 0.5, "carbon_weight": 0.5}`, http.StatusOK},
+		{"carbon only", `{"cost_weight": 0, "carbon_weight": 1}`, http.StatusOK},
+		{"missing carbon weight", `{"cost_weight": 1}`, http.StatusBadRequest},
+		{"negative weight", `{"cost_weight": -0.5, "carbon_weight": 1.5}`, http.StatusBadRequest},
+		{"not a number", `{"cost_weight": "half", "carbon_weight": 0.5}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/settings/validate", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestIsCheckViolation(t *testing.T) {
+	constraintErr := &pgconn.PgError{Code: "23514", ConstraintName: "weights_must_sum_to_one"}
+
+	assert.True(t, isCheckViolation(constraintErr))
+	assert.True(t, isCheckViolation(fmt.Errorf("upsert failed: %w", constraintErr)))
+	assert.False(t, isCheckViolation(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, isCheckViolation(fmt.Errorf("connection refused")))
+}
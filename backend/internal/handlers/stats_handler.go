@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+const (
+	defaultStatsRange = 30 * 24 * time.Hour
+	maxStatsBuckets   = 400
+)
+
+// statsQuery is the time range and optional bucketing of a stats request
+type statsQuery struct {
+	From   time.Time
+	To     time.Time
+	Bucket string
+}
+
+// GetStats handles GET /stats
+func GetStats(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	query, err := parseStatsQuery(c, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid stats query",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	totalJobs, err := app.Queries.GetJobCount(ctx, ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch stats",
+		})
+		return
+	}
+
+	totals, err := app.Queries.GetUserExecutionStats(ctx, database.GetUserExecutionStatsParams{
+		OwnerID:     ownerID,
+		CreatedFrom: query.From,
+		CreatedTo:   query.To,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch stats",
+		})
+		return
+	}
+
+	stats := toJobStats(query, totalJobs, totals)
+
+	if query.Bucket != "" {
+		rows, err := app.Queries.GetUserExecutionStatsByBucket(ctx, database.GetUserExecutionStatsByBucketParams{
+			Bucket:      query.Bucket,
+			OwnerID:     ownerID,
+			CreatedFrom: query.From,
+			CreatedTo:   query.To,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to fetch stats",
+			})
+			return
+		}
+		stats.Series = toStatsSeries(query, rows)
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// parseStatsQuery reads the optional from, to (RFC 3339) and bucket parameters.
+// The range defaults to the 30 days up to now.
+func parseStatsQuery(c *gin.Context, now time.Time) (statsQuery, error) {
+	from, to, err := parseTimeRange(c, now, defaultStatsRange)
+	if err != nil {
+		return statsQuery{}, err
+	}
+	query := statsQuery{From: from, To: to}
+
+	switch bucket := c.Query("bucket"); bucket {
+	case "":
+	case models.StatsBucketDay, models.StatsBucketWeek, models.StatsBucketMonth:
+		query.Bucket = bucket
+		if _, err := bucketStarts(query); err != nil {
+			return statsQuery{}, err
+		}
+	default:
+		return statsQuery{}, errors.New("bucket must be one of day, week or month")
+	}
+
+	return query, nil
+}
+
+// parseTimeRange reads the optional from and to (RFC 3339) parameters, converted
+// to UTC. To defaults to now and from to defaultRange before to.
+func parseTimeRange(c *gin.Context, now time.Time, defaultRange time.Duration) (time.Time, time.Time, error) {
+	to := now.UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be an RFC 3339 timestamp")
+		}
+		to = parsed.UTC()
+	}
+
+	from := to.Add(-defaultRange)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be an RFC 3339 timestamp")
+		}
+		from = parsed.UTC()
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
+
+// truncateBucket returns the start of the UTC bucket containing t, matching
+// Postgres date_trunc: days at midnight, weeks on Monday, months on the first
+func truncateBucket(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch bucket {
+	case models.StatsBucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.StatsBucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// bucketStarts lists the start of every bucket overlapping the query range. It
+// gives up as soon as the range spans more than maxStatsBuckets buckets.
+func bucketStarts(query statsQuery) ([]time.Time, error) {
+	var starts []time.Time
+	for start := truncateBucket(query.From, query.Bucket); start.Before(query.To); {
+		if len(starts) == maxStatsBuckets {
+			return nil, fmt.Errorf("range spans more than %d %s buckets", maxStatsBuckets, query.Bucket)
+		}
+		starts = append(starts, start)
+
+		switch query.Bucket {
+		case models.StatsBucketWeek:
+			start = start.AddDate(0, 0, 7)
+		case models.StatsBucketMonth:
+			start = start.AddDate(0, 1, 0)
+		default:
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return starts, nil
+}
+
+// toJobStats converts execution totals to the dashboard representation
+func toJobStats(query statsQuery, totalJobs int64, totals database.GetUserExecutionStatsRow) models.JobStats {
+	return models.JobStats{
+		From:                       query.From,
+		To:                         query.To,
+		TotalJobs:                  totalJobs,
+		TotalExecutions:            totals.TotalExecutions,
+		PendingExecutions:          totals.PendingExecutions,
+		EvaluatingExecutions:       totals.EvaluatingExecutions,
+		RunningExecutions:          totals.RunningExecutions,
+		CompletedSuccessExecutions: totals.SuccessfulExecutions,
+		CompletedErrorExecutions:   totals.FailedExecutions,
+		OrphanedExecutions:         totals.OrphanedExecutions,
+		TotalCostUSD:               totals.TotalCost,
+		TotalCarbonEmittedKg:       totals.TotalCarbon,
+		TotalCostSavingsUSD:        roundSaving(totals.CostSavings),
+		TotalCarbonSavingsKg:       roundSaving(totals.CarbonSavings),
+		AvgDeferralHours:           totals.AvgDeferralSeconds / time.Hour.Seconds(),
+		Bucket:                     query.Bucket,
+	}
+}
+
+// toStatsSeries lays per-bucket totals over every bucket in the range, so buckets
+// without executions appear as zeros rather than gaps
+func toStatsSeries(query statsQuery, rows []database.GetUserExecutionStatsByBucketRow) []models.StatsBucket {
+	byStart := make(map[time.Time]database.GetUserExecutionStatsByBucketRow, len(rows))
+	for _, row := range rows {
+		byStart[row.BucketStart.Time.UTC()] = row
+	}
+
+	starts, _ := bucketStarts(query) // The range was checked by parseStatsQuery
+	series := make([]models.StatsBucket, 0, len(starts))
+	for _, start := range starts {
+		row := byStart[start]
+		series = append(series, models.StatsBucket{
+			Start:                      start,
+			TotalExecutions:            row.TotalExecutions,
+			CompletedSuccessExecutions: row.SuccessfulExecutions,
+			CompletedErrorExecutions:   row.FailedExecutions,
+			TotalCostUSD:               row.TotalCost,
+			TotalCarbonEmittedKg:       row.TotalCarbon,
+			TotalCostSavingsUSD:        roundSaving(row.CostSavings),
+			TotalCarbonSavingsKg:       roundSaving(row.CarbonSavings),
+			AvgDeferralHours:           row.AvgDeferralSeconds / time.Hour.Seconds(),
+		})
+	}
+	return series
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+func TestParseStatsQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2025, 3, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectError  bool
+	}{
+		{"defaults to last 30 days", "", now.AddDate(0, 0, -30), now, false},
+		{"explicit range", "?from=2025-01-01T00:00:00Z&to=2025-02-01T00:00:00Z", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		{"offset converted to UTC", "?from=2025-03-01T02:00:00%2B02:00", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), now, false},
+		{"month buckets", "?from=2024-01-01T00:00:00Z&bucket=month", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), now, false},
+		{"not a timestamp", "?from=yesterday", time.Time{}, time.Time{}, true},
+		{"from after to", "?from=2025-03-16T00:00:00Z", time.Time{}, time.Time{}, true},
+		{"unknown bucket", "?bucket=hour", time.Time{}, time.Time{}, true},
+		{"too many buckets", "?from=2020-01-01T00:00:00Z&bucket=day", time.Time{}, time.Time{}, true},
+		{"huge range", "?from=0001-01-01T00:00:00Z&to=9999-12-31T00:00:00Z&bucket=day", time.Time{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil)
+
+			query, err := parseStatsQuery(c, now)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, query.From)
+			assert.Equal(t, tt.expectedTo, query.To)
+		})
+	}
+}
+
+func TestBucketStarts(t *testing.T) {
+	// Wednesday 5 March to Tuesday 18 March 2025
+	from := time.Date(2025, 3, 5, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 18, 9, 0, 0, 0, time.UTC)
+
+	weeks, err := bucketStarts(statsQuery{From: from, To: to, Bucket: models.StatsBucketWeek})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+	}, weeks)
+
+	days, err := bucketStarts(statsQuery{From: from, To: to, Bucket: models.StatsBucketDay})
+	require.NoError(t, err)
+	assert.Len(t, days, 14)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), days[0])
+
+	months, err := bucketStarts(statsQuery{From: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), To: to, Bucket: models.StatsBucketMonth})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, months)
+
+	// Huge ranges are rejected without listing every bucket
+	_, err = bucketStarts(statsQuery{From: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), Bucket: models.StatsBucketDay})
+	assert.ErrorContains(t, err, "more than 400 day buckets")
+}
+
+func TestToStatsSeries_FillsEmptyBuckets(t *testing.T) {
+	query := statsQuery{
+		From:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:     time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+		Bucket: models.StatsBucketDay,
+	}
+	rows := []database.GetUserExecutionStatsByBucketRow{
+		{
+			BucketStart:        pgtype.Timestamptz{Time: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+			TotalExecutions:    4,
+			CostSavings:        0.30000000000000004,
+			AvgDeferralSeconds: 5400,
+		},
+	}
+
+	series := toStatsSeries(query, rows)
+
+	require.Len(t, series, 3)
+	assert.Zero(t, series[0].TotalExecutions)
+	assert.Equal(t, int64(4), series[1].TotalExecutions)
+	assert.Equal(t, 0.3, series[1].TotalCostSavingsUSD)
+	assert.Equal(t, 1.5, series[1].AvgDeferralHours)
+	assert.Zero(t, series[2].TotalExecutions)
+}
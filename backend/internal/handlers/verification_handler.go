@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/mailer"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+)
+
+// VerifyEmailHandler handles POST /auth/verify-email
+func VerifyEmailHandler(c *gin.Context, app *app.App) {
+	var req models.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	userID, email, err := app.JWTManager.ValidateEmailVerificationToken(req.Token)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or expired verification token",
+		})
+		return
+	}
+
+	// Links sent to an address the account no longer uses are void
+	user, err := app.Queries.GetUserByID(ctx, userID)
+	if err != nil || user.Email != email {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or expired verification token",
+		})
+		return
+	}
+
+	if !user.EmailVerified {
+		err = app.Queries.UpdateUserEmailVerified(ctx, database.UpdateUserEmailVerifiedParams{
+			ID:            user.ID,
+			EmailVerified: true,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to verify email",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerificationHandler handles POST /auth/resend-verification, emailing a new
+// verification link to the authenticated user
+func ResendVerificationHandler(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	ctx := c.Request.Context()
+
+	user, err := app.Queries.GetUserByID(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+		return
+	}
+
+	if user.EmailVerified {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Email is already verified",
+		})
+		return
+	}
+
+	if err := sendVerificationEmail(ctx, app, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to send verification email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// sendVerificationEmail emails user a link that verifies their current address
+func sendVerificationEmail(ctx context.Context, app *app.App, user database.User) error {
+	token, _, err := app.JWTManager.GenerateEmailVerificationToken(user.ID, user.Email, app.Accounts.VerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := linkWithToken(app.Accounts.VerifyEmailURL, token)
+	if err != nil {
+		return err
+	}
+
+	return app.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Veridian email address",
+		Body: fmt.Sprintf("Confirm that this is your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. If you did not create a Veridian account, you can ignore this email.\n",
+			link, app.Accounts.VerificationTTL),
+	})
+}
+
+// linkWithToken adds token to the query string of the page at base
+func linkWithToken(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid link base %q: %w", base, err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
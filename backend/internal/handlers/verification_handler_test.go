@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/auth"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/mailer"
+)
+
+func newVerificationTestApp() (*app.App, *mailer.MemoryMailer) {
+	mail := mailer.NewMemoryMailer()
+	return &app.App{
+		JWTManager: auth.NewJWTManager("test-secret-key-32-characters-long", "test-issuer", "test-audience", time.Minute, time.Hour),
+		Mailer:     mail,
+		Accounts: app.AccountConfig{
+			VerifyEmailURL:  "https://app.example.com/verify-email?lang=en",
+			VerificationTTL: time.Hour,
+		},
+	}, mail
+}
+
+func TestSendVerificationEmail(t *testing.T) {
+	testApp, mail := newVerificationTestApp()
+	user := database.User{ID: uuid.New(), Email: "ada@example.com"}
+
+	require.NoError(t, sendVerificationEmail(context.Background(), testApp, user))
+
+	messages := mail.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "ada@example.com", messages[0].To)
+
+	// The emailed link carries a token for this user and address
+	var link *url.URL
+	for _, field := range strings.Fields(messages[0].Body) {
+		if strings.HasPrefix(field, "https://") {
+			link, _ = url.Parse(field)
+		}
+	}
+	require.NotNil(t, link)
+	assert.Equal(t, "/verify-email", link.Path)
+	assert.Equal(t, "en", link.Query().Get("lang"))
+
+	userID, email, err := testApp.JWTManager.ValidateEmailVerificationToken(link.Query().Get("token"))
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, userID)
+	assert.Equal(t, user.Email, email)
+}
+
+func TestVerifyEmailHandler_InvalidToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	testApp, _ := newVerificationTestApp()
+
+	for _, body := range []string{`{}`, `{"token":"not-a-token"}`} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/auth/verify-email", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		VerifyEmailHandler(c, testApp)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/middleware"
+	"github.com/nouvadev/veridian/backend/internal/models"
+	"github.com/nouvadev/veridian/backend/internal/webhook"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// CreateWebhook handles POST /webhooks
+func CreateWebhook(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook URL",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid webhook events",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Get authenticated user ID from JWT token
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	secret := ""
+	if req.Secret != nil {
+		secret = *req.Secret
+	} else if secret, err = webhook.GenerateSecret(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate webhook secret",
+		})
+		return
+	}
+
+	subscription, err := app.Queries.CreateWebhookSubscription(c.Request.Context(), database.CreateWebhookSubscriptionParams{
+		OwnerID: ownerID,
+		Url:     req.URL,
+		Secret:  secret,
+		Events:  events,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create webhook",
+		})
+		return
+	}
+
+	// The secret is shown once, so the receiver can verify signatures
+	response := toAPIWebhookSubscription(subscription)
+	response.Secret = subscription.Secret
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetWebhooks handles GET /webhooks
+func GetWebhooks(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	// Get authenticated user ID from JWT token
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	subscriptions, err := app.Queries.ListWebhookSubscriptions(c.Request.Context(), ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhooks",
+		})
+		return
+	}
+
+	apiSubscriptions := make([]models.WebhookSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		apiSubscriptions[i] = toAPIWebhookSubscription(subscription)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": apiSubscriptions,
+	})
+}
+
+// GetWebhook handles GET /webhooks/:id
+func GetWebhook(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	subscription, ok := loadOwnedWebhook(c, app)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, toAPIWebhookSubscription(subscription))
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id. Pending deliveries are discarded.
+func DeleteWebhook(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID format",
+		})
+		return
+	}
+
+	// Get authenticated user ID from JWT token
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	deleted, err := app.Queries.DeleteWebhookSubscription(c.Request.Context(), database.DeleteWebhookSubscriptionParams{
+		ID:      subscriptionID,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete webhook",
+		})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries handles GET /webhooks/:id/deliveries, newest first
+func GetWebhookDeliveries(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	limit, offset, err := parsePagination(c, defaultDeliveriesLimit, maxDeliveriesLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	subscription, ok := loadOwnedWebhook(c, app)
+	if !ok {
+		return
+	}
+
+	deliveries, err := app.Queries.ListWebhookDeliveries(c.Request.Context(), database.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhook deliveries",
+		})
+		return
+	}
+
+	apiDeliveries := make([]models.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		apiDeliveries[i] = toAPIWebhookDelivery(delivery)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": apiDeliveries,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// GetWebhookDeliveryAttempts handles GET /webhooks/:id/deliveries/:delivery_id/attempts
+func GetWebhookDeliveryAttempts(c *gin.Context, app *app.App) {
+	if !middleware.RequireAuth(c) {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid delivery ID format",
+		})
+		return
+	}
+
+	subscription, ok := loadOwnedWebhook(c, app)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	delivery, err := app.Queries.GetWebhookDelivery(ctx, database.GetWebhookDeliveryParams{
+		ID:             deliveryID,
+		SubscriptionID: subscription.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Delivery not found",
+		})
+		return
+	}
+
+	attempts, err := app.Queries.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch delivery attempts",
+		})
+		return
+	}
+
+	apiAttempts := make([]models.WebhookDeliveryAttempt, len(attempts))
+	for i, attempt := range attempts {
+		apiAttempts[i] = models.WebhookDeliveryAttempt{
+			ID:          attempt.ID,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMs:  attempt.DurationMs,
+			AttemptedAt: attempt.AttemptedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": toAPIWebhookDelivery(delivery),
+		"attempts": apiAttempts,
+	})
+}
+
+// loadOwnedWebhook resolves the :id path parameter to a webhook subscription owned
+// by the authenticated user. On failure it writes the error response and returns
+// false.
+func loadOwnedWebhook(c *gin.Context, app *app.App) (database.WebhookSubscription, bool) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID format",
+		})
+		return database.WebhookSubscription{}, false
+	}
+
+	// Get authenticated user ID from JWT token
+	ownerID, exists := middleware.GetUserIDFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return database.WebhookSubscription{}, false
+	}
+
+	subscription, err := app.Queries.GetWebhookSubscription(c.Request.Context(), database.GetWebhookSubscriptionParams{
+		ID:      subscriptionID,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return database.WebhookSubscription{}, false
+	}
+
+	return subscription, true
+}
+
+// validateWebhookURL requires an absolute http or https URL with a public host.
+// Hostnames are resolved and checked again by the dispatcher on every delivery.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url must use http or https")
+	}
+	if u.Host == "" {
+		return errors.New("url must include a host")
+	}
+	if u.User != nil {
+		return errors.New("url must not include credentials")
+	}
+	if !webhook.AllowedHost(u.Hostname()) {
+		return errors.New("url must not point to a local or private network address")
+	}
+	return nil
+}
+
+// normalizeWebhookEvents validates requested events and removes duplicates. An
+// empty list subscribes to every event.
+func normalizeWebhookEvents(requested []string) ([]string, error) {
+	events := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, event := range requested {
+		if !webhook.Event(event).Valid() {
+			return nil, fmt.Errorf("unknown event %q", event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// toAPIWebhookSubscription converts a database subscription to its API
+// representation, without the secret
+func toAPIWebhookSubscription(subscription database.WebhookSubscription) models.WebhookSubscription {
+	events := subscription.Events
+	if events == nil {
+		events = []string{}
+	}
+	return models.WebhookSubscription{
+		ID:        subscription.ID,
+		URL:       subscription.Url,
+		Events:    events,
+		IsActive:  subscription.IsActive,
+		CreatedAt: subscription.CreatedAt,
+		UpdatedAt: subscription.UpdatedAt,
+	}
+}
+
+// toAPIWebhookDelivery converts a database delivery to its API representation.
+// The next attempt time is only meaningful while the delivery is pending.
+func toAPIWebhookDelivery(delivery database.WebhookDelivery) models.WebhookDelivery {
+	apiDelivery := models.WebhookDelivery{
+		ID:          delivery.ID,
+		Event:       delivery.Event,
+		ExecutionID: delivery.ExecutionID,
+		Payload:     delivery.Payload,
+		Status:      string(delivery.Status),
+		Attempts:    delivery.Attempts,
+		LastError:   delivery.LastError,
+		DeliveredAt: delivery.DeliveredAt.Ptr(),
+		CreatedAt:   delivery.CreatedAt,
+	}
+	if delivery.Status == database.WebhookDeliveryStatusPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		apiDelivery.NextAttemptAt = &nextAttemptAt
+	}
+	return apiDelivery
+}
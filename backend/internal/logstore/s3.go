@@ -0,0 +1,277 @@
+package logstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config configures a bucket on S3 or an S3-compatible service such as MinIO
+type S3Config struct {
+	Endpoint        string        // Service URL, e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	Region          string        // Signing region, us-east-1 if unset
+	Bucket          string        // Bucket holding the logs
+	Prefix          string        // Optional key prefix, e.g. "veridian/"
+	AccessKeyID     string        // Static credentials used to sign requests
+	SecretAccessKey string        // Static credentials used to sign requests
+	PathStyle       bool          // Address the bucket in the path rather than the host name, as MinIO expects
+	Timeout         time.Duration // Per-request timeout including the body transfer, 0 for none
+}
+
+// S3Store keeps logs as objects in a bucket. Objects are uploaded in one request
+// when their writer is closed, so a log becomes readable only once its execution
+// has finished.
+type S3Store struct {
+	config   S3Config
+	endpoint *url.URL
+	signer   signer
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Store creates a store backed by the configured bucket
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("logstore: S3 endpoint is required")
+	}
+	if config.Bucket == "" {
+		return nil, errors.New("logstore: S3 bucket is required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("logstore: S3 credentials are required")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("logstore: invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return &S3Store{
+		config:   config,
+		endpoint: endpoint,
+		signer: signer{
+			accessKeyID:     config.AccessKeyID,
+			secretAccessKey: config.SecretAccessKey,
+			region:          config.Region,
+		},
+		client: &http.Client{Timeout: config.Timeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Create returns a writer that spools the log to a temporary file and uploads it
+// on Close
+func (s *S3Store) Create(ctx context.Context, key string) (io.WriteCloser, error) {
+	spool, err := os.CreateTemp("", "veridian-log-*")
+	if err != nil {
+		return nil, fmt.Errorf("logstore: failed to create spool file: %w", err)
+	}
+
+	return &s3Writer{store: s, ctx: ctx, key: key, spool: spool, hash: sha256.New()}, nil
+}
+
+// Open looks up the object for key; its content is fetched lazily, from the
+// current offset, on the first Read after opening or seeking
+func (s *S3Store) Open(ctx context.Context, key string) (Object, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, -1, emptyPayloadHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("logstore: unexpected status %d from HEAD %s", resp.StatusCode, key)
+	}
+
+	object := &s3Object{store: s, ctx: ctx, key: key, size: resp.ContentLength}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		object.modTime = modTime
+	}
+	return object, nil
+}
+
+// URI returns the s3:// URI of the object for key
+func (s *S3Store) URI(key string) string {
+	return "s3://" + s.config.Bucket + "/" + s.config.Prefix + key
+}
+
+// objectURL returns the URL of the object for key
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	objectPath := "/" + s.config.Prefix + key
+	if s.config.PathStyle {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + s.config.Bucket + objectPath
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = strings.TrimRight(u.Path, "/") + objectPath
+	}
+	u.RawPath = ""
+	return &u
+}
+
+// do sends a signed request for the object under key. A negative length sends no
+// Content-Length.
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, length int64, payloadHash string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("logstore: failed to build request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if length >= 0 {
+		req.ContentLength = length
+	}
+
+	s.signer.sign(req, payloadHash, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("logstore: %s %s failed: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+// s3Writer spools a log locally while hashing it, as the signature covers the body
+type s3Writer struct {
+	store *S3Store
+	ctx   context.Context
+	key   string
+	spool *os.File
+	hash  hash.Hash
+	size  int64
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	n, err := w.spool.Write(p)
+	w.hash.Write(p[:n])
+	w.size += int64(n)
+	return n, err
+}
+
+// Close uploads the spooled log and removes the spool file
+func (w *s3Writer) Close() error {
+	defer func() {
+		w.spool.Close()
+		os.Remove(w.spool.Name())
+	}()
+
+	if _, err := w.spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("logstore: failed to rewind spool file: %w", err)
+	}
+
+	header := http.Header{"Content-Type": {"text/plain; charset=utf-8"}}
+	payloadHash := hex.EncodeToString(w.hash.Sum(nil))
+	resp, err := w.store.do(w.ctx, http.MethodPut, w.key, io.NopCloser(w.spool), w.size, payloadHash, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("logstore: unexpected status %d from PUT %s", resp.StatusCode, w.key)
+	}
+	return nil
+}
+
+// s3Object reads an object with ranged GETs
+type s3Object struct {
+	store   *S3Store
+	ctx     context.Context
+	key     string
+	size    int64
+	modTime time.Time
+
+	offset int64
+	body   io.ReadCloser
+}
+
+func (o *s3Object) Size() int64 {
+	return o.size
+}
+
+func (o *s3Object) ModTime() time.Time {
+	return o.modTime
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+
+	if o.body == nil {
+		header := http.Header{"Range": {"bytes=" + strconv.FormatInt(o.offset, 10) + "-"}}
+		resp, err := o.store.do(o.ctx, http.MethodGet, o.key, nil, -1, emptyPayloadHash, header)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return 0, fmt.Errorf("logstore: unexpected status %d from GET %s", resp.StatusCode, o.key)
+		}
+		if resp.StatusCode == http.StatusOK && o.offset > 0 {
+			// The server ignored the range
+			if _, err := io.CopyN(io.Discard, resp.Body, o.offset); err != nil {
+				resp.Body.Close()
+				return 0, fmt.Errorf("logstore: failed to skip to offset: %w", err)
+			}
+		}
+		o.body = resp.Body
+	}
+
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	if err == io.EOF && o.offset < o.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = o.offset + offset
+	case io.SeekEnd:
+		next = o.size + offset
+	default:
+		return 0, errors.New("logstore: invalid whence")
+	}
+	if next < 0 {
+		return 0, errors.New("logstore: negative position")
+	}
+
+	if next != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = next
+	return next, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
@@ -0,0 +1,62 @@
+// Package logstore stores the output of executions. A log is written once, by the
+// executor running the workload, and read back through the API.
+package logstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when no log is stored under a key
+var ErrNotFound = errors.New("logstore: log not found")
+
+// Store persists logs under slash-separated keys.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Create returns a writer for the log under key, replacing any existing log.
+	// The log is complete once the writer is closed; stores that upload on close
+	// report upload failures from Close.
+	Create(ctx context.Context, key string) (io.WriteCloser, error)
+	// Open returns the log under key for reading, or ErrNotFound
+	Open(ctx context.Context, key string) (Object, error)
+	// URI identifies the log under key, as recorded in executions.log_uri
+	URI(key string) string
+}
+
+// Object is a stored log opened for reading
+type Object interface {
+	io.ReadSeekCloser
+	// Size is the length of the log in bytes when it was opened
+	Size() int64
+	// ModTime is when the log was last written
+	ModTime() time.Time
+}
+
+// Key returns the key an execution's log is stored under
+func Key(executionID uuid.UUID) string {
+	return "executions/" + executionID.String() + ".log"
+}
+
+// Config selects and configures a store implementation
+type Config struct {
+	Backend string   // "local" or "s3"
+	Dir     string   // Root directory for the local backend
+	S3      S3Config // Bucket settings for the s3 backend
+}
+
+// New creates the store described by config
+func New(config Config) (Store, error) {
+	switch config.Backend {
+	case "local":
+		return NewLocalStore(config.Dir)
+	case "s3":
+		return NewS3Store(config.S3)
+	default:
+		return nil, fmt.Errorf("logstore: unknown backend %q", config.Backend)
+	}
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer writes each message to its own .eml file in a directory instead of
+// delivering it, so development setups can open links without a mail server
+type FileMailer struct {
+	from string
+	dir  string
+	now  func() time.Time
+}
+
+// NewFileMailer creates a mailer writing to dir, creating it if needed
+func NewFileMailer(from, dir string) (*FileMailer, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("mailer: file backend requires a directory")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mailer: failed to create mail directory: %w", err)
+	}
+	return &FileMailer{from: from, dir: dir, now: time.Now}, nil
+}
+
+// Send writes msg to <dir>/<timestamp>-<id>.eml, so files sort by send time
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	now := m.now()
+	data, err := format(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), uuid.New())
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("mailer: failed to write message: %w", err)
+	}
+	return nil
+}
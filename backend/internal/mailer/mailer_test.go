@@ -0,0 +1,136 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = Message{
+	To:      "ada@example.com",
+	Subject: "Verify your email – Veridian",
+	Body:    "Open https://app.example.com/verify-email?token=abc to verify.\n",
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{Backend: "memory", From: "not an address"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Backend: "pigeon", From: "noreply@example.com"})
+	assert.Error(t, err)
+
+	m, err := New(Config{Backend: "memory", From: "Veridian <noreply@example.com>"})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryMailer{}, m)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	require.NoError(t, m.Send(context.Background(), testMessage))
+	assert.Equal(t, []Message{testMessage}, m.Messages())
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewFileMailer("Veridian <noreply@example.com>", dir)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Send(context.Background(), testMessage))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	f, err := os.Open(files[0])
+	require.NoError(t, err)
+	defer f.Close()
+
+	parsed, err := mail.ReadMessage(f)
+	require.NoError(t, err)
+	assert.Equal(t, "<ada@example.com>", parsed.Header.Get("To"))
+	assert.Equal(t, `"Veridian" <noreply@example.com>`, parsed.Header.Get("From"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, testMessage.Subject, subject)
+}
+
+func TestSMTPMailer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(t, listener, received)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	m, err := NewSMTPMailer("noreply@example.com", SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    addr.Port,
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Send(context.Background(), testMessage))
+
+	select {
+	case commands := <-received:
+		assert.Contains(t, commands, "MAIL FROM:<noreply@example.com>")
+		assert.Contains(t, commands, "RCPT TO:<ada@example.com>")
+		assert.Contains(t, commands, "Subject: =?utf-8?q?Verify_your_email_=E2=80=93_Veridian?=")
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not receive the message")
+	}
+}
+
+// serveSMTP accepts one session and reports the lines the client sent
+func serveSMTP(t *testing.T, listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var lines []string
+	inData := false
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		switch {
+		case inData && line == ".":
+			inData = false
+			reply("250 OK")
+		case inData:
+		case strings.HasPrefix(line, "EHLO"):
+			reply("250 localhost")
+		case line == "DATA":
+			inData = true
+			reply("354 Go ahead")
+		case line == "QUIT":
+			reply("221 Bye")
+			received <- lines
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
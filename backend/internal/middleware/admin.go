@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// UserLookup loads the authenticated user's account
+type UserLookup interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error)
+}
+
+// AdminMiddleware restricts a route group to administrators. It must run after
+// JWTAuthMiddleware. Admin status is read from the database on every request so
+// revoking it takes effect immediately.
+func AdminMiddleware(users UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !RequireAuth(c) {
+			return
+		}
+
+		userID, _ := GetUserIDFromContext(c)
+		user, err := users.GetUserByID(c.Request.Context(), userID)
+		if err != nil || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Administrator access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUsers maps user IDs to accounts
+type fakeUsers map[uuid.UUID]database.User
+
+func (f fakeUsers) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, ok := f[id]
+	if !ok {
+		return database.User{}, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := database.User{ID: uuid.New(), IsAdmin: true}
+	member := database.User{ID: uuid.New()}
+	users := fakeUsers{admin.ID: admin, member.ID: member}
+
+	tests := []struct {
+		name     string
+		userID   *uuid.UUID
+		expected int
+	}{
+		{"admin", &admin.ID, http.StatusOK},
+		{"regular user", &member.ID, http.StatusForbidden},
+		{"unknown user", &[]uuid.UUID{uuid.New()}[0], http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("user_id", *tt.userID)
+				}
+			})
+			r.Use(AdminMiddleware(users))
+			r.GET("/admin", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -1,16 +1,28 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"net/netip"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nouvadev/veridian/backend/internal/auth"
+	"github.com/nouvadev/veridian/backend/internal/database"
 )
 
-// JWTAuthMiddleware creates a JWT authentication middleware
-func JWTAuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
+// APIKeyStore looks up API keys presented as bearer credentials
+type APIKeyStore interface {
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (database.ApiKey, error)
+	UpdateAPIKeyLastUsed(ctx context.Context, arg database.UpdateAPIKeyLastUsedParams) error
+}
+
+// JWTAuthMiddleware creates a JWT authentication middleware. When keys is not
+// nil, API keys are accepted as an alternative to access tokens; requests
+// authenticated with one are limited to the key's scopes (see RequireScope).
+func JWTAuthMiddleware(jwtManager *auth.JWTManager, keys APIKeyStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -34,6 +46,11 @@ func JWTAuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 
 		tokenString := strings.TrimSpace(tokenParts[1])
 
+		if keys != nil && auth.IsAPIKey(tokenString) {
+			authenticateAPIKey(c, keys, tokenString)
+			return
+		}
+
 		// Validate the token
 		claims, err := jwtManager.ValidateAccessToken(tokenString)
 		if err != nil {
@@ -48,11 +65,89 @@ func JWTAuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("jwt_id", claims.JTI)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
+// authenticateAPIKey authenticates the request as the owner of an API key
+func authenticateAPIKey(c *gin.Context, keys APIKeyStore, key string) {
+	apiKey, err := keys.GetActiveAPIKeyByHash(c.Request.Context(), auth.HashToken(key))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid, expired or revoked API key",
+		})
+		c.Abort()
+		return
+	}
+
+	// Usage tracking is informational and must not fail the request
+	var clientIP *netip.Addr
+	if addr, err := netip.ParseAddr(c.ClientIP()); err == nil {
+		clientIP = &addr
+	}
+	if err := keys.UpdateAPIKeyLastUsed(c.Request.Context(), database.UpdateAPIKeyLastUsedParams{
+		ID:         apiKey.ID,
+		LastUsedIp: clientIP,
+	}); err != nil {
+		log.Printf("auth: API key %s: failed to record use: %v", apiKey.ID, err)
+	}
+
+	c.Set("user_id", apiKey.UserID)
+	c.Set("api_key_id", apiKey.ID)
+	c.Set("api_key_scopes", apiKey.Scopes)
+
+	c.Next()
+}
+
+// RequireScope restricts a route to sessions and to API keys granted scope.
+// It must run after JWTAuthMiddleware.
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := GetAPIKeyIDFromContext(c); isAPIKey && !hasScope(c, scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Insufficient API key scope",
+				"details": "This request requires the " + string(scope) + " scope",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession restricts a route to users signed in with an access token.
+// Account management is never available to API keys. It must run after
+// JWTAuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := GetAPIKeyIDFromContext(c); isAPIKey {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "API keys cannot access this endpoint",
+				"details": "Sign in to manage your account",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasScope reports whether the request's API key was granted scope
+func hasScope(c *gin.Context, scope auth.Scope) bool {
+	scopes, _ := c.Get("api_key_scopes")
+	granted, _ := scopes.([]string)
+	for _, s := range granted {
+		if auth.Scope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUserIDFromContext extracts user ID from gin context
 func GetUserIDFromContext(c *gin.Context) (uuid.UUID, bool) {
 	userID, exists := c.Get("user_id")
@@ -68,6 +163,38 @@ func GetUserIDFromContext(c *gin.Context) (uuid.UUID, bool) {
 	return id, true
 }
 
+// GetSessionIDFromContext extracts the caller's session (refresh token family)
+// from gin context. Tokens issued before sessions were tracked have none.
+func GetSessionIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	id, ok := sessionID.(uuid.UUID)
+	if !ok || id == uuid.Nil {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// GetAPIKeyIDFromContext extracts the API key the request authenticated with
+// from gin context. Requests authenticated with an access token have none.
+func GetAPIKeyIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	apiKeyID, exists := c.Get("api_key_id")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	id, ok := apiKeyID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
 // GetUserEmailFromContext extracts user email from gin context
 func GetUserEmailFromContext(c *gin.Context) (string, bool) {
 	email, exists := c.Get("user_email")
@@ -27,14 +27,15 @@ func TestJWTAuthMiddleware_ValidToken(t *testing.T) {
 
 	userID := uuid.New()
 	email := "test@example.com"
+	sessionID := uuid.New()
 
 	// Generate valid token
-	token, _, err := jwtManager.GenerateAccessToken(userID, email)
+	token, _, err := jwtManager.GenerateAccessToken(userID, email, sessionID)
 	require.NoError(t, err)
 
 	// Setup router with middleware
 	r := gin.New()
-	r.Use(JWTAuthMiddleware(jwtManager))
+	r.Use(JWTAuthMiddleware(jwtManager, nil))
 	r.GET("/test", func(c *gin.Context) {
 		// Extract user info from context
 		contextUserID, exists := GetUserIDFromContext(c)
@@ -45,6 +46,10 @@ func TestJWTAuthMiddleware_ValidToken(t *testing.T) {
 		assert.True(t, exists)
 		assert.Equal(t, email, contextEmail)
 
+		contextSessionID, exists := GetSessionIDFromContext(c)
+		assert.True(t, exists)
+		assert.Equal(t, sessionID, contextSessionID)
+
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -74,7 +79,7 @@ func TestJWTAuthMiddleware_MissingHeader(t *testing.T) {
 	)
 
 	r := gin.New()
-	r.Use(JWTAuthMiddleware(jwtManager))
+	r.Use(JWTAuthMiddleware(jwtManager, nil))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "should not reach here"})
 	})
@@ -104,7 +109,7 @@ func TestJWTAuthMiddleware_InvalidHeaderFormat(t *testing.T) {
 	)
 
 	r := gin.New()
-	r.Use(JWTAuthMiddleware(jwtManager))
+	r.Use(JWTAuthMiddleware(jwtManager, nil))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "should not reach here"})
 	})
@@ -146,7 +151,7 @@ func TestJWTAuthMiddleware_InvalidToken(t *testing.T) {
 	)
 
 	r := gin.New()
-	r.Use(JWTAuthMiddleware(jwtManager))
+	r.Use(JWTAuthMiddleware(jwtManager, nil))
 	r.GET("/test", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "should not reach here"})
 	})
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifiedEmailMiddleware restricts a route to users who have verified their
+// email address. It must run after JWTAuthMiddleware.
+func VerifiedEmailMiddleware(users UserLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !RequireAuth(c) {
+			return
+		}
+
+		userID, _ := GetUserIDFromContext(c)
+		user, err := users.GetUserByID(c.Request.Context(), userID)
+		if err != nil || !user.EmailVerified {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Email verification required",
+				"details": "Verify your email address before submitting jobs",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
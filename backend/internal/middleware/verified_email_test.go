@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifiedEmailMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verified := database.User{ID: uuid.New(), EmailVerified: true}
+	unverified := database.User{ID: uuid.New()}
+	users := fakeUsers{verified.ID: verified, unverified.ID: unverified}
+
+	tests := []struct {
+		name     string
+		userID   *uuid.UUID
+		expected int
+	}{
+		{"verified", &verified.ID, http.StatusOK},
+		{"unverified", &unverified.ID, http.StatusForbidden},
+		{"unknown user", &[]uuid.UUID{uuid.New()}[0], http.StatusForbidden},
+		{"unauthenticated", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.userID != nil {
+					c.Set("user_id", *tt.userID)
+				}
+			})
+			r.POST("/jobs", VerifiedEmailMiddleware(users), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("POST", "/jobs", nil))
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Execution represents a single run of a job
+type Execution struct {
+	ID                  uuid.UUID              `json:"id"`
+	JobID               uuid.UUID              `json:"job_id"`
+	Status              string                 `json:"status"`
+	ChosenAt            *time.Time             `json:"chosen_at,omitempty"`
+	PlannedStartAt      *time.Time             `json:"planned_start_at,omitempty"`
+	CloudRegion         *string                `json:"cloud_region,omitempty"`
+	VMType              *string                `json:"vm_type,omitempty"`
+	StartedAt           *time.Time             `json:"started_at,omitempty"`
+	CompletedAt         *time.Time             `json:"completed_at,omitempty"`
+	ExitCode            *int64                 `json:"exit_code,omitempty"`
+	CostEstimateUSD     *float64               `json:"cost_estimate_usd,omitempty"`
+	CostActualUSD       *float64               `json:"cost_actual_usd,omitempty"`
+	CarbonIntensityGKwh *float64               `json:"carbon_intensity_g_kwh,omitempty"`
+	CarbonEmittedKg     *float64               `json:"carbon_emitted_kg,omitempty"`
+	EnergyKWh           *float64               `json:"energy_kwh,omitempty"`
+	EnvVars             map[string]interface{} `json:"env_vars,omitempty"`
+	DelayToleranceHours *int32                 `json:"delay_tolerance_hours,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+}
+
+// ExecutionEvent is one status transition in an execution's history
+type ExecutionEvent struct {
+	ID         uuid.UUID `json:"id"`
+	FromStatus *string   `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ExecutionUpdate is a live execution event pushed over the event stream: a
+// status change, or output lines of a running execution
+type ExecutionUpdate struct {
+	ExecutionID uuid.UUID `json:"execution_id"`
+	JobID       uuid.UUID `json:"job_id"`
+	Status      *string   `json:"status,omitempty"`
+	Lines       []string  `json:"lines,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// RunJobRequest represents the optional request payload for POST /jobs/:id/run.
+// Omitted fields inherit the job definition's values.
+type RunJobRequest struct {
+	EnvVars             map[string]interface{} `json:"env_vars,omitempty"`
+	DelayToleranceHours *int                   `json:"delay_tolerance_hours,omitempty" binding:"omitempty,min=0,max=168"`
+}
+
+// ExecutionStats summarises the executions of a single job
+type ExecutionStats struct {
+	TotalExecutions      int64   `json:"total_executions"`
+	SuccessfulExecutions int64   `json:"successful_executions"`
+	FailedExecutions     int64   `json:"failed_executions"`
+	PendingExecutions    int64   `json:"pending_executions"`
+	RunningExecutions    int64   `json:"running_executions"`
+	AvgCostUSD           float64 `json:"avg_cost_usd"`
+	TotalCostUSD         float64 `json:"total_cost_usd"`
+	TotalCarbonKg        float64 `json:"total_carbon_kg"`
+}
+
+// OrphanedExecution is an execution whose compute could not be torn down,
+// as listed for administrators
+type OrphanedExecution struct {
+	Execution
+	InstanceID        *string    `json:"instance_id,omitempty"`
+	TeardownAttempts  int32      `json:"teardown_attempts"`
+	NextTeardownAt    *time.Time `json:"next_teardown_at,omitempty"`
+	LastTeardownError *string    `json:"last_teardown_error,omitempty"`
+}
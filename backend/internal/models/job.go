@@ -1,9 +1,11 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/schedule"
 )
 
 // Job represents a job definition in the database
@@ -13,8 +15,29 @@ type Job struct {
 	ImageURI            string                 `json:"image_uri" db:"image_uri"`
 	EnvVars             map[string]interface{} `json:"env_vars" db:"env_vars"`
 	DelayToleranceHours int                    `json:"delay_tolerance_hours" db:"delay_tolerance_hours"`
-	CreatedAt           time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt           time.Time              `json:"updated_at" db:"updated_at"`
+	CronSchedule        *string                `json:"cron_schedule,omitempty" db:"cron_schedule"`
+	Timezone            string                 `json:"timezone" db:"timezone"`
+	NextRunAt           *time.Time             `json:"next_run_at,omitempty" db:"next_run_at"`
+	NextRuns            []time.Time            `json:"next_runs,omitempty"`
+	JobResources
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Default resource requirements, matching the jobs column defaults
+const (
+	DefaultCPUCores = 1
+	DefaultMemoryMB = 2048
+	DefaultDiskGB   = 10
+)
+
+// JobResources describes what a job needs to run. Runtimes are in minutes.
+type JobResources struct {
+	CPUCores               int  `json:"cpu_cores" db:"cpu_cores"`
+	MemoryMB               int  `json:"memory_mb" db:"memory_mb"`
+	DiskGB                 int  `json:"disk_gb" db:"disk_gb"`
+	ExpectedRuntimeMinutes *int `json:"expected_runtime_minutes,omitempty" db:"expected_runtime_minutes"`
+	MaxRuntimeMinutes      *int `json:"max_runtime_minutes,omitempty" db:"max_runtime_minutes"`
 }
 
 // CreateJobRequest represents the request payload for POST /jobs
@@ -22,6 +45,53 @@ type CreateJobRequest struct {
 	ImageURI            string                 `json:"image_uri" validate:"required" binding:"required"`
 	EnvVars             map[string]interface{} `json:"env_vars,omitempty"`
 	DelayToleranceHours int                    `json:"delay_tolerance_hours" validate:"required,min=0,max=168" binding:"required,min=0,max=168"`
+	// CronSchedule makes the job recurring; Timezone defaults to UTC
+	CronSchedule *string `json:"cron_schedule,omitempty"`
+	Timezone     string  `json:"timezone,omitempty"`
+	// Resource requirements; omitted values use the defaults
+	CPUCores               int  `json:"cpu_cores,omitempty" binding:"omitempty,min=1,max=416"`
+	MemoryMB               int  `json:"memory_mb,omitempty" binding:"omitempty,min=128,max=12582912"`
+	DiskGB                 int  `json:"disk_gb,omitempty" binding:"omitempty,min=1,max=65536"`
+	ExpectedRuntimeMinutes *int `json:"expected_runtime_minutes,omitempty" binding:"omitempty,min=1"`
+	MaxRuntimeMinutes      *int `json:"max_runtime_minutes,omitempty" binding:"omitempty,min=1"`
+}
+
+// Resources returns the requested resources with defaults applied. A job may not
+// be expected to run longer than its max runtime.
+func (r *CreateJobRequest) Resources() (JobResources, error) {
+	resources := JobResources{
+		CPUCores:               r.CPUCores,
+		MemoryMB:               r.MemoryMB,
+		DiskGB:                 r.DiskGB,
+		ExpectedRuntimeMinutes: r.ExpectedRuntimeMinutes,
+		MaxRuntimeMinutes:      r.MaxRuntimeMinutes,
+	}
+	if resources.CPUCores == 0 {
+		resources.CPUCores = DefaultCPUCores
+	}
+	if resources.MemoryMB == 0 {
+		resources.MemoryMB = DefaultMemoryMB
+	}
+	if resources.DiskGB == 0 {
+		resources.DiskGB = DefaultDiskGB
+	}
+
+	if r.ExpectedRuntimeMinutes != nil && r.MaxRuntimeMinutes != nil && *r.ExpectedRuntimeMinutes > *r.MaxRuntimeMinutes {
+		return JobResources{}, errors.New("expected_runtime_minutes must not exceed max_runtime_minutes")
+	}
+	return resources, nil
+}
+
+// ParseSchedule validates the cron expression and timezone of a recurring job.
+// It returns nil for on-demand jobs.
+func (r *CreateJobRequest) ParseSchedule() (*schedule.Schedule, error) {
+	if r.CronSchedule == nil {
+		if r.Timezone != "" {
+			return nil, errors.New("timezone requires a cron_schedule")
+		}
+		return nil, nil
+	}
+	return schedule.Parse(*r.CronSchedule, r.Timezone)
 }
 
 // CreateJobResponse represents the response payload for POST /jobs
@@ -31,6 +101,10 @@ type CreateJobResponse struct {
 	ImageURI            string                 `json:"image_uri"`
 	EnvVars             map[string]interface{} `json:"env_vars"`
 	DelayToleranceHours int                    `json:"delay_tolerance_hours"`
-	CreatedAt           time.Time              `json:"created_at"`
-	UpdatedAt           time.Time              `json:"updated_at"`
+	CronSchedule        *string                `json:"cron_schedule,omitempty"`
+	Timezone            string                 `json:"timezone"`
+	NextRunAt           *time.Time             `json:"next_run_at,omitempty"`
+	JobResources
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Footprint is the cost and carbon of running an execution in one placement
+type Footprint struct {
+	Region   *string    `json:"region,omitempty"`
+	VMType   *string    `json:"vm_type,omitempty"`
+	StartAt  *time.Time `json:"start_at,omitempty"`
+	CostUSD  *float64   `json:"cost_usd,omitempty"`
+	CarbonKg *float64   `json:"carbon_kg,omitempty"`
+}
+
+// ExecutionSavings compares an execution with its baseline, running immediately at
+// submission time in the default region. Positive savings mean the execution did
+// better than the baseline; they are omitted until both sides are known.
+type ExecutionSavings struct {
+	ExecutionID     uuid.UUID `json:"execution_id"`
+	Actual          Footprint `json:"actual"`
+	Baseline        Footprint `json:"baseline"`
+	CostSavingsUSD  *float64  `json:"cost_savings_usd,omitempty"`
+	CarbonSavingsKg *float64  `json:"carbon_savings_kg,omitempty"`
+}
+
+// UserSavings totals the savings across all of a user's executions. Each total
+// only includes executions with both a baseline and an actual figure.
+type UserSavings struct {
+	Executions       int64   `json:"executions"` // Executions with a recorded baseline
+	BaselineCostUSD  float64 `json:"baseline_cost_usd"`
+	ActualCostUSD    float64 `json:"actual_cost_usd"`
+	CostSavingsUSD   float64 `json:"cost_savings_usd"`
+	BaselineCarbonKg float64 `json:"baseline_carbon_kg"`
+	ActualCarbonKg   float64 `json:"actual_carbon_kg"`
+	CarbonSavingsKg  float64 `json:"carbon_savings_kg"`
+}
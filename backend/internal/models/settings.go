@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// UserSettings represents a user's optimization preferences
+type UserSettings struct {
+	CostWeight   float64   `json:"cost_weight"`
+	CarbonWeight float64   `json:"carbon_weight"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UpdateSettingsRequest represents the request payload for PUT /settings.
+// Weights must each be between 0 and 1 and sum to 1.
+type UpdateSettingsRequest struct {
+	CostWeight   *float64 `json:"cost_weight" binding:"required,min=0,max=1"`
+	CarbonWeight *float64 `json:"carbon_weight" binding:"required,min=0,max=1"`
+}
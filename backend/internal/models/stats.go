@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Stats buckets for trend series
+const (
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+)
+
+// JobStats aggregates a user's executions submitted in [From, To) for the dashboard.
+// Savings are measured against running immediately in the default region.
+type JobStats struct {
+	From                       time.Time `json:"from"`
+	To                         time.Time `json:"to"`
+	TotalJobs                  int64     `json:"total_jobs"`
+	TotalExecutions            int64     `json:"total_executions"`
+	PendingExecutions          int64     `json:"pending_executions"`
+	EvaluatingExecutions       int64     `json:"evaluating_executions"`
+	RunningExecutions          int64     `json:"running_executions"`
+	CompletedSuccessExecutions int64     `json:"completed_success_executions"`
+	CompletedErrorExecutions   int64     `json:"completed_error_executions"`
+	OrphanedExecutions         int64     `json:"orphaned_executions"`
+	TotalCostUSD               float64   `json:"total_cost_usd"`
+	TotalCarbonEmittedKg       float64   `json:"total_carbon_emitted_kg"`
+	TotalCostSavingsUSD        float64   `json:"total_cost_savings_usd"`
+	TotalCarbonSavingsKg       float64   `json:"total_carbon_savings_kg"`
+	AvgDeferralHours           float64   `json:"avg_deferral_hours"`
+
+	// Bucket and Series are set when a trend was requested; every bucket in the
+	// range is present, including those without executions
+	Bucket string        `json:"bucket,omitempty"`
+	Series []StatsBucket `json:"series,omitempty"`
+}
+
+// StatsBucket aggregates the executions submitted in one UTC day, week (from
+// Monday) or month
+type StatsBucket struct {
+	Start                      time.Time `json:"start"`
+	TotalExecutions            int64     `json:"total_executions"`
+	CompletedSuccessExecutions int64     `json:"completed_success_executions"`
+	CompletedErrorExecutions   int64     `json:"completed_error_executions"`
+	TotalCostUSD               float64   `json:"total_cost_usd"`
+	TotalCarbonEmittedKg       float64   `json:"total_carbon_emitted_kg"`
+	TotalCostSavingsUSD        float64   `json:"total_cost_savings_usd"`
+	TotalCarbonSavingsKg       float64   `json:"total_carbon_savings_kg"`
+	AvgDeferralHours           float64   `json:"avg_deferral_hours"`
+}
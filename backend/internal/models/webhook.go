@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookRequest represents the request payload for POST /webhooks.
+// Omitted events subscribe to all of them; an omitted secret is generated.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,max=2048"`
+	Events []string `json:"events,omitempty"`
+	Secret *string  `json:"secret,omitempty" binding:"omitempty,min=16,max=256"`
+}
+
+// WebhookSubscription is a user's webhook endpoint. The signing secret is only
+// returned when the subscription is created.
+type WebhookSubscription struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for a subscription
+type WebhookDelivery struct {
+	ID            uuid.UUID       `json:"id"`
+	Event         string          `json:"event"`
+	ExecutionID   uuid.UUID       `json:"execution_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int32           `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	LastError     *string         `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryAttempt is one HTTP request made for a delivery
+type WebhookDeliveryAttempt struct {
+	ID          uuid.UUID `json:"id"`
+	StatusCode  *int32    `json:"status_code,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	DurationMs  int32     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
@@ -0,0 +1,206 @@
+// Package pricing provides hourly cloud VM prices per region and VM type and
+// estimates what executions cost.
+package pricing
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PurchaseOption selects which price of a VM type applies
+type PurchaseOption string
+
+// Purchase options
+const (
+	OnDemand PurchaseOption = "on_demand"
+	Spot     PurchaseOption = "spot"
+)
+
+// ErrPriceNotFound is returned when the catalogue has no price for a region, VM type and purchase option
+var ErrPriceNotFound = errors.New("pricing: no price for region and VM type")
+
+//go:embed default_catalogue.json
+var defaultCatalogue []byte
+
+// Price is the hourly USD price of one VM type in one region
+type Price struct {
+	Region   string   `json:"region" yaml:"region"`
+	VMType   string   `json:"vm_type" yaml:"vm_type"`
+	OnDemand float64  `json:"on_demand" yaml:"on_demand"`
+	Spot     *float64 `json:"spot,omitempty" yaml:"spot,omitempty"` // Not every VM type is sold as spot
+}
+
+// VMType describes the resources of a VM type
+type VMType struct {
+	Name     string  `json:"name" yaml:"name"`
+	VCPUs    int     `json:"vcpus" yaml:"vcpus"`
+	MemoryGB float64 `json:"memory_gb" yaml:"memory_gb"`
+	DiskGB   int     `json:"disk_gb" yaml:"disk_gb"` // Ephemeral disk available to the workload
+}
+
+// catalogueFile is the on-disk catalogue format. The version identifies the
+// price list so estimates can be traced back to the prices they used.
+type catalogueFile struct {
+	Version  string   `json:"version" yaml:"version"`
+	Currency string   `json:"currency" yaml:"currency"`
+	VMTypes  []VMType `json:"vm_types" yaml:"vm_types"`
+	Prices   []Price  `json:"prices" yaml:"prices"`
+}
+
+// priceKey identifies a VM type in a region
+type priceKey struct {
+	region string
+	vmType string
+}
+
+// Catalogue holds hourly prices for regions × VM types and the resources of each
+// VM type. It is immutable once loaded and safe for concurrent use.
+type Catalogue struct {
+	version string
+	vmTypes map[string]VMType
+	prices  map[priceKey]Price
+}
+
+// Default returns the catalogue built into the binary
+func Default() *Catalogue {
+	catalogue, err := LoadJSON(bytes.NewReader(defaultCatalogue))
+	if err != nil {
+		panic(fmt.Sprintf("pricing: invalid built-in catalogue: %v", err))
+	}
+	return catalogue
+}
+
+// LoadFile reads a catalogue from a .json, .yaml or .yml file
+func LoadFile(path string) (*Catalogue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pricing catalogue: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return LoadJSON(f)
+	case ".yaml", ".yml":
+		return LoadYAML(f)
+	default:
+		return nil, fmt.Errorf("pricing catalogue must be a .json, .yaml or .yml file, got %q", path)
+	}
+}
+
+// LoadJSON parses a catalogue from a JSON stream
+func LoadJSON(r io.Reader) (*Catalogue, error) {
+	var file catalogueFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing catalogue: %w", err)
+	}
+	return newCatalogue(file)
+}
+
+// LoadYAML parses a catalogue from a YAML stream
+func LoadYAML(r io.Reader) (*Catalogue, error) {
+	var file catalogueFile
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing catalogue: %w", err)
+	}
+	return newCatalogue(file)
+}
+
+// newCatalogue validates a parsed catalogue file and indexes its prices
+func newCatalogue(file catalogueFile) (*Catalogue, error) {
+	if file.Version == "" {
+		return nil, errors.New("pricing catalogue is missing a version")
+	}
+	if file.Currency != "" && file.Currency != "USD" {
+		return nil, fmt.Errorf("pricing catalogue must be in USD, got %s", file.Currency)
+	}
+
+	vmTypes := make(map[string]VMType, len(file.VMTypes))
+	for i, vmType := range file.VMTypes {
+		if vmType.Name == "" {
+			return nil, fmt.Errorf("vm type %d: name is required", i)
+		}
+		if vmType.VCPUs <= 0 || vmType.MemoryGB <= 0 || vmType.DiskGB < 0 {
+			return nil, fmt.Errorf("vm type %s: vcpus and memory_gb must be positive", vmType.Name)
+		}
+		if _, exists := vmTypes[vmType.Name]; exists {
+			return nil, fmt.Errorf("vm type %s: duplicate entry", vmType.Name)
+		}
+		vmTypes[vmType.Name] = vmType
+	}
+
+	prices := make(map[priceKey]Price, len(file.Prices))
+	for i, price := range file.Prices {
+		if price.Region == "" || price.VMType == "" {
+			return nil, fmt.Errorf("price %d: region and vm_type are required", i)
+		}
+		if price.OnDemand < 0 || (price.Spot != nil && *price.Spot < 0) {
+			return nil, fmt.Errorf("price %d: prices must not be negative", i)
+		}
+
+		key := priceKey{region: price.Region, vmType: price.VMType}
+		if _, exists := prices[key]; exists {
+			return nil, fmt.Errorf("price %d: duplicate entry for %s in %s", i, price.VMType, price.Region)
+		}
+		prices[key] = price
+	}
+
+	return &Catalogue{version: file.Version, vmTypes: vmTypes, prices: prices}, nil
+}
+
+// Version identifies the loaded price list
+func (c *Catalogue) Version() string {
+	return c.version
+}
+
+// HourlyPrice returns the USD price per hour of a VM type in a region
+func (c *Catalogue) HourlyPrice(region, vmType string, option PurchaseOption) (float64, error) {
+	price, ok := c.prices[priceKey{region: region, vmType: vmType}]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s in %s", ErrPriceNotFound, vmType, region)
+	}
+
+	switch option {
+	case OnDemand:
+		return price.OnDemand, nil
+	case Spot:
+		if price.Spot == nil {
+			return 0, fmt.Errorf("%w: %s in %s is not sold as spot", ErrPriceNotFound, vmType, region)
+		}
+		return *price.Spot, nil
+	default:
+		return 0, fmt.Errorf("pricing: unknown purchase option %q", option)
+	}
+}
+
+// VMType returns the resources of a VM type, if the catalogue describes it
+func (c *Catalogue) VMType(name string) (VMType, bool) {
+	vmType, ok := c.vmTypes[name]
+	return vmType, ok
+}
+
+// VMTypes returns the VM types priced in a region, sorted by name
+func (c *Catalogue) VMTypes(region string) []string {
+	var vmTypes []string
+	for key := range c.prices {
+		if key.region == region {
+			vmTypes = append(vmTypes, key.vmType)
+		}
+	}
+	sort.Strings(vmTypes)
+	return vmTypes
+}
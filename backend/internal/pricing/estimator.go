@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Estimator prices executions from a catalogue using one purchase option.
+// Compute is billed per second of runtime.
+type Estimator struct {
+	catalogue *Catalogue
+	option    PurchaseOption
+}
+
+// NewEstimator creates an estimator for the given catalogue and purchase option
+func NewEstimator(catalogue *Catalogue, option PurchaseOption) (*Estimator, error) {
+	switch option {
+	case OnDemand, Spot:
+	default:
+		return nil, fmt.Errorf("pricing: unknown purchase option %q", option)
+	}
+
+	return &Estimator{catalogue: catalogue, option: option}, nil
+}
+
+// Catalogue returns the price list the estimator uses
+func (e *Estimator) Catalogue() *Catalogue {
+	return e.catalogue
+}
+
+// HourlyPrice returns the USD price per hour of a VM type in a region
+func (e *Estimator) HourlyPrice(region, vmType string) (float64, error) {
+	return e.catalogue.HourlyPrice(region, vmType, e.option)
+}
+
+// Estimate returns the USD cost of running a VM type in a region for the expected runtime
+func (e *Estimator) Estimate(region, vmType string, runtime time.Duration) (float64, error) {
+	hourly, err := e.HourlyPrice(region, vmType)
+	if err != nil {
+		return 0, err
+	}
+
+	if runtime < 0 {
+		runtime = 0
+	}
+	return roundUSD(hourly * runtime.Hours()), nil
+}
+
+// Actual returns the USD cost of an execution that ran from startedAt to completedAt
+func (e *Estimator) Actual(region, vmType string, startedAt, completedAt time.Time) (float64, error) {
+	return e.Estimate(region, vmType, completedAt.Sub(startedAt).Round(time.Second))
+}
+
+// roundUSD rounds to the six decimal places stored in the cost columns
+func roundUSD(amount float64) float64 {
+	return math.Round(amount*1e6) / 1e6
+}
@@ -0,0 +1,139 @@
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCatalogue = `{
+  "version": "2025-01-01",
+  "currency": "USD",
+  "prices": [
+    {"region": "westeurope", "vm_type": "Standard_D2s_v5", "on_demand": 0.096, "spot": 0.0192},
+    {"region": "westeurope", "vm_type": "Standard_B2s", "on_demand": 0.0416},
+    {"region": "northeurope", "vm_type": "Standard_D2s_v5", "on_demand": 0.088}
+  ]
+}`
+
+func TestLoadJSON_HourlyPrice(t *testing.T) {
+	catalogue, err := LoadJSON(strings.NewReader(testCatalogue))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-01-01", catalogue.Version())
+
+	price, err := catalogue.HourlyPrice("westeurope", "Standard_D2s_v5", OnDemand)
+	require.NoError(t, err)
+	assert.Equal(t, 0.096, price)
+
+	price, err = catalogue.HourlyPrice("westeurope", "Standard_D2s_v5", Spot)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0192, price)
+
+	_, err = catalogue.HourlyPrice("westeurope", "Standard_B2s", Spot)
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+
+	_, err = catalogue.HourlyPrice("eastus", "Standard_D2s_v5", OnDemand)
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+
+	assert.Equal(t, []string{"Standard_B2s", "Standard_D2s_v5"}, catalogue.VMTypes("westeurope"))
+}
+
+func TestLoadYAML(t *testing.T) {
+	catalogue, err := LoadYAML(strings.NewReader(`
+version: "2025-02"
+prices:
+  - region: swedencentral
+    vm_type: Standard_D2s_v5
+    on_demand: 0.091
+    spot: 0.0182
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "2025-02", catalogue.Version())
+
+	price, err := catalogue.HourlyPrice("swedencentral", "Standard_D2s_v5", Spot)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0182, price)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "prices.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(testCatalogue), 0o644))
+	_, err := LoadFile(jsonPath)
+	assert.NoError(t, err)
+
+	csvPath := filepath.Join(dir, "prices.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte(testCatalogue), 0o644))
+	_, err = LoadFile(csvPath)
+	assert.Error(t, err)
+}
+
+func TestLoadJSON_InvalidCatalogue(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing version", `{"prices": []}`},
+		{"wrong currency", `{"version": "1", "currency": "EUR", "prices": []}`},
+		{"missing vm type", `{"version": "1", "prices": [{"region": "westeurope", "on_demand": 0.1}]}`},
+		{"negative price", `{"version": "1", "prices": [{"region": "westeurope", "vm_type": "A", "on_demand": -1}]}`},
+		{"duplicate", `{"version": "1", "prices": [{"region": "r", "vm_type": "A", "on_demand": 1}, {"region": "r", "vm_type": "A", "on_demand": 2}]}`},
+		{"vm type without vcpus", `{"version": "1", "vm_types": [{"name": "A", "memory_gb": 4}], "prices": []}`},
+		{"duplicate vm type", `{"version": "1", "vm_types": [{"name": "A", "vcpus": 2, "memory_gb": 4}, {"name": "A", "vcpus": 4, "memory_gb": 8}], "prices": []}`},
+		{"unknown field", `{"version": "1", "prices": [{"region": "r", "vm_type": "A", "reserved": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadJSON(strings.NewReader(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	catalogue := Default()
+	assert.NotEmpty(t, catalogue.Version())
+
+	// The built-in catalogue covers the scheduler's default regions and VM type
+	for _, region := range []string{"westeurope", "northeurope", "swedencentral"} {
+		_, err := catalogue.HourlyPrice(region, "Standard_D2s_v5", OnDemand)
+		assert.NoError(t, err, region)
+	}
+
+	// Every priced VM type can be selected
+	for _, name := range catalogue.VMTypes("westeurope") {
+		_, ok := catalogue.VMType(name)
+		assert.True(t, ok, name)
+	}
+}
+
+func TestEstimator(t *testing.T) {
+	catalogue, err := LoadJSON(strings.NewReader(testCatalogue))
+	require.NoError(t, err)
+
+	_, err = NewEstimator(catalogue, "reserved")
+	assert.Error(t, err)
+
+	estimator, err := NewEstimator(catalogue, OnDemand)
+	require.NoError(t, err)
+
+	cost, err := estimator.Estimate("westeurope", "Standard_D2s_v5", 90*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 0.144, cost)
+
+	// Billed per second, rounded to the stored precision
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cost, err = estimator.Actual("northeurope", "Standard_D2s_v5", started, started.Add(10*time.Minute+400*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 0.014667, cost)
+
+	_, err = estimator.Estimate("eastus", "Standard_D2s_v5", time.Hour)
+	assert.ErrorIs(t, err, ErrPriceNotFound)
+}
@@ -0,0 +1,93 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Strategy decides which of the VM types that fit a workload is chosen
+type Strategy string
+
+// Selection strategies
+const (
+	Cheapest Strategy = "cheapest" // Lowest hourly price
+	Greenest Strategy = "greenest" // Fewest vCPUs, then least memory: the smallest machine draws the least power
+)
+
+// ErrNoVMType is returned when no VM type priced in a region satisfies the requirements
+var ErrNoVMType = errors.New("pricing: no VM type satisfies the requirements")
+
+// ParseStrategy validates a selection strategy name
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(name); strategy {
+	case Cheapest, Greenest:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("pricing: unknown VM selection strategy %q", name)
+	}
+}
+
+// Requirements are the minimum resources a workload needs
+type Requirements struct {
+	CPUCores int
+	MemoryMB int
+	DiskGB   int
+}
+
+// String formats requirements for logs and execution history
+func (r Requirements) String() string {
+	return fmt.Sprintf("%d vCPU, %d MiB memory, %d GiB disk", r.CPUCores, r.MemoryMB, r.DiskGB)
+}
+
+// Fits reports whether the VM type has at least the required resources
+func (v VMType) Fits(req Requirements) bool {
+	return v.VCPUs >= req.CPUCores &&
+		v.MemoryGB*1024 >= float64(req.MemoryMB) &&
+		v.DiskGB >= req.DiskGB
+}
+
+// Selection is the VM type chosen for a workload in one region
+type Selection struct {
+	VMType      VMType
+	HourlyPrice float64
+}
+
+// Select returns the VM type in a region that satisfies the requirements, chosen by
+// strategy. Only VM types with both a price for the estimator's purchase option and
+// a resource description are considered. Remaining ties go to the cheaper, then the
+// alphabetically first VM type, so the choice is stable.
+func (e *Estimator) Select(region string, req Requirements, strategy Strategy) (Selection, error) {
+	var candidates []Selection
+	for _, name := range e.catalogue.VMTypes(region) {
+		vmType, ok := e.catalogue.VMType(name)
+		if !ok || !vmType.Fits(req) {
+			continue
+		}
+
+		price, err := e.HourlyPrice(region, name)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, Selection{VMType: vmType, HourlyPrice: price})
+	}
+
+	if len(candidates) == 0 {
+		return Selection{}, fmt.Errorf("%w in %s: %s", ErrNoVMType, region, req)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if strategy == Greenest {
+			if a.VMType.VCPUs != b.VMType.VCPUs {
+				return a.VMType.VCPUs < b.VMType.VCPUs
+			}
+			if a.VMType.MemoryGB != b.VMType.MemoryGB {
+				return a.VMType.MemoryGB < b.VMType.MemoryGB
+			}
+		}
+		return a.HourlyPrice < b.HourlyPrice
+	})
+
+	return candidates[0], nil
+}
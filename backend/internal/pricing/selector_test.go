@@ -0,0 +1,72 @@
+package pricing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const selectorCatalogue = `{
+  "version": "test",
+  "vm_types": [
+    {"name": "Small", "vcpus": 2, "memory_gb": 4, "disk_gb": 16},
+    {"name": "Burst", "vcpus": 2, "memory_gb": 8, "disk_gb": 32},
+    {"name": "Large", "vcpus": 8, "memory_gb": 32, "disk_gb": 300},
+    {"name": "Unpriced", "vcpus": 1, "memory_gb": 1, "disk_gb": 10}
+  ],
+  "prices": [
+    {"region": "westeurope", "vm_type": "Small", "on_demand": 0.10},
+    {"region": "westeurope", "vm_type": "Burst", "on_demand": 0.05, "spot": 0.01},
+    {"region": "westeurope", "vm_type": "Large", "on_demand": 0.40, "spot": 0.08},
+    {"region": "westeurope", "vm_type": "Undescribed", "on_demand": 0.01}
+  ]
+}`
+
+func TestSelect(t *testing.T) {
+	catalogue, err := LoadJSON(strings.NewReader(selectorCatalogue))
+	require.NoError(t, err)
+
+	onDemand, err := NewEstimator(catalogue, OnDemand)
+	require.NoError(t, err)
+	spot, err := NewEstimator(catalogue, Spot)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		estimator *Estimator
+		req       Requirements
+		strategy  Strategy
+		want      string
+	}{
+		{"cheapest fit", onDemand, Requirements{CPUCores: 1, MemoryMB: 1024, DiskGB: 10}, Cheapest, "Burst"},
+		{"greenest fit", onDemand, Requirements{CPUCores: 1, MemoryMB: 1024, DiskGB: 10}, Greenest, "Small"},
+		{"memory rules out small", onDemand, Requirements{CPUCores: 1, MemoryMB: 6144, DiskGB: 10}, Greenest, "Burst"},
+		{"only large fits", onDemand, Requirements{CPUCores: 4, MemoryMB: 1024, DiskGB: 10}, Cheapest, "Large"},
+		{"spot skips types without spot", spot, Requirements{CPUCores: 1, MemoryMB: 1024, DiskGB: 10}, Greenest, "Burst"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection, err := tt.estimator.Select("westeurope", tt.req, tt.strategy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, selection.VMType.Name)
+		})
+	}
+
+	_, err = onDemand.Select("westeurope", Requirements{CPUCores: 16, MemoryMB: 1024, DiskGB: 10}, Cheapest)
+	assert.ErrorIs(t, err, ErrNoVMType)
+
+	_, err = onDemand.Select("northeurope", Requirements{CPUCores: 1, MemoryMB: 1024, DiskGB: 10}, Cheapest)
+	assert.ErrorIs(t, err, ErrNoVMType)
+}
+
+func TestParseStrategy(t *testing.T) {
+	strategy, err := ParseStrategy("greenest")
+	require.NoError(t, err)
+	assert.Equal(t, Greenest, strategy)
+
+	_, err = ParseStrategy("fastest")
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+// Package report generates sustainability reports of the compute footprint of a
+// user's executions. Reports are written as the rows are read from the database,
+// so they can cover long periods without being held in memory.
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// Format is the encoding of a report
+type Format string
+
+// Report formats
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates a report format name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatCSV, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("report format must be csv or json, got %q", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// Source streams the rows of a sustainability report
+type Source interface {
+	StreamSustainabilityReport(ctx context.Context, arg database.SustainabilityReportParams, fn func(database.SustainabilityReportRow) error) error
+}
+
+// Row is the footprint of one job in one region and month. Emissions are
+// location-based operational emissions of the electricity drawn, including data
+// centre overhead (Scope 2 style); embodied hardware emissions are not included.
+// Savings compare each execution with running immediately in the default region.
+type Row struct {
+	Month                   string    `json:"month"` // YYYY-MM, UTC
+	Region                  string    `json:"region"`
+	JobID                   uuid.UUID `json:"job_id"`
+	ImageURI                string    `json:"image_uri"`
+	Executions              int64     `json:"executions"`
+	RuntimeHours            float64   `json:"runtime_hours"`
+	EnergyKWh               float64   `json:"energy_kwh"`
+	EmissionsKg             float64   `json:"emissions_kg"`
+	AvgIntensityGramsPerKWh float64   `json:"avg_intensity_g_kwh"` // Energy-weighted, over executions with known emissions
+	EmissionsSavingsKg      float64   `json:"emissions_savings_kg"`
+	CostUSD                 float64   `json:"cost_usd"`
+	CostSavingsUSD          float64   `json:"cost_savings_usd"`
+}
+
+// Totals sums every row of a report
+type Totals struct {
+	Executions              int64   `json:"executions"`
+	RuntimeHours            float64 `json:"runtime_hours"`
+	EnergyKWh               float64 `json:"energy_kwh"`
+	EmissionsKg             float64 `json:"emissions_kg"`
+	AvgIntensityGramsPerKWh float64 `json:"avg_intensity_g_kwh"`
+	EmissionsSavingsKg      float64 `json:"emissions_savings_kg"`
+	CostUSD                 float64 `json:"cost_usd"`
+	CostSavingsUSD          float64 `json:"cost_savings_usd"`
+
+	intensityEnergyKWh float64
+}
+
+// Period is the completion time range a report covers, [From, To)
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// csvHeader names the CSV columns, matching the JSON field names
+var csvHeader = []string{
+	"month", "region", "job_id", "image_uri", "executions", "runtime_hours", "energy_kwh",
+	"emissions_kg", "avg_intensity_g_kwh", "emissions_savings_kg", "cost_usd", "cost_savings_usd",
+}
+
+// csvFlushEvery bounds how many CSV rows are buffered before being written out
+const csvFlushEvery = 100
+
+// Generate writes the sustainability report of a user's executions completed in
+// the period to w. If generation fails part way through, w holds a truncated report.
+func Generate(ctx context.Context, source Source, ownerID uuid.UUID, period Period, format Format, w io.Writer) error {
+	params := database.SustainabilityReportParams{OwnerID: ownerID, From: period.From, To: period.To}
+
+	switch format {
+	case FormatCSV:
+		return generateCSV(ctx, source, params, w)
+	case FormatJSON:
+		return generateJSON(ctx, source, params, period, w)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+// generateCSV writes a header line and one line per row
+func generateCSV(ctx context.Context, source Source, params database.SustainabilityReportParams, w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+
+	written := 0
+	err := source.StreamSustainabilityReport(ctx, params, func(dbRow database.SustainabilityReportRow) error {
+		row := toRow(dbRow)
+		if err := out.Write([]string{
+			row.Month,
+			row.Region,
+			row.JobID.String(),
+			row.ImageURI,
+			strconv.FormatInt(row.Executions, 10),
+			formatFloat(row.RuntimeHours),
+			formatFloat(row.EnergyKWh),
+			formatFloat(row.EmissionsKg),
+			formatFloat(row.AvgIntensityGramsPerKWh),
+			formatFloat(row.EmissionsSavingsKg),
+			formatFloat(row.CostUSD),
+			formatFloat(row.CostSavingsUSD),
+		}); err != nil {
+			return err
+		}
+
+		if written++; written%csvFlushEvery == 0 {
+			out.Flush()
+			return out.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// generateJSON writes an object holding the period, the rows and their totals. The
+// rows array is written element by element as rows arrive.
+func generateJSON(ctx context.Context, source Source, params database.SustainabilityReportParams, period Period, w io.Writer) error {
+	from, err := json.Marshal(period.From)
+	if err != nil {
+		return err
+	}
+	to, err := json.Marshal(period.To)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"from":%s,"to":%s,"rows":[`, from, to); err != nil {
+		return err
+	}
+
+	var totals Totals
+	first := true
+	err = source.StreamSustainabilityReport(ctx, params, func(dbRow database.SustainabilityReportRow) error {
+		totals.add(dbRow)
+
+		encoded, err := json.Marshal(toRow(dbRow))
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(totals.rounded())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, `],"totals":%s}`+"\n", encoded)
+	return err
+}
+
+// toRow derives the average intensity of a database row and rounds its figures
+func toRow(row database.SustainabilityReportRow) Row {
+	return Row{
+		Month:                   row.Month.UTC().Format("2006-01"),
+		Region:                  row.Region,
+		JobID:                   row.JobID,
+		ImageURI:                row.ImageUri,
+		Executions:              row.Executions,
+		RuntimeHours:            round(row.RuntimeHours),
+		EnergyKWh:               round(row.EnergyKwh),
+		EmissionsKg:             round(row.EmissionsKg),
+		AvgIntensityGramsPerKWh: round(intensity(row.EmissionsKg, row.IntensityEnergyKwh)),
+		EmissionsSavingsKg:      round(row.EmissionsSavingsKg),
+		CostUSD:                 round(row.CostUsd),
+		CostSavingsUSD:          round(row.CostSavingsUsd),
+	}
+}
+
+// add accumulates a row into the totals
+func (t *Totals) add(row database.SustainabilityReportRow) {
+	t.Executions += row.Executions
+	t.RuntimeHours += row.RuntimeHours
+	t.EnergyKWh += row.EnergyKwh
+	t.EmissionsKg += row.EmissionsKg
+	t.EmissionsSavingsKg += row.EmissionsSavingsKg
+	t.CostUSD += row.CostUsd
+	t.CostSavingsUSD += row.CostSavingsUsd
+	t.intensityEnergyKWh += row.IntensityEnergyKwh
+}
+
+// rounded returns the totals with the average intensity derived and figures rounded
+func (t Totals) rounded() Totals {
+	return Totals{
+		Executions:              t.Executions,
+		RuntimeHours:            round(t.RuntimeHours),
+		EnergyKWh:               round(t.EnergyKWh),
+		EmissionsKg:             round(t.EmissionsKg),
+		AvgIntensityGramsPerKWh: round(intensity(t.EmissionsKg, t.intensityEnergyKWh)),
+		EmissionsSavingsKg:      round(t.EmissionsSavingsKg),
+		CostUSD:                 round(t.CostUSD),
+		CostSavingsUSD:          round(t.CostSavingsUSD),
+	}
+}
+
+// intensity returns the average gCO2/kWh of energy with known emissions
+func intensity(emissionsKg, energyKWh float64) float64 {
+	if energyKWh <= 0 {
+		return 0
+	}
+	return emissionsKg * 1000 / energyKWh
+}
+
+// round keeps the six decimal places stored for costs, energy and emissions
+func round(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
+
+// formatFloat writes a float without exponent notation
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// sliceSource streams a fixed set of rows
+type sliceSource struct {
+	rows   []database.SustainabilityReportRow
+	params database.SustainabilityReportParams
+	err    error
+}
+
+func (s *sliceSource) StreamSustainabilityReport(ctx context.Context, arg database.SustainabilityReportParams, fn func(database.SustainabilityReportRow) error) error {
+	s.params = arg
+	for _, row := range s.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+var (
+	testJob    = uuid.MustParse("7b0a6d7e-3c1f-4a57-9e61-2f0c1f2b9a10")
+	testPeriod = Period{
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func testSource() *sliceSource {
+	return &sliceSource{rows: []database.SustainabilityReportRow{
+		{
+			Month: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Region: "swedencentral", JobID: testJob, ImageUri: "etl:1",
+			Executions: 3, RuntimeHours: 6, EnergyKwh: 0.06, IntensityEnergyKwh: 0.06, EmissionsKg: 0.0012,
+			EmissionsSavingsKg: 0.01, CostUsd: 0.5, CostSavingsUsd: 0.1,
+		},
+		{
+			// Emissions are only known for half the energy
+			Month: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Region: "westeurope", JobID: testJob, ImageUri: "etl:1",
+			Executions: 2, RuntimeHours: 4, EnergyKwh: 0.04, IntensityEnergyKwh: 0.02, EmissionsKg: 0.004,
+			CostUsd: 0.3,
+		},
+	}}
+}
+
+func TestGenerate_CSV(t *testing.T) {
+	source := testSource()
+	ownerID := uuid.New()
+
+	var out bytes.Buffer
+	require.NoError(t, Generate(context.Background(), source, ownerID, testPeriod, FormatCSV, &out))
+
+	assert.Equal(t, ownerID, source.params.OwnerID)
+	assert.Equal(t, testPeriod.From, source.params.From)
+	assert.Equal(t, testPeriod.To, source.params.To)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"2025-01", "swedencentral", testJob.String(), "etl:1", "3", "6", "0.06", "0.0012", "20", "0.01", "0.5", "0.1"}, records[1])
+
+	// Average intensity is weighted over the energy with known emissions
+	assert.Equal(t, "200", records[2][8])
+}
+
+func TestGenerate_JSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Generate(context.Background(), testSource(), uuid.New(), testPeriod, FormatJSON, &out))
+
+	var result struct {
+		From   time.Time `json:"from"`
+		To     time.Time `json:"to"`
+		Rows   []Row     `json:"rows"`
+		Totals Totals    `json:"totals"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+
+	assert.Equal(t, testPeriod.From, result.From)
+	require.Len(t, result.Rows, 2)
+	assert.Equal(t, "2025-02", result.Rows[1].Month)
+	assert.Equal(t, int64(5), result.Totals.Executions)
+	assert.Equal(t, 0.1, result.Totals.EnergyKWh)
+	assert.Equal(t, 0.0052, result.Totals.EmissionsKg)
+	assert.Equal(t, 65.0, result.Totals.AvgIntensityGramsPerKWh)
+	assert.Equal(t, 0.8, result.Totals.CostUSD)
+}
+
+func TestGenerate_EmptyJSON(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Generate(context.Background(), &sliceSource{}, uuid.New(), testPeriod, FormatJSON, &out))
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	assert.Empty(t, result["rows"])
+}
+
+func TestGenerate_ReturnsSourceErrors(t *testing.T) {
+	source := testSource()
+	source.err = errors.New("connection reset")
+
+	var out bytes.Buffer
+	err := Generate(context.Background(), source, uuid.New(), testPeriod, FormatJSON, &out)
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("json")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = ParseFormat("xlsx")
+	assert.Error(t, err)
+}
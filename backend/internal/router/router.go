@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/nouvadev/veridian/backend/internal/app"
+	"github.com/nouvadev/veridian/backend/internal/auth"
 	"github.com/nouvadev/veridian/backend/internal/handlers"
 	"github.com/nouvadev/veridian/backend/internal/middleware"
 )
@@ -17,28 +18,95 @@ func SetupRouter(app *app.App) *gin.Engine {
 	r.GET("/health", handlers.HealthHandler)
 
 	// Public auth routes
-	auth := r.Group("/auth")
+	authRoutes := r.Group("/auth")
 	{
-		auth.POST("/register", func(c *gin.Context) { handlers.RegisterHandler(c, app) })
-		auth.POST("/login", func(c *gin.Context) { handlers.LoginHandler(c, app) })
-		auth.POST("/refresh", func(c *gin.Context) { handlers.RefreshTokenHandler(c, app) })
-		auth.POST("/logout", func(c *gin.Context) { handlers.LogoutHandler(c, app) })
+		authRoutes.POST("/register", func(c *gin.Context) { handlers.RegisterHandler(c, app) })
+		authRoutes.POST("/login", func(c *gin.Context) { handlers.LoginHandler(c, app) })
+		authRoutes.POST("/refresh", func(c *gin.Context) { handlers.RefreshTokenHandler(c, app) })
+		authRoutes.POST("/logout", func(c *gin.Context) { handlers.LogoutHandler(c, app) })
+		authRoutes.POST("/verify-email", func(c *gin.Context) { handlers.VerifyEmailHandler(c, app) })
+		authRoutes.POST("/forgot-password", func(c *gin.Context) { handlers.ForgotPasswordHandler(c, app) })
+		authRoutes.POST("/reset-password", func(c *gin.Context) { handlers.ResetPasswordHandler(c, app) })
 	}
 
-	// Protected API routes
+	// Job submission can be limited to users with a verified email address
+	requireVerifiedEmail := func(c *gin.Context) { c.Next() }
+	if app.Accounts.RequireVerifiedEmail {
+		requireVerifiedEmail = middleware.VerifiedEmailMiddleware(app.Queries)
+	}
+
+	// Scope checks apply only to requests made with API keys
+	jobsRead := middleware.RequireScope(auth.ScopeJobsRead)
+	jobsWrite := middleware.RequireScope(auth.ScopeJobsWrite)
+	webhooksRead := middleware.RequireScope(auth.ScopeWebhooksRead)
+	webhooksWrite := middleware.RequireScope(auth.ScopeWebhooksWrite)
+	settingsRead := middleware.RequireScope(auth.ScopeSettingsRead)
+	settingsWrite := middleware.RequireScope(auth.ScopeSettingsWrite)
+
+	// Protected API routes - access tokens and API keys are accepted
 	api := r.Group("/api/v1")
-	api.Use(middleware.JWTAuthMiddleware(app.JWTManager))
+	api.Use(middleware.JWTAuthMiddleware(app.JWTManager, app.Queries))
 	{
+		// Account routes are only available to signed-in users, not API keys
+		account := api.Group("/auth", middleware.RequireSession())
+
 		// Auth profile routes
-		api.GET("/auth/profile", func(c *gin.Context) { handlers.GetProfileHandler(c, app) })
-		api.POST("/auth/change-password", func(c *gin.Context) { handlers.ChangePasswordHandler(c, app) })
+		account.GET("/profile", func(c *gin.Context) { handlers.GetProfileHandler(c, app) })
+		account.POST("/change-password", func(c *gin.Context) { handlers.ChangePasswordHandler(c, app) })
+		account.POST("/resend-verification", func(c *gin.Context) { handlers.ResendVerificationHandler(c, app) })
+
+		// Signed-in devices and clients of the caller's account
+		account.GET("/sessions", func(c *gin.Context) { handlers.GetSessionsHandler(c, app) })
+		account.DELETE("/sessions/:id", func(c *gin.Context) { handlers.RevokeSessionHandler(c, app) })
+		account.POST("/sessions/revoke-others", func(c *gin.Context) { handlers.RevokeOtherSessionsHandler(c, app) })
+
+		// Long-lived API keys for scripts and CI pipelines
+		account.POST("/api-keys", func(c *gin.Context) { handlers.CreateAPIKeyHandler(c, app) })
+		account.GET("/api-keys", func(c *gin.Context) { handlers.GetAPIKeysHandler(c, app) })
+		account.DELETE("/api-keys/:id", func(c *gin.Context) { handlers.RevokeAPIKeyHandler(c, app) })
 
 		// Job routes - all protected
-		api.POST("/jobs", func(c *gin.Context) { handlers.CreateJob(c, app) })
-		api.GET("/jobs", func(c *gin.Context) { handlers.GetJobs(c, app) })
-		api.GET("/jobs/:id", func(c *gin.Context) { handlers.GetJob(c, app) })
-		api.PUT("/jobs/:id", func(c *gin.Context) { handlers.UpdateJob(c, app) })
-		api.DELETE("/jobs/:id", func(c *gin.Context) { handlers.DeleteJob(c, app) })
+		api.POST("/jobs", jobsWrite, requireVerifiedEmail, func(c *gin.Context) { handlers.CreateJob(c, app) })
+		api.GET("/jobs", jobsRead, func(c *gin.Context) { handlers.GetJobs(c, app) })
+		api.GET("/jobs/:id", jobsRead, func(c *gin.Context) { handlers.GetJob(c, app) })
+		api.PUT("/jobs/:id", jobsWrite, func(c *gin.Context) { handlers.UpdateJob(c, app) })
+		api.DELETE("/jobs/:id", jobsWrite, func(c *gin.Context) { handlers.DeleteJob(c, app) })
+
+		// Execution routes - scoped to jobs owned by the caller
+		api.POST("/jobs/:id/run", jobsWrite, requireVerifiedEmail, func(c *gin.Context) { handlers.RunJob(c, app) })
+		api.GET("/jobs/:id/executions", jobsRead, func(c *gin.Context) { handlers.GetJobExecutions(c, app) })
+		api.GET("/jobs/:id/executions/stats", jobsRead, func(c *gin.Context) { handlers.GetJobExecutionStats(c, app) })
+		api.GET("/jobs/:id/executions/:execution_id", jobsRead, func(c *gin.Context) { handlers.GetJobExecution(c, app) })
+		api.GET("/jobs/:id/executions/:execution_id/events", jobsRead, func(c *gin.Context) { handlers.GetJobExecutionEvents(c, app) })
+		api.GET("/jobs/:id/executions/:execution_id/savings", jobsRead, func(c *gin.Context) { handlers.GetJobExecutionSavings(c, app) })
+		api.GET("/executions/:id/logs", jobsRead, func(c *gin.Context) { handlers.GetExecutionLogs(c, app) })
+
+		// Live execution status changes and output, for all of the caller's jobs or one job
+		api.GET("/events", jobsRead, func(c *gin.Context) { handlers.StreamExecutionEvents(c, app) })
+
+		// Dashboard aggregates, and savings against running immediately in the default region
+		api.GET("/stats", jobsRead, func(c *gin.Context) { handlers.GetStats(c, app) })
+		api.GET("/savings", jobsRead, func(c *gin.Context) { handlers.GetUserSavings(c, app) })
+		api.GET("/reports/sustainability", jobsRead, func(c *gin.Context) { handlers.GetSustainabilityReport(c, app) })
+
+		// Webhook subscriptions and their delivery history
+		api.POST("/webhooks", webhooksWrite, func(c *gin.Context) { handlers.CreateWebhook(c, app) })
+		api.GET("/webhooks", webhooksRead, func(c *gin.Context) { handlers.GetWebhooks(c, app) })
+		api.GET("/webhooks/:id", webhooksRead, func(c *gin.Context) { handlers.GetWebhook(c, app) })
+		api.DELETE("/webhooks/:id", webhooksWrite, func(c *gin.Context) { handlers.DeleteWebhook(c, app) })
+		api.GET("/webhooks/:id/deliveries", webhooksRead, func(c *gin.Context) { handlers.GetWebhookDeliveries(c, app) })
+		api.GET("/webhooks/:id/deliveries/:delivery_id/attempts", webhooksRead, func(c *gin.Context) { handlers.GetWebhookDeliveryAttempts(c, app) })
+
+		// Settings routes
+		api.GET("/settings", settingsRead, func(c *gin.Context) { handlers.GetSettings(c, app) })
+		api.PUT("/settings", settingsWrite, func(c *gin.Context) { handlers.UpdateSettings(c, app) })
+	}
+
+	// Admin routes - protected and restricted to administrators signed in with a session
+	admin := r.Group("/api/v1/admin")
+	admin.Use(middleware.JWTAuthMiddleware(app.JWTManager, nil), middleware.AdminMiddleware(app.Queries))
+	{
+		admin.GET("/executions/orphaned", func(c *gin.Context) { handlers.GetOrphanedExecutions(c, app) })
 	}
 
 	return r
@@ -0,0 +1,91 @@
+// Package schedule parses cron expressions for recurring jobs and computes
+// their fire times in the job's timezone.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	// Embed the timezone database so schedules work in minimal containers
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is used when a job does not specify one
+const DefaultTimezone = "UTC"
+
+// Schedule is a parsed cron expression bound to a timezone
+type Schedule struct {
+	spec     cron.Schedule
+	location *time.Location
+}
+
+// Parse parses a standard five-field cron expression (or a descriptor such
+// as @daily) evaluated in the given IANA timezone. An empty timezone means UTC.
+func Parse(expr, timezone string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, errors.New("cron expression is required")
+	}
+	// The timezone is a separate field; inline overrides would bypass it
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, errors.New("cron expression must not contain a timezone; use the timezone field")
+	}
+
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	// The server's local zone would make schedules depend on where they run
+	if timezone == "Local" {
+		return nil, errors.New("timezone must be an IANA name such as Europe/Amsterdam")
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+
+	spec, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", location.String(), expr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	s := &Schedule{spec: spec, location: location}
+
+	// Expressions such as "0 0 30 2 *" parse but never fire
+	if s.Next(time.Now()).IsZero() {
+		return nil, errors.New("cron expression never fires")
+	}
+
+	return s, nil
+}
+
+// Next returns the first fire time strictly after t, in UTC.
+// The zero time is returned if the schedule never fires again.
+func (s *Schedule) Next(t time.Time) time.Time {
+	next := s.spec.Next(t)
+	if next.IsZero() {
+		return next
+	}
+	return next.UTC()
+}
+
+// NextN returns up to n consecutive fire times after t, in UTC
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for len(times) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// Location returns the timezone the schedule is evaluated in
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+	}{
+		{"empty", "", "UTC"},
+		{"garbage", "every night", "UTC"},
+		{"too many fields", "0 0 2 * * * *", "UTC"},
+		{"out of range", "0 25 * * *", "UTC"},
+		{"unknown timezone", "0 2 * * *", "Mars/Olympus_Mons"},
+		{"server local timezone", "0 2 * * *", "Local"},
+		{"inline timezone", "CRON_TZ=Europe/Amsterdam 0 2 * * *", "UTC"},
+		{"never fires", "0 0 30 2 *", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr, tt.timezone)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_NextInTimezone(t *testing.T) {
+	s, err := Parse("0 2 * * *", "Europe/Amsterdam")
+	require.NoError(t, err)
+
+	// 02:00 in Amsterdam is 01:00 UTC in winter and 00:00 UTC in summer
+	winter := s.Next(time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 16, 1, 0, 0, 0, time.UTC), winter)
+
+	summer := s.Next(time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 7, 16, 0, 0, 0, 0, time.UTC), summer)
+}
+
+func TestSchedule_NextN(t *testing.T) {
+	s, err := Parse("@daily", "")
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", s.Location().String())
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := s.NextN(start, 3)
+	require.Len(t, times, 3)
+	assert.Equal(t, start.Add(24*time.Hour), times[0])
+	assert.Equal(t, start.Add(72*time.Hour), times[2])
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+// Slot is a candidate region and start time with its expected cost and carbon intensity
+type Slot struct {
+	Region         string
+	VMType         string
+	StartAt        time.Time
+	GramsPerKWh    float64
+	HourlyPriceUSD float64
+}
+
+// Weights expresses how much a user cares about cost versus carbon (sum to 1.0)
+type Weights struct {
+	Cost   float64
+	Carbon float64
+}
+
+// DefaultWeights matches the user_settings column defaults
+var DefaultWeights = Weights{Cost: 0.5, Carbon: 0.5}
+
+// Window is the interval in which an execution may start
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// SearchWindow returns the start window for an execution submitted at submittedAt
+// with the given delay tolerance. Slots in the past are never offered, so a window
+// that has already closed collapses to now.
+func SearchWindow(submittedAt, now time.Time, toleranceHours int32) Window {
+	start := submittedAt
+	if now.After(start) {
+		start = now
+	}
+
+	end := submittedAt.Add(time.Duration(toleranceHours) * time.Hour)
+	if end.Before(start) {
+		end = start
+	}
+
+	return Window{Start: start, End: end}
+}
+
+// BestSlot returns the slot with the lowest weighted score. Cost and carbon are
+// min-max normalised across all candidates so the weights compare like with like.
+// Ties go to the earliest slot, then to the first candidate given.
+func BestSlot(slots []Slot, weights Weights) (Slot, bool) {
+	if len(slots) == 0 {
+		return Slot{}, false
+	}
+
+	minCost, maxCost := math.Inf(1), math.Inf(-1)
+	minCarbon, maxCarbon := math.Inf(1), math.Inf(-1)
+	for _, slot := range slots {
+		minCost = math.Min(minCost, slot.HourlyPriceUSD)
+		maxCost = math.Max(maxCost, slot.HourlyPriceUSD)
+		minCarbon = math.Min(minCarbon, slot.GramsPerKWh)
+		maxCarbon = math.Max(maxCarbon, slot.GramsPerKWh)
+	}
+
+	best := 0
+	bestScore := math.Inf(1)
+	for i, slot := range slots {
+		score := weights.Cost*normalise(slot.HourlyPriceUSD, minCost, maxCost) +
+			weights.Carbon*normalise(slot.GramsPerKWh, minCarbon, maxCarbon)
+
+		if score < bestScore || (score == bestScore && slot.StartAt.Before(slots[best].StartAt)) {
+			best = i
+			bestScore = score
+		}
+	}
+
+	return slots[best], true
+}
+
+// normalise maps value into [0, 1] relative to min and max
+func normalise(value, min, max float64) float64 {
+	if max <= min {
+		return 0
+	}
+	return (value - min) / (max - min)
+}
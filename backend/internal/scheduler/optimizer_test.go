@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchWindow(t *testing.T) {
+	submitted := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	window := SearchWindow(submitted, submitted.Add(time.Hour), 6)
+	assert.Equal(t, submitted.Add(time.Hour), window.Start)
+	assert.Equal(t, submitted.Add(6*time.Hour), window.End)
+
+	// A window that has already closed collapses to now
+	late := submitted.Add(10 * time.Hour)
+	window = SearchWindow(submitted, late, 6)
+	assert.Equal(t, late, window.Start)
+	assert.Equal(t, late, window.End)
+}
+
+func TestBestSlot(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	slots := []Slot{
+		{Region: "westeurope", StartAt: start, GramsPerKWh: 300, HourlyPriceUSD: 0.08},
+		{Region: "swedencentral", StartAt: start, GramsPerKWh: 20, HourlyPriceUSD: 0.12},
+	}
+
+	tests := []struct {
+		name    string
+		weights Weights
+		want    string
+	}{
+		{"cost only", Weights{Cost: 1, Carbon: 0}, "westeurope"},
+		{"carbon only", Weights{Cost: 0, Carbon: 1}, "swedencentral"},
+		{"mostly cost", Weights{Cost: 0.9, Carbon: 0.1}, "westeurope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slot, ok := BestSlot(slots, tt.weights)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, slot.Region)
+		})
+	}
+}
+
+func TestBestSlot_TiesPreferEarliest(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	slots := []Slot{
+		{Region: "westeurope", StartAt: start.Add(2 * time.Hour), GramsPerKWh: 100},
+		{Region: "northeurope", StartAt: start, GramsPerKWh: 100},
+	}
+
+	slot, ok := BestSlot(slots, DefaultWeights)
+	require.True(t, ok)
+	assert.Equal(t, "northeurope", slot.Region)
+
+	_, ok = BestSlot(nil, DefaultWeights)
+	assert.False(t, ok)
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/guregu/null/v6"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/schedule"
+)
+
+// FireSchedules creates a pending execution for every recurring job whose next
+// fire time has passed and returns how many were created. Each execution is
+// anchored at its nominal fire time so the delay tolerance window starts there.
+//
+// After downtime only the oldest missed occurrence is fired; the schedule then
+// skips ahead to the next fire time after now rather than replaying a backlog.
+func (s *Scheduler) FireSchedules(ctx context.Context) (int, error) {
+	now := s.now()
+
+	jobs, err := s.store.GetDueJobSchedules(ctx, database.GetDueJobSchedulesParams{
+		DueBefore: null.TimeFrom(now),
+		BatchSize: s.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		ok, err := s.fireSchedule(ctx, job)
+		if err != nil {
+			log.Printf("scheduler: job %s: %v", job.ID, err)
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+
+	return fired, nil
+}
+
+// fireSchedule creates the execution for a job's due occurrence and advances its
+// schedule. It returns false if another replica fired the occurrence first.
+func (s *Scheduler) fireSchedule(ctx context.Context, job database.Job) (bool, error) {
+	if job.CronSchedule == nil {
+		return false, nil
+	}
+
+	sched, err := schedule.Parse(*job.CronSchedule, job.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	next := sched.Next(s.now())
+	rows, err := s.store.FireJobSchedule(ctx, database.FireJobScheduleParams{
+		JobID:        job.ID,
+		ScheduledFor: job.NextRunAt,
+		// A schedule that never fires again leaves next_run_at NULL
+		NextRunAt: null.NewTime(next, !next.IsZero()),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to fire schedule: %w", err)
+	}
+
+	return rows > 0, nil
+}
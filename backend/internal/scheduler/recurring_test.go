@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+func TestFireSchedules_CreatesOccurrenceAndAdvances(t *testing.T) {
+	nightly := "0 2 * * *"
+	missed := time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)
+	future := time.Date(2025, 1, 2, 2, 0, 0, 0, time.UTC)
+
+	store := newMockStore()
+	store.recurring = []database.Job{
+		{ID: uuid.New(), CronSchedule: &nightly, Timezone: "UTC", NextRunAt: null.TimeFrom(missed)},
+		{ID: uuid.New(), CronSchedule: &nightly, Timezone: "UTC", NextRunAt: null.TimeFrom(future)},
+	}
+
+	s := newTestScheduler(store)
+	fired, err := s.FireSchedules(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+
+	require.Len(t, store.fired, 1)
+	assert.Equal(t, missed, store.fired[0].ScheduledFor.Time)
+	// The next occurrence is computed from now, not from the missed fire time
+	assert.Equal(t, future, store.fired[0].NextRunAt.Time)
+
+	require.Len(t, store.executions, 1)
+	for _, e := range store.executions {
+		assert.Equal(t, database.ExecutionStatusPending, e.Status)
+		assert.Equal(t, missed, e.ScheduledFor.Time)
+	}
+}
+
+func TestProcessPending_AnchorsWindowAtFireTime(t *testing.T) {
+	fireTime := time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC)
+	pending := database.Execution{
+		ID:           uuid.New(),
+		JobID:        uuid.New(),
+		Status:       database.ExecutionStatusPending,
+		ScheduledFor: null.TimeFrom(fireTime),
+		CreatedAt:    fireTime.Add(5 * time.Minute),
+	}
+	store := newMockStore(pending)
+	store.jobs[pending.JobID] = database.GetJobSchedulingInfoRow{DelayToleranceHours: 4, CostWeight: 0, CarbonWeight: 1}
+
+	// The clean slot is within four hours of creation but not of the fire time
+	provider, err := carbon.LoadCSV(strings.NewReader(`region,timestamp,g_co2_per_kwh
+westeurope,2025-01-01T02:00:00Z,300
+westeurope,2025-01-01T06:03:00Z,50
+`))
+	require.NoError(t, err)
+
+	s := newTestScheduler(store)
+	s.carbon = provider
+	s.now = func() time.Time { return fireTime.Add(10 * time.Minute) }
+
+	_, err = s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	result := store.executions[pending.ID]
+	assert.Equal(t, s.now(), result.PlannedStartAt.Time)
+	assert.Equal(t, 300.0, result.CarbonIntensityGKwh.Float64)
+}
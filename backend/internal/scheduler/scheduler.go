@@ -0,0 +1,419 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+// Store is the subset of database.Querier used by the scheduler
+type Store interface {
+	ClaimExecutions(ctx context.Context, opts database.ClaimOptions) ([]database.Execution, error)
+	UpdateExecutionScheduling(ctx context.Context, arg database.UpdateExecutionSchedulingParams) (database.Execution, error)
+	UpdateExecutionCostEstimate(ctx context.Context, arg database.UpdateExecutionCostEstimateParams) (database.Execution, error)
+	UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error)
+	GetJobSchedulingInfo(ctx context.Context, id uuid.UUID) (database.GetJobSchedulingInfoRow, error)
+	GetDueJobSchedules(ctx context.Context, arg database.GetDueJobSchedulesParams) ([]database.Job, error)
+	FireJobSchedule(ctx context.Context, arg database.FireJobScheduleParams) (int64, error)
+}
+
+// Config holds scheduler configuration
+type Config struct {
+	WorkerID      string        // Lease owner identifier, unique per replica
+	PollInterval  time.Duration // How often pending executions are polled
+	BatchSize     int32         // Maximum executions claimed (and schedules fired) per poll
+	LeaseDuration time.Duration // How long a claimed execution is held before returning to pending
+	Regions       []string      // Candidate cloud regions, in order of preference
+	DefaultVMType string        // VM type assigned to scheduled executions when no catalogue is configured
+
+	// VMSelection picks among the VM types that satisfy a job's requirements
+	VMSelection pricing.Strategy
+	// DefaultRuntime is the expected runtime of jobs that do not declare one
+	DefaultRuntime time.Duration
+}
+
+// ErrUnschedulable is returned when no candidate region can run an execution.
+// Such executions are failed rather than retried.
+var ErrUnschedulable = errors.New("execution cannot be scheduled")
+
+// Placement describes where and when an execution should run
+type Placement struct {
+	Region      string
+	VMType      string
+	StartAt     time.Time
+	GramsPerKWh null.Float // Forecast intensity at StartAt, if known
+	CostUSD     null.Float // Estimated cost of the expected runtime, if the VM type is priced
+	Reason      string     // Why this slot was chosen, for the execution's history
+	Baseline    *Baseline  // Where the execution would have run without carbon-aware scheduling
+}
+
+// Baseline is the counterfactual an execution's savings are measured against: running
+// immediately at submission time in the default (most preferred) region
+type Baseline struct {
+	Region  string
+	VMType  string
+	StartAt time.Time
+}
+
+// Scheduler drains pending executions and assigns them a region and start time
+type Scheduler struct {
+	store  Store
+	carbon carbon.Provider
+	prices *pricing.Estimator
+	config Config
+	now    func() time.Time
+}
+
+// NewScheduler creates a new scheduler. The carbon provider is optional; without
+// one, executions are scheduled without intensity estimates. Without a price
+// estimator every region is treated as equally expensive and no cost is estimated.
+func NewScheduler(store Store, provider carbon.Provider, prices *pricing.Estimator, config Config) *Scheduler {
+	return &Scheduler{
+		store:  store,
+		carbon: provider,
+		prices: prices,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run fires due recurring schedules and polls for pending executions until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.config.Regions) == 0 {
+		return errors.New("scheduler requires at least one region")
+	}
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		fired, err := s.FireSchedules(ctx)
+		if err != nil {
+			log.Printf("scheduler: failed to fire recurring schedules: %v", err)
+		} else if fired > 0 {
+			log.Printf("scheduler: created %d execution(s) from recurring schedules", fired)
+		}
+
+		scheduled, err := s.ProcessPending(ctx)
+		if err != nil {
+			log.Printf("scheduler: failed to process pending executions: %v", err)
+		} else if scheduled > 0 {
+			log.Printf("scheduler: scheduled %d execution(s)", scheduled)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending claims a batch of pending executions, schedules them and returns how many were scheduled
+func (s *Scheduler) ProcessPending(ctx context.Context) (int, error) {
+	executions, err := s.store.ClaimExecutions(ctx, database.ClaimOptions{
+		Owner:         s.config.WorkerID,
+		BatchSize:     s.config.BatchSize,
+		LeaseDuration: s.config.LeaseDuration,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	scheduled := 0
+	for _, execution := range executions {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// On failure the lease expires and the execution returns to pending
+		if err := s.scheduleExecution(ctx, execution); err != nil {
+			log.Printf("scheduler: execution %s: %v", execution.ID, err)
+			continue
+		}
+		scheduled++
+	}
+
+	return scheduled, nil
+}
+
+// scheduleExecution records the placement of a claimed execution and releases its lease
+func (s *Scheduler) scheduleExecution(ctx context.Context, execution database.Execution) error {
+	placement, err := s.choosePlacement(ctx, execution)
+	if errors.Is(err, ErrUnschedulable) {
+		return s.failUnschedulable(ctx, execution, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	delay := placement.StartAt.Sub(s.now())
+	reason := fmt.Sprintf("placed in %s to start at %s", placement.Region, placement.StartAt.Format(time.RFC3339))
+	if delay >= time.Minute {
+		reason += fmt.Sprintf(", deferred %s", delay.Round(time.Minute))
+	}
+
+	transition := database.Transition{
+		ExecutionID: execution.ID,
+		From:        execution.Status,
+		To:          database.ExecutionStatusEvaluating,
+		Actor:       database.ExecutionActorScheduler,
+		Reason:      reason + ": " + placement.Reason,
+	}
+	if err := transition.Validate(); err != nil {
+		return err
+	}
+
+	// The execution stays in evaluating until an executor picks it up at the planned start
+	params := database.UpdateExecutionSchedulingParams{
+		ID:             execution.ID,
+		Status:         transition.To,
+		Actor:          transition.Actor,
+		Reason:         transition.Reason,
+		ChosenAt:       null.TimeFrom(s.now()),
+		CloudRegion:    &placement.Region,
+		VmType:         &placement.VMType,
+		PlannedStartAt: null.TimeFrom(placement.StartAt),
+	}
+	if placement.Baseline != nil {
+		params.BaselineRegion = &placement.Baseline.Region
+		params.BaselineVmType = &placement.Baseline.VMType
+		params.BaselineStartAt = null.TimeFrom(placement.Baseline.StartAt)
+	}
+	_, err = s.store.UpdateExecutionScheduling(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to record scheduling decision: %w", err)
+	}
+
+	if delay >= time.Minute {
+		log.Printf("scheduler: execution %s deferred %s to %s in %s", execution.ID, delay.Round(time.Minute), placement.StartAt.Format(time.RFC3339), placement.Region)
+	}
+
+	s.recordEstimate(ctx, execution, placement)
+
+	return nil
+}
+
+// failUnschedulable marks an execution that no region can run as completed_error,
+// so it is not claimed again, and returns cause
+func (s *Scheduler) failUnschedulable(ctx context.Context, execution database.Execution, cause error) error {
+	transition := database.Transition{
+		ExecutionID: execution.ID,
+		From:        execution.Status,
+		To:          database.ExecutionStatusCompletedError,
+		Actor:       database.ExecutionActorScheduler,
+		Reason:      cause.Error(),
+	}
+	if err := transition.Validate(); err != nil {
+		return fmt.Errorf("%w (and cannot record failure: %v)", cause, err)
+	}
+
+	if _, err := s.store.UpdateExecutionComplete(ctx, database.UpdateExecutionCompleteParams{
+		ID:          execution.ID,
+		FromStatus:  transition.From,
+		Status:      transition.To,
+		CompletedAt: null.TimeFrom(s.now()),
+		Actor:       transition.Actor,
+		Reason:      transition.Reason,
+	}); err != nil {
+		return fmt.Errorf("%w (and failed to record failure: %v)", cause, err)
+	}
+
+	return cause
+}
+
+// recordEstimate stores the estimated cost and forecast carbon intensity of the chosen
+// slot. Estimates are informational, so failures are logged rather than failing the execution.
+func (s *Scheduler) recordEstimate(ctx context.Context, execution database.Execution, placement Placement) {
+	if !placement.CostUSD.Valid && !placement.GramsPerKWh.Valid {
+		return
+	}
+
+	_, err := s.store.UpdateExecutionCostEstimate(ctx, database.UpdateExecutionCostEstimateParams{
+		ID:                  execution.ID,
+		CostEstimateUsd:     placement.CostUSD,
+		CarbonIntensityGKwh: placement.GramsPerKWh,
+	})
+	if err != nil {
+		log.Printf("scheduler: execution %s: failed to record estimate: %v", execution.ID, err)
+	}
+}
+
+// choosePlacement searches every candidate region across the execution's delay
+// tolerance window and picks the slot with the best weighted cost/carbon score
+func (s *Scheduler) choosePlacement(ctx context.Context, execution database.Execution) (Placement, error) {
+	info, err := s.store.GetJobSchedulingInfo(ctx, execution.JobID)
+	if err != nil {
+		return Placement{}, fmt.Errorf("failed to load job scheduling info: %w", err)
+	}
+
+	// A per-run delay tolerance overrides the job definition
+	tolerance := info.DelayToleranceHours
+	if execution.DelayToleranceHours != nil {
+		tolerance = *execution.DelayToleranceHours
+	}
+
+	// Recurring occurrences are anchored at their nominal fire time
+	anchor := execution.CreatedAt
+	if execution.ScheduledFor.Valid {
+		anchor = execution.ScheduledFor.Time
+	}
+
+	req := pricing.Requirements{
+		CPUCores: int(info.CpuCores),
+		MemoryMB: int(info.MemoryMb),
+		DiskGB:   int(info.DiskGb),
+	}
+	runtime := s.config.DefaultRuntime
+	if info.ExpectedRuntimeMinutes != nil {
+		runtime = time.Duration(*info.ExpectedRuntimeMinutes) * time.Minute
+	}
+
+	machines := s.selectMachines(execution, req)
+	if len(machines) == 0 {
+		return Placement{}, fmt.Errorf("%w: no candidate region offers a VM type with %s", ErrUnschedulable, req)
+	}
+
+	// Without carbon-aware scheduling the execution would have started on submission
+	// in the default region. If nothing fits there, there is no baseline to compare with.
+	var baseline *Baseline
+	if machines[0].Region == s.config.Regions[0] {
+		baseline = &Baseline{Region: machines[0].Region, VMType: machines[0].VMType, StartAt: anchor}
+	}
+
+	weights := Weights{Cost: info.CostWeight, Carbon: info.CarbonWeight}
+	window := SearchWindow(anchor, s.now(), tolerance)
+
+	slots := s.candidateSlots(ctx, execution, window, machines)
+	slot, ok := BestSlot(slots, weights)
+	if !ok {
+		// No usable forecast: run now in the preferred region
+		placement := Placement{
+			Region:   machines[0].Region,
+			VMType:   machines[0].VMType,
+			StartAt:  window.Start,
+			Reason:   "no carbon forecast available, running as soon as possible in the preferred region",
+			Baseline: baseline,
+		}
+		placement.CostUSD = s.estimateCost(execution, placement, runtime)
+		return placement, nil
+	}
+
+	placement := Placement{
+		Region:  slot.Region,
+		VMType:  slot.VMType,
+		StartAt: slot.StartAt,
+		Reason: fmt.Sprintf("best score (cost weight %.2f, carbon weight %.2f) among %d slot(s) within %dh delay tolerance",
+			weights.Cost, weights.Carbon, len(slots), tolerance),
+		Baseline: baseline,
+	}
+	if s.carbon != nil {
+		placement.GramsPerKWh = null.FloatFrom(slot.GramsPerKWh)
+		placement.Reason += fmt.Sprintf(", forecast %.0f gCO2/kWh", slot.GramsPerKWh)
+	}
+	if s.prices != nil {
+		placement.Reason += fmt.Sprintf(", %s as the %s VM type with %s", slot.VMType, s.config.VMSelection, req)
+	}
+	placement.CostUSD = s.estimateCost(execution, placement, runtime)
+
+	return placement, nil
+}
+
+// machine is the VM type selected for an execution in one region
+type machine struct {
+	Region         string
+	VMType         string
+	HourlyPriceUSD float64
+}
+
+// selectMachines picks a VM type satisfying the requirements in every candidate
+// region, in order of preference. Regions where nothing fits are skipped. Without a
+// catalogue every region is offered the default VM type at the same price.
+func (s *Scheduler) selectMachines(execution database.Execution, req pricing.Requirements) []machine {
+	var machines []machine
+
+	for _, region := range s.config.Regions {
+		if s.prices == nil {
+			machines = append(machines, machine{Region: region, VMType: s.config.DefaultVMType})
+			continue
+		}
+
+		selection, err := s.prices.Select(region, req, s.config.VMSelection)
+		if err != nil {
+			log.Printf("scheduler: execution %s: skipping %s: %v", execution.ID, region, err)
+			continue
+		}
+		machines = append(machines, machine{
+			Region:         region,
+			VMType:         selection.VMType.Name,
+			HourlyPriceUSD: selection.HourlyPrice,
+		})
+	}
+
+	return machines
+}
+
+// estimateCost prices the expected runtime of a placement, if the VM type is in the catalogue
+func (s *Scheduler) estimateCost(execution database.Execution, placement Placement, runtime time.Duration) null.Float {
+	if s.prices == nil {
+		return null.Float{}
+	}
+
+	cost, err := s.prices.Estimate(placement.Region, placement.VMType, runtime)
+	if err != nil {
+		log.Printf("scheduler: execution %s: no cost estimate: %v", execution.ID, err)
+		return null.Float{}
+	}
+	return null.FloatFrom(cost)
+}
+
+// candidateSlots builds one slot per forecast reading per region within the window.
+// Without a carbon provider every region is offered at the start of the window.
+func (s *Scheduler) candidateSlots(ctx context.Context, execution database.Execution, window Window, machines []machine) []Slot {
+	var slots []Slot
+
+	for _, m := range machines {
+		if s.carbon == nil {
+			slots = append(slots, Slot{Region: m.Region, VMType: m.VMType, StartAt: window.Start, HourlyPriceUSD: m.HourlyPriceUSD})
+			continue
+		}
+
+		forecast, err := s.carbon.Forecast(ctx, m.Region, window.Start, window.End)
+		if err != nil {
+			log.Printf("scheduler: execution %s: no forecast for %s: %v", execution.ID, m.Region, err)
+			continue
+		}
+
+		// Readings are ordered; only the latest one at or before the window start is
+		// in effect when it opens, and readings after the window end are never offered
+		for i, reading := range forecast {
+			if reading.Timestamp.After(window.End) {
+				break
+			}
+			start := reading.Timestamp
+			if !start.After(window.Start) {
+				if i+1 < len(forecast) && !forecast[i+1].Timestamp.After(window.Start) {
+					continue
+				}
+				start = window.Start
+			}
+			slots = append(slots, Slot{
+				Region:         m.Region,
+				VMType:         m.VMType,
+				StartAt:        start,
+				GramsPerKWh:    reading.GramsPerKWh,
+				HourlyPriceUSD: m.HourlyPriceUSD,
+			})
+		}
+	}
+
+	return slots
+}
@@ -0,0 +1,461 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/carbon"
+	"github.com/nouvadev/veridian/backend/internal/database"
+	"github.com/nouvadev/veridian/backend/internal/pricing"
+)
+
+// mockStore is an in-memory implementation of Store
+type mockStore struct {
+	executions map[uuid.UUID]database.Execution
+	jobs       map[uuid.UUID]database.GetJobSchedulingInfoRow
+	recurring  []database.Job
+	fired      []database.FireJobScheduleParams
+	claims     []database.ClaimOptions
+	events     []database.CreateExecutionEventParams
+	failOn     uuid.UUID
+}
+
+func newMockStore(executions ...database.Execution) *mockStore {
+	m := &mockStore{
+		executions: make(map[uuid.UUID]database.Execution),
+		jobs:       make(map[uuid.UUID]database.GetJobSchedulingInfoRow),
+	}
+	for _, e := range executions {
+		m.executions[e.ID] = e
+	}
+	return m
+}
+
+func (m *mockStore) ClaimExecutions(ctx context.Context, opts database.ClaimOptions) ([]database.Execution, error) {
+	m.claims = append(m.claims, opts)
+
+	var claimed []database.Execution
+	for id, e := range m.executions {
+		if e.Status == database.ExecutionStatusPending && int32(len(claimed)) < opts.BatchSize {
+			e.Status = database.ExecutionStatusEvaluating
+			e.LeaseOwner = &opts.Owner
+			m.executions[id] = e
+			claimed = append(claimed, e)
+			m.record(id, database.ExecutionStatusPending, e.Status, database.ExecutionActorScheduler, "claimed by scheduler "+opts.Owner)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *mockStore) UpdateExecutionScheduling(ctx context.Context, arg database.UpdateExecutionSchedulingParams) (database.Execution, error) {
+	if arg.ID == m.failOn {
+		return database.Execution{}, fmt.Errorf("database error")
+	}
+	e := m.executions[arg.ID]
+	e.Status = arg.Status
+	e.ChosenAt = arg.ChosenAt
+	e.CloudRegion = arg.CloudRegion
+	e.VmType = arg.VmType
+	e.PlannedStartAt = arg.PlannedStartAt
+	e.BaselineRegion = arg.BaselineRegion
+	e.BaselineVmType = arg.BaselineVmType
+	e.BaselineStartAt = arg.BaselineStartAt
+	e.LeaseOwner = nil
+	m.executions[arg.ID] = e
+	m.record(arg.ID, database.ExecutionStatusEvaluating, arg.Status, arg.Actor, arg.Reason)
+	return e, nil
+}
+
+func (m *mockStore) UpdateExecutionCostEstimate(ctx context.Context, arg database.UpdateExecutionCostEstimateParams) (database.Execution, error) {
+	e := m.executions[arg.ID]
+	e.CostEstimateUsd = arg.CostEstimateUsd
+	e.CarbonIntensityGKwh = arg.CarbonIntensityGKwh
+	m.executions[arg.ID] = e
+	return e, nil
+}
+
+func (m *mockStore) UpdateExecutionComplete(ctx context.Context, arg database.UpdateExecutionCompleteParams) (database.Execution, error) {
+	e := m.executions[arg.ID]
+	if e.Status != arg.FromStatus {
+		return database.Execution{}, pgx.ErrNoRows
+	}
+	e.Status = arg.Status
+	e.CompletedAt = arg.CompletedAt
+	e.LeaseOwner = nil
+	m.executions[arg.ID] = e
+	m.record(arg.ID, arg.FromStatus, arg.Status, arg.Actor, arg.Reason)
+	return e, nil
+}
+
+func (m *mockStore) GetJobSchedulingInfo(ctx context.Context, id uuid.UUID) (database.GetJobSchedulingInfoRow, error) {
+	if info, ok := m.jobs[id]; ok {
+		return info, nil
+	}
+	return database.GetJobSchedulingInfoRow{CostWeight: 0.5, CarbonWeight: 0.5}, nil
+}
+
+func (m *mockStore) GetDueJobSchedules(ctx context.Context, arg database.GetDueJobSchedulesParams) ([]database.Job, error) {
+	var due []database.Job
+	for _, job := range m.recurring {
+		if job.NextRunAt.Valid && !job.NextRunAt.Time.After(arg.DueBefore.Time) {
+			due = append(due, job)
+		}
+	}
+	return due, nil
+}
+
+func (m *mockStore) FireJobSchedule(ctx context.Context, arg database.FireJobScheduleParams) (int64, error) {
+	for i, job := range m.recurring {
+		if job.ID != arg.JobID || !job.NextRunAt.Time.Equal(arg.ScheduledFor.Time) {
+			continue
+		}
+		m.recurring[i].NextRunAt = arg.NextRunAt
+		m.fired = append(m.fired, arg)
+
+		e := database.Execution{ID: uuid.New(), JobID: job.ID, Status: database.ExecutionStatusPending, ScheduledFor: arg.ScheduledFor}
+		m.executions[e.ID] = e
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// record stores a transition, as the status-changing queries do
+func (m *mockStore) record(id uuid.UUID, from, to database.ExecutionStatus, actor database.ExecutionActor, reason string) {
+	m.events = append(m.events, database.Transition{ExecutionID: id, From: from, To: to, Actor: actor, Reason: reason}.Event())
+}
+
+// eventsFor returns the transitions recorded for one execution, in order
+func (m *mockStore) eventsFor(id uuid.UUID) []database.CreateExecutionEventParams {
+	var events []database.CreateExecutionEventParams
+	for _, event := range m.events {
+		if event.ExecutionID == id {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func newTestScheduler(store Store) *Scheduler {
+	s := NewScheduler(store, nil, nil, Config{
+		WorkerID:       "test-worker",
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		LeaseDuration:  time.Minute,
+		Regions:        []string{"westeurope", "northeurope"},
+		DefaultVMType:  "Standard_D2s_v5",
+		VMSelection:    pricing.Cheapest,
+		DefaultRuntime: 2 * time.Hour,
+	})
+	s.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func TestProcessPending_SchedulesExecutions(t *testing.T) {
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending}
+	running := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusRunning}
+	store := newMockStore(pending, running)
+
+	s := newTestScheduler(store)
+	scheduled, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, scheduled)
+
+	result := store.executions[pending.ID]
+	assert.Equal(t, database.ExecutionStatusEvaluating, result.Status)
+	require.NotNil(t, result.CloudRegion)
+	assert.Equal(t, "westeurope", *result.CloudRegion)
+	require.NotNil(t, result.VmType)
+	assert.Equal(t, "Standard_D2s_v5", *result.VmType)
+	assert.True(t, result.ChosenAt.Valid)
+	assert.Equal(t, s.now(), result.PlannedStartAt.Time)
+	assert.Nil(t, result.LeaseOwner)
+
+	// Non-pending executions are left alone
+	assert.Equal(t, database.ExecutionStatusRunning, store.executions[running.ID].Status)
+
+	require.Len(t, store.claims, 1)
+	assert.Equal(t, "test-worker", store.claims[0].Owner)
+	assert.Equal(t, int32(10), store.claims[0].BatchSize)
+
+	// Both the claim and the placement are recorded in the execution's history
+	events := store.eventsFor(pending.ID)
+	require.Len(t, events, 2)
+	assert.Equal(t, database.ExecutionStatusPending, events[0].FromStatus.ExecutionStatus)
+	assert.Equal(t, database.ExecutionStatusEvaluating, events[0].ToStatus)
+	assert.Equal(t, database.ExecutionActorScheduler, events[0].Actor)
+	assert.Equal(t, "claimed by scheduler test-worker", events[0].Reason)
+	assert.Contains(t, events[1].Reason, "placed in westeurope")
+}
+
+func TestScheduleExecution_RejectsIllegalTransition(t *testing.T) {
+	finished := database.Execution{ID: uuid.New(), Status: database.ExecutionStatusCompletedSuccess}
+	store := newMockStore(finished)
+
+	s := newTestScheduler(store)
+	err := s.scheduleExecution(context.Background(), finished)
+	assert.ErrorIs(t, err, database.ErrIllegalTransition)
+
+	assert.Nil(t, store.executions[finished.ID].CloudRegion)
+	assert.Empty(t, store.events)
+}
+
+func TestProcessPending_ContinuesAfterFailure(t *testing.T) {
+	first := database.Execution{ID: uuid.New(), Status: database.ExecutionStatusPending}
+	second := database.Execution{ID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(first, second)
+	store.failOn = first.ID
+
+	s := newTestScheduler(store)
+	scheduled, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, scheduled)
+	assert.NotNil(t, store.executions[second.ID].CloudRegion)
+
+	// The failed execution keeps its lease so it returns to pending once the lease expires
+	assert.NotNil(t, store.executions[first.ID].LeaseOwner)
+}
+
+func TestRun_RequiresRegions(t *testing.T) {
+	s := NewScheduler(newMockStore(), nil, nil, Config{PollInterval: time.Second})
+	err := s.Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestProcessPending_RecordsCarbonIntensity(t *testing.T) {
+	pending := database.Execution{ID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(pending)
+
+	provider, err := carbon.LoadCSV(strings.NewReader("region,timestamp,g_co2_per_kwh\nwesteurope,2024-12-31T00:00:00Z,275.5\n"))
+	require.NoError(t, err)
+
+	s := newTestScheduler(store)
+	s.carbon = provider
+
+	_, err = s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	result := store.executions[pending.ID]
+	assert.Equal(t, 275.5, result.CarbonIntensityGKwh.Float64)
+	assert.False(t, result.CostEstimateUsd.Valid)
+}
+
+func TestProcessPending_DefersToGreenestSlot(t *testing.T) {
+	submitted := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending, CreatedAt: submitted}
+	store := newMockStore(pending)
+	store.jobs[pending.JobID] = database.GetJobSchedulingInfoRow{DelayToleranceHours: 4, CostWeight: 0, CarbonWeight: 1}
+
+	provider, err := carbon.LoadCSV(strings.NewReader(`region,timestamp,g_co2_per_kwh
+westeurope,2025-01-01T12:00:00Z,300
+westeurope,2025-01-01T14:00:00Z,120
+westeurope,2025-01-01T18:00:00Z,10
+northeurope,2025-01-01T12:00:00Z,200
+`))
+	require.NoError(t, err)
+
+	s := newTestScheduler(store)
+	s.carbon = provider
+
+	_, err = s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	// The 18:00 reading is outside the four hour tolerance window
+	result := store.executions[pending.ID]
+	assert.Equal(t, "westeurope", *result.CloudRegion)
+	assert.Equal(t, time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC), result.PlannedStartAt.Time)
+	assert.Equal(t, 120.0, result.CarbonIntensityGKwh.Float64)
+
+	// Savings are measured against starting on submission in the default region
+	assert.Equal(t, "westeurope", *result.BaselineRegion)
+	assert.Equal(t, "Standard_D2s_v5", *result.BaselineVmType)
+	assert.Equal(t, submitted, result.BaselineStartAt.Time)
+
+	// The history explains the wait
+	events := store.eventsFor(pending.ID)
+	require.Len(t, events, 2)
+	assert.Equal(t, "placed in westeurope to start at 2025-01-01T14:00:00Z, deferred 2h0m0s: "+
+		"best score (cost weight 0.00, carbon weight 1.00) among 3 slot(s) within 4h delay tolerance, forecast 120 gCO2/kWh", events[1].Reason)
+}
+
+// rawForecast returns its readings whatever range is asked for
+type rawForecast []carbon.Intensity
+
+func (f rawForecast) Current(ctx context.Context, region string) (carbon.Intensity, error) {
+	return f[len(f)-1], nil
+}
+
+func (f rawForecast) Forecast(ctx context.Context, region string, from, to time.Time) ([]carbon.Intensity, error) {
+	return f, nil
+}
+
+func TestCandidateSlots_StayWithinWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2025, 1, 1, hour, minute, 0, 0, time.UTC) }
+
+	s := newTestScheduler(newMockStore())
+	s.carbon = rawForecast{
+		{Timestamp: at(10, 0), GramsPerKWh: 5},
+		{Timestamp: at(11, 30), GramsPerKWh: 250},
+		{Timestamp: at(13, 0), GramsPerKWh: 120},
+		{Timestamp: at(16, 0), GramsPerKWh: 90},
+		{Timestamp: at(17, 0), GramsPerKWh: 1},
+	}
+
+	window := Window{Start: at(12, 0), End: at(16, 0)}
+	slots := s.candidateSlots(context.Background(), database.Execution{ID: uuid.New()}, window, []machine{{Region: "westeurope", VMType: "Standard_D2s_v5"}})
+
+	// A stale reading from before the one in effect and one past the window are left out
+	require.Len(t, slots, 3)
+	assert.Equal(t, at(12, 0), slots[0].StartAt)
+	assert.Equal(t, 250.0, slots[0].GramsPerKWh)
+	assert.Equal(t, at(13, 0), slots[1].StartAt)
+	assert.Equal(t, at(16, 0), slots[2].StartAt)
+}
+
+// testEstimator prices two VM types in two regions; northeurope is cheaper
+func testEstimator(t *testing.T) *pricing.Estimator {
+	catalogue, err := pricing.LoadJSON(strings.NewReader(`{"version": "test",
+		"vm_types": [
+			{"name": "Standard_D2s_v5", "vcpus": 2, "memory_gb": 8, "disk_gb": 75},
+			{"name": "Standard_D8s_v5", "vcpus": 8, "memory_gb": 32, "disk_gb": 300}
+		],
+		"prices": [
+			{"region": "westeurope", "vm_type": "Standard_D2s_v5", "on_demand": 0.096},
+			{"region": "westeurope", "vm_type": "Standard_D8s_v5", "on_demand": 0.384},
+			{"region": "northeurope", "vm_type": "Standard_D2s_v5", "on_demand": 0.088}
+		]}`))
+	require.NoError(t, err)
+
+	estimator, err := pricing.NewEstimator(catalogue, pricing.OnDemand)
+	require.NoError(t, err)
+	return estimator
+}
+
+func TestProcessPending_PrefersCheapestRegionWithoutForecast(t *testing.T) {
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(pending)
+
+	s := newTestScheduler(store)
+	s.prices = testEstimator(t)
+
+	_, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	result := store.executions[pending.ID]
+	assert.Equal(t, "northeurope", *result.CloudRegion)
+	assert.False(t, result.CarbonIntensityGKwh.Valid)
+
+	// The default expected runtime is priced in the chosen region
+	assert.Equal(t, 0.176, result.CostEstimateUsd.Float64)
+
+	// The baseline stays in the default region
+	assert.Equal(t, "westeurope", *result.BaselineRegion)
+}
+
+func TestProcessPending_NoBaselineWhenDefaultRegionCannotRun(t *testing.T) {
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(pending)
+
+	s := newTestScheduler(store)
+	s.config.Regions = []string{"swedencentral", "northeurope"}
+	s.prices = testEstimator(t)
+
+	_, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	// swedencentral is not priced, so there is nothing to compare against
+	result := store.executions[pending.ID]
+	assert.Equal(t, "northeurope", *result.CloudRegion)
+	assert.Nil(t, result.BaselineRegion)
+	assert.False(t, result.BaselineStartAt.Valid)
+}
+
+func TestProcessPending_SelectsVMTypeForRequirements(t *testing.T) {
+	expected := int32(30)
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(pending)
+	store.jobs[pending.JobID] = database.GetJobSchedulingInfoRow{
+		CpuCores:               4,
+		MemoryMb:               16384,
+		DiskGb:                 100,
+		ExpectedRuntimeMinutes: &expected,
+		CostWeight:             1,
+	}
+
+	s := newTestScheduler(store)
+	s.prices = testEstimator(t)
+
+	_, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	// Only westeurope offers a VM type large enough
+	result := store.executions[pending.ID]
+	assert.Equal(t, "westeurope", *result.CloudRegion)
+	assert.Equal(t, "Standard_D8s_v5", *result.VmType)
+	assert.Equal(t, 0.192, result.CostEstimateUsd.Float64)
+
+	events := store.eventsFor(pending.ID)
+	require.Len(t, events, 2)
+	assert.Contains(t, events[1].Reason, "Standard_D8s_v5 as the cheapest VM type with 4 vCPU, 16384 MiB memory, 100 GiB disk")
+}
+
+func TestProcessPending_FailsUnschedulableExecutions(t *testing.T) {
+	pending := database.Execution{ID: uuid.New(), JobID: uuid.New(), Status: database.ExecutionStatusPending}
+	store := newMockStore(pending)
+	store.jobs[pending.JobID] = database.GetJobSchedulingInfoRow{CpuCores: 64, MemoryMb: 2048, DiskGb: 10}
+
+	s := newTestScheduler(store)
+	s.prices = testEstimator(t)
+
+	scheduled, err := s.ProcessPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, scheduled)
+
+	// Failed rather than left to be claimed again
+	result := store.executions[pending.ID]
+	assert.Equal(t, database.ExecutionStatusCompletedError, result.Status)
+	assert.Nil(t, result.LeaseOwner)
+
+	events := store.eventsFor(pending.ID)
+	require.Len(t, events, 2)
+	assert.Equal(t, database.ExecutionStatusCompletedError, events[1].ToStatus)
+	assert.Contains(t, events[1].Reason, "no candidate region offers a VM type with 64 vCPU")
+}
+
+func TestProcessPending_HonoursRunDelayTolerance(t *testing.T) {
+	submitted := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	noDelay := int32(0)
+	pending := database.Execution{
+		ID:                  uuid.New(),
+		JobID:               uuid.New(),
+		Status:              database.ExecutionStatusPending,
+		CreatedAt:           submitted,
+		DelayToleranceHours: &noDelay,
+	}
+	store := newMockStore(pending)
+	store.jobs[pending.JobID] = database.GetJobSchedulingInfoRow{DelayToleranceHours: 24, CostWeight: 0, CarbonWeight: 1}
+
+	provider, err := carbon.LoadCSV(strings.NewReader(`region,timestamp,g_co2_per_kwh
+westeurope,2025-01-01T12:00:00Z,300
+westeurope,2025-01-01T14:00:00Z,120
+`))
+	require.NoError(t, err)
+
+	s := newTestScheduler(store)
+	s.carbon = provider
+
+	_, err = s.ProcessPending(context.Background())
+	require.NoError(t, err)
+
+	// The run asked not to be delayed, so the cleaner 14:00 slot is ignored
+	result := store.executions[pending.ID]
+	assert.Equal(t, submitted, result.PlannedStartAt.Time)
+	assert.Equal(t, 300.0, result.CarbonIntensityGKwh.Float64)
+}
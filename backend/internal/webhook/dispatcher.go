@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// Store is the subset of database.Querier used by the dispatcher
+type Store interface {
+	ClaimDueWebhookDeliveries(ctx context.Context, arg database.ClaimDueWebhookDeliveriesParams) ([]database.WebhookDelivery, error)
+	GetWebhookSubscriptionByID(ctx context.Context, id uuid.UUID) (database.WebhookSubscription, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg database.CreateWebhookDeliveryAttemptParams) error
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg database.UpdateWebhookDeliveryAttemptParams) error
+}
+
+// Config holds dispatcher configuration
+type Config struct {
+	PollInterval  time.Duration // How often due deliveries are polled
+	BatchSize     int32         // Maximum deliveries attempted per poll
+	LeaseDuration time.Duration // How long a claimed delivery is hidden from other dispatchers; at least twice Timeout
+	Timeout       time.Duration // Per-request timeout
+	MaxAttempts   int32         // Attempts after which a delivery is marked failed
+	BaseBackoff   time.Duration // Delay before the first retry, doubled after each failure
+	MaxBackoff    time.Duration // Upper bound on the delay between retries
+}
+
+// Dispatcher sends due webhook deliveries, retrying failures with exponential
+// backoff. Dispatchers in several processes may share one outbox.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	config Config
+	now    func() time.Time
+}
+
+// NewDispatcher creates a new dispatcher
+func NewDispatcher(store Store, config Config) *Dispatcher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.LeaseDuration < 2*config.Timeout {
+		config.LeaseDuration = 2 * config.Timeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 30 * time.Second
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+
+	return &Dispatcher{
+		store:  store,
+		client: newClient(config.Timeout, AllowedAddress),
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run delivers due events on every poll until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := d.ProcessDue(ctx)
+		if err != nil {
+			log.Printf("webhook: failed to process due deliveries: %v", err)
+		} else if sent > 0 {
+			log.Printf("webhook: attempted %d delivery(ies)", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue attempts up to BatchSize due deliveries and returns how many were
+// attempted. Each delivery is claimed just before it is sent, so its lease only
+// has to cover one attempt however long the batch takes.
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	subscriptions := make(map[uuid.UUID]database.WebhookSubscription)
+	attempted := 0
+	for attempted < int(d.config.BatchSize) && ctx.Err() == nil {
+		claimed, err := d.store.ClaimDueWebhookDeliveries(ctx, database.ClaimDueWebhookDeliveriesParams{
+			LeaseSeconds: int32(d.config.LeaseDuration / time.Second),
+			BatchSize:    1,
+		})
+		if err != nil {
+			return attempted, err
+		}
+		if len(claimed) == 0 {
+			break
+		}
+		delivery := claimed[0]
+		attempted++
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.store.GetWebhookSubscriptionByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				// The lease expires and the delivery is retried later
+				log.Printf("webhook: delivery %s: failed to load subscription: %v", delivery.ID, err)
+				continue
+			}
+			subscriptions[subscription.ID] = subscription
+		}
+
+		if err := d.Deliver(ctx, delivery, subscription); err != nil {
+			log.Printf("webhook: delivery %s: %v", delivery.ID, err)
+		}
+	}
+
+	return attempted, nil
+}
+
+// Deliver makes one attempt at a delivery and records its outcome. Failed
+// attempts are rescheduled with backoff until MaxAttempts is reached.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery database.WebhookDelivery, subscription database.WebhookSubscription) error {
+	started := d.now()
+	statusCode, attemptErr := d.send(ctx, delivery, subscription)
+	duration := d.now().Sub(started)
+
+	attempt := database.CreateWebhookDeliveryAttemptParams{
+		DeliveryID: delivery.ID,
+		DurationMs: int32(duration / time.Millisecond),
+	}
+	if statusCode != 0 {
+		code := int32(statusCode)
+		attempt.StatusCode = &code
+	}
+	if attemptErr != nil {
+		message := attemptErr.Error()
+		attempt.Error = &message
+	}
+	// History is informational; the delivery's state below is what matters
+	if err := d.store.CreateWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		log.Printf("webhook: delivery %s: failed to record attempt: %v", delivery.ID, err)
+	}
+
+	now := d.now()
+	update := database.UpdateWebhookDeliveryAttemptParams{
+		ID:            delivery.ID,
+		Status:        database.WebhookDeliveryStatusDelivered,
+		NextAttemptAt: now,
+	}
+	switch {
+	case attemptErr == nil:
+		update.DeliveredAt = null.TimeFrom(now)
+	case delivery.Attempts+1 >= d.config.MaxAttempts:
+		update.Status = database.WebhookDeliveryStatusFailed
+		update.LastError = attempt.Error
+	default:
+		update.Status = database.WebhookDeliveryStatusPending
+		update.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		update.LastError = attempt.Error
+	}
+
+	if err := d.store.UpdateWebhookDeliveryAttempt(ctx, update); err != nil {
+		return fmt.Errorf("failed to record delivery outcome: %w", err)
+	}
+	if attemptErr != nil {
+		return fmt.Errorf("attempt %d of %d failed: %w", delivery.Attempts+1, d.config.MaxAttempts, attemptErr)
+	}
+	return nil
+}
+
+// send POSTs the signed payload and returns the response status, 0 if there was
+// no response. Any non-2xx status is an error.
+func (d *Dispatcher) send(ctx context.Context, delivery database.WebhookDelivery, subscription database.WebhookSubscription) (int, error) {
+	if !subscription.IsActive {
+		return 0, errors.New("subscription is disabled")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Veridian-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The response body is discarded unread: it is shown to the subscription
+	// owner, and must not become a way to read other services' responses
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next retry after the given number of failures
+func (d *Dispatcher) backoff(attempts int32) time.Duration {
+	delay := d.config.BaseBackoff
+	for i := int32(0); i < attempts; i++ {
+		delay *= 2
+		if delay >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return delay
+}
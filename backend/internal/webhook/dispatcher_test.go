@@ -0,0 +1,355 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nouvadev/veridian/backend/internal/database"
+)
+
+// mockStore is an in-memory implementation of Store that leases claimed
+// deliveries like the database does
+type mockStore struct {
+	mu            sync.Mutex
+	now           func() time.Time
+	deliveries    []database.WebhookDelivery
+	subscriptions map[uuid.UUID]database.WebhookSubscription
+	attempts      []database.CreateWebhookDeliveryAttemptParams
+	updates       []database.UpdateWebhookDeliveryAttemptParams
+}
+
+func (m *mockStore) ClaimDueWebhookDeliveries(ctx context.Context, arg database.ClaimDueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := testNow
+	if m.now != nil {
+		now = m.now()
+	}
+	var claimed []database.WebhookDelivery
+	for i := range m.deliveries {
+		delivery := &m.deliveries[i]
+		if len(claimed) == int(arg.BatchSize) || delivery.Status != database.WebhookDeliveryStatusPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = now.Add(time.Duration(arg.LeaseSeconds) * time.Second)
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (m *mockStore) GetWebhookSubscriptionByID(ctx context.Context, id uuid.UUID) (database.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.subscriptions[id], nil
+}
+
+func (m *mockStore) CreateWebhookDeliveryAttempt(ctx context.Context, arg database.CreateWebhookDeliveryAttemptParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, arg)
+	return nil
+}
+
+func (m *mockStore) UpdateWebhookDeliveryAttempt(ctx context.Context, arg database.UpdateWebhookDeliveryAttemptParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates = append(m.updates, arg)
+	for i := range m.deliveries {
+		if m.deliveries[i].ID == arg.ID {
+			m.deliveries[i].Status = arg.Status
+			m.deliveries[i].NextAttemptAt = arg.NextAttemptAt
+			m.deliveries[i].Attempts++
+		}
+	}
+	return nil
+}
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestDispatcher(store Store) *Dispatcher {
+	d := NewDispatcher(store, Config{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+	})
+	d.now = func() time.Time { return testNow }
+	// Test servers listen on loopback, which deliveries normally may not reach
+	d.client = newClient(d.config.Timeout, func(netip.Addr) bool { return true })
+	return d
+}
+
+func seed(url string, attempts int32) (*mockStore, database.WebhookDelivery) {
+	subscription := database.WebhookSubscription{ID: uuid.New(), Url: url, Secret: "s3cret", IsActive: true}
+	delivery := database.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscription.ID,
+		Event:          string(EventCompletedSuccess),
+		Payload:        []byte(`{"event":"completed_success"}`),
+		Status:         database.WebhookDeliveryStatusPending,
+		Attempts:       attempts,
+		NextAttemptAt:  testNow,
+	}
+	store := &mockStore{
+		deliveries:    []database.WebhookDelivery{delivery},
+		subscriptions: map[uuid.UUID]database.WebhookSubscription{subscription.ID: subscription},
+	}
+	return store, delivery
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store, delivery := seed(server.URL, 0)
+	sent, err := newTestDispatcher(store).ProcessDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	require.NotNil(t, received)
+	assert.Equal(t, delivery.Payload, body)
+	assert.Equal(t, "completed_success", received.Header.Get(HeaderEvent))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderDelivery))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign("s3cret", timestamp, body), received.Header.Get(HeaderSignature))
+
+	require.Len(t, store.attempts, 1)
+	require.NotNil(t, store.attempts[0].StatusCode)
+	assert.Equal(t, int32(http.StatusNoContent), *store.attempts[0].StatusCode)
+	assert.Nil(t, store.attempts[0].Error)
+
+	require.Len(t, store.updates, 1)
+	assert.Equal(t, database.WebhookDeliveryStatusDelivered, store.updates[0].Status)
+	assert.True(t, store.updates[0].DeliveredAt.Valid)
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		attempts int32
+		status   database.WebhookDeliveryStatus
+		next     time.Time
+	}{
+		{"first failure", 0, database.WebhookDeliveryStatusPending, testNow.Add(time.Minute)},
+		{"second failure", 1, database.WebhookDeliveryStatusPending, testNow.Add(2 * time.Minute)},
+		{"last attempt", 2, database.WebhookDeliveryStatusFailed, testNow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, _ := seed(server.URL, tt.attempts)
+			_, err := newTestDispatcher(store).ProcessDue(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, store.attempts, 1)
+			assert.Equal(t, int32(http.StatusServiceUnavailable), *store.attempts[0].StatusCode)
+			// The response body is not kept
+			assert.Equal(t, "endpoint responded 503", *store.attempts[0].Error)
+
+			require.Len(t, store.updates, 1)
+			assert.Equal(t, tt.status, store.updates[0].Status)
+			assert.Equal(t, tt.next, store.updates[0].NextAttemptAt)
+			assert.NotNil(t, store.updates[0].LastError)
+			assert.False(t, store.updates[0].DeliveredAt.Valid)
+		})
+	}
+}
+
+func TestDispatcher_RecordsConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	store, _ := seed(server.URL, 0)
+	_, err := newTestDispatcher(store).ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, store.attempts, 1)
+	assert.Nil(t, store.attempts[0].StatusCode)
+	assert.NotNil(t, store.attempts[0].Error)
+	assert.Equal(t, database.WebhookDeliveryStatusPending, store.updates[0].Status)
+}
+
+func TestDispatcher_LeasesCoverTheWholeBatch(t *testing.T) {
+	var mu sync.Mutex
+	now := testNow
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	store, _ := seed("", 0)
+	store.now = clock
+	subscription := database.WebhookSubscription{ID: uuid.New(), Secret: "s3cret", IsActive: true}
+	store.subscriptions[subscription.ID] = subscription
+	store.deliveries = nil
+	for i := 0; i < 4; i++ {
+		store.deliveries = append(store.deliveries, database.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: subscription.ID,
+			Event:          string(EventStarted),
+			Payload:        []byte(`{}`),
+			Status:         database.WebhookDeliveryStatusPending,
+			NextAttemptAt:  testNow,
+		})
+	}
+
+	first, second := newTestDispatcher(store), newTestDispatcher(store)
+	first.now, second.now = clock, clock
+
+	// Each of the first dispatcher's requests takes as long as the timeout
+	// allows. While it is on its third delivery, past the lease it would have
+	// taken on a batch claimed up front, a second dispatcher polls.
+	received := make(map[string]int)
+	polling, polled := false, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.Header.Get(HeaderDelivery)]++
+		poll := !polled && len(received) == 3
+		if !polling {
+			now = now.Add(first.config.Timeout)
+		}
+		polling, polled = poll || polling, poll || polled
+		mu.Unlock()
+
+		if poll {
+			_, err := second.ProcessDue(context.Background())
+			assert.NoError(t, err)
+			mu.Lock()
+			polling = false
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	subscription.Url = server.URL
+	store.subscriptions[subscription.ID] = subscription
+
+	_, err := first.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, polled)
+	require.Len(t, received, 4)
+	for id, count := range received {
+		assert.Equal(t, 1, count, "delivery %s was sent %d times", id, count)
+	}
+	assert.Len(t, store.updates, 4)
+}
+
+func TestDispatcher_RefusesNonPublicDestinations(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	store, _ := seed(server.URL, 0)
+	d := newTestDispatcher(store)
+	d.client = newClient(d.config.Timeout, AllowedAddress)
+
+	_, err := d.ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, requested)
+	require.Len(t, store.attempts, 1)
+	assert.Nil(t, store.attempts[0].StatusCode)
+	assert.Contains(t, *store.attempts[0].Error, "is not allowed")
+}
+
+func TestDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	store, _ := seed(server.URL, 0)
+	_, err := newTestDispatcher(store).ProcessDue(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, followed)
+	require.Len(t, store.attempts, 1)
+	assert.Equal(t, int32(http.StatusTemporaryRedirect), *store.attempts[0].StatusCode)
+	assert.Equal(t, database.WebhookDeliveryStatusPending, store.updates[0].Status)
+}
+
+func TestAllowedAddress(t *testing.T) {
+	tests := []struct {
+		addr    string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a01:203", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, AllowedAddress(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestAllowedHost(t *testing.T) {
+	assert.True(t, AllowedHost("example.com"))
+	assert.True(t, AllowedHost("93.184.216.34"))
+	assert.False(t, AllowedHost("localhost"))
+	assert.False(t, AllowedHost("api.LOCALHOST."))
+	assert.False(t, AllowedHost("169.254.169.254"))
+	assert.False(t, AllowedHost("[::1]"))
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1700000000, []byte(`{}`))
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.NotEqual(t, signature, Sign("secret", 1700000001, []byte(`{}`)))
+	assert.NotEqual(t, signature, Sign("other", 1700000000, []byte(`{}`)))
+}
+
+func TestEventValid(t *testing.T) {
+	assert.True(t, EventOrphaned.Valid())
+	assert.False(t, Event("evaluating").Valid())
+}
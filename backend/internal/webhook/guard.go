@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// nonPublicPrefixes are special-purpose ranges not covered by the netip.Addr
+// predicates used in AllowedAddress
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which can reach private IPv4
+}
+
+// AllowedAddress reports whether deliveries may be sent to addr. Loopback,
+// private, link-local, unspecified, multicast and other non-public addresses are
+// refused so subscriptions cannot reach the server's own network.
+func AllowedAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowedHost reports whether a URL host is acceptable before it is resolved:
+// IP literals must be allowed addresses and localhost names are refused.
+// Hostnames are checked again, after resolution, on every connection.
+func AllowedHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return AllowedAddress(addr)
+	}
+	return true
+}
+
+// newClient returns an HTTP client that only connects to addresses accepted by
+// allow. Redirects are not followed, so a public endpoint cannot point deliveries
+// elsewhere; the redirect response fails the attempt like any non-2xx status.
+// The check runs on the resolved address of each connection, so DNS rebinding
+// cannot bypass it.
+func newClient(timeout time.Duration, allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("invalid destination %q: %w", address, err)
+			}
+			if !allow(addrPort.Addr()) {
+				return fmt.Errorf("destination %s is not allowed", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil, // A proxy would connect on our behalf, unchecked
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,68 @@
+// Package webhook delivers execution lifecycle events to user endpoints. Events
+// are enqueued in the webhook_deliveries outbox by a database trigger, in the same
+// transaction as the execution change, and sent by a Dispatcher.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Event is an execution lifecycle event a subscription can receive
+type Event string
+
+const (
+	EventScheduled        Event = "scheduled"         // The scheduler chose a region, VM type and start time
+	EventStarted          Event = "started"           // The workload started running
+	EventCompletedSuccess Event = "completed_success" // The workload exited with code 0
+	EventCompletedError   Event = "completed_error"   // The workload failed or could not be run
+	EventOrphaned         Event = "orphaned"          // The execution's compute could not be torn down
+)
+
+// AllEvents lists every event in lifecycle order
+func AllEvents() []Event {
+	return []Event{EventScheduled, EventStarted, EventCompletedSuccess, EventCompletedError, EventOrphaned}
+}
+
+// Valid reports whether e is a known event
+func (e Event) Valid() bool {
+	for _, known := range AllEvents() {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Veridian-Event"
+	HeaderDelivery  = "X-Veridian-Delivery"
+	HeaderTimestamp = "X-Veridian-Timestamp"
+	HeaderSignature = "X-Veridian-Signature"
+)
+
+// Sign returns the signature header value for a payload sent at timestamp (Unix
+// seconds): "sha256=" followed by the hex HMAC-SHA256, keyed with the
+// subscription secret, of the timestamp, a '.', and the body. Receivers should
+// recompute it and reject stale timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("webhook: failed to generate secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,40 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    user_id,
+    name,
+    prefix,
+    key_hash,
+    scopes,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- Finds the key a request authenticates with. Revoked and expired keys, and
+-- keys of deactivated users, do not authenticate.
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys 
+WHERE key_hash = $1 
+  AND revoked_at IS NULL 
+  AND (expires_at IS NULL OR expires_at > now())
+  AND user_id IN (SELECT id FROM users WHERE is_active = TRUE);
+
+-- name: ListUserAPIKeys :many
+SELECT * FROM api_keys 
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys 
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- Records use of a key at most once a minute, so busy clients do not cause a
+-- write per request.
+-- name: UpdateAPIKeyLastUsed :exec
+UPDATE api_keys 
+SET 
+    last_used_at = now(),
+    last_used_ip = $2
+WHERE id = $1 
+  AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
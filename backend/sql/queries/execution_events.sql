@@ -0,0 +1,15 @@
+-- name: CreateExecutionEvent :exec
+INSERT INTO execution_events (
+    execution_id,
+    from_status,
+    to_status,
+    actor,
+    reason
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetExecutionEvents :many
+SELECT * FROM execution_events 
+WHERE execution_id = $1
+ORDER BY created_at ASC;
@@ -1,9 +1,11 @@
 -- name: CreateExecution :one
 INSERT INTO executions (
     job_id,
-    status
+    status,
+    env_vars,
+    delay_tolerance_hours
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetExecution :one
@@ -26,46 +28,145 @@ SELECT * FROM executions
 WHERE status = 'pending'
 ORDER BY created_at ASC;
 
+-- name: ClaimPendingExecutions :many
+-- Leases pending executions to lease_owner and records the transitions
+WITH claimed AS (
+    UPDATE executions 
+    SET 
+        status = 'evaluating',
+        lease_owner = sqlc.arg(lease_owner)::text,
+        lease_expires_at = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+    WHERE id IN (
+        SELECT id FROM executions 
+        WHERE status = 'pending'
+        ORDER BY created_at ASC
+        LIMIT sqlc.arg(batch_size)
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING *
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'pending', 'evaluating', sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM claimed
+)
+SELECT * FROM claimed;
+
+-- name: LeaseDueExecutions :many
+UPDATE executions 
+SET 
+    lease_owner = sqlc.arg(lease_owner)::text,
+    lease_expires_at = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+WHERE id IN (
+    SELECT id FROM executions 
+    WHERE status = 'evaluating'
+      AND lease_owner IS NULL
+      AND planned_start_at <= now()
+    ORDER BY planned_start_at ASC
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RenewExecutionLease :execrows
+-- Extends the lease lease_owner holds on an execution it is starting or running.
+-- An expired lease on an evaluating execution may already have been released.
+UPDATE executions 
+SET lease_expires_at = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+WHERE id = sqlc.arg(id)
+  AND lease_owner = sqlc.arg(lease_owner)::text
+  AND (status = 'running' OR (status = 'evaluating' AND lease_expires_at > now()));
+
+-- name: ReleaseExpiredLeases :execrows
+-- Returns executions whose lease expired to pending and records the transitions
+WITH released AS (
+    UPDATE executions 
+    SET 
+        status = 'pending',
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE status = 'evaluating'
+      AND lease_owner IS NOT NULL
+      AND lease_expires_at < now()
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, 'evaluating', 'pending', sqlc.arg(actor)::execution_actor, 'lease expired before the worker finished' FROM released;
+
 -- name: GetExecutionsByStatus :many
 SELECT * FROM executions 
 WHERE status = $1
 ORDER BY created_at DESC;
 
--- name: UpdateExecutionStatus :one
-UPDATE executions 
-SET status = $2
-WHERE id = $1
-RETURNING *;
-
 -- name: UpdateExecutionScheduling :one
-UPDATE executions 
-SET 
-    status = $2,
-    chosen_at = $3,
-    cloud_region = $4,
-    vm_type = $5
-WHERE id = $1
-RETURNING *;
+-- Records the placement of an evaluating execution, releases its lease and
+-- records the transition
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = sqlc.arg(status),
+        chosen_at = sqlc.arg(chosen_at),
+        cloud_region = sqlc.arg(cloud_region),
+        vm_type = sqlc.arg(vm_type),
+        planned_start_at = sqlc.arg(planned_start_at),
+        baseline_region = sqlc.arg(baseline_region),
+        baseline_vm_type = sqlc.arg(baseline_vm_type),
+        baseline_start_at = sqlc.arg(baseline_start_at),
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE id = sqlc.arg(id) AND status = 'evaluating'
+    RETURNING *
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'evaluating', status, sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM updated
+)
+SELECT * FROM updated;
 
 -- name: UpdateExecutionStart :one
-UPDATE executions 
-SET 
-    status = 'running',
-    started_at = $2
-WHERE id = $1
-RETURNING *;
+-- Marks an evaluating execution running and records the transition, provided
+-- worker_id still holds its lease. The worker keeps renewing the lease while the
+-- execution runs.
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = 'running',
+        started_at = sqlc.arg(started_at)
+    WHERE id = sqlc.arg(id)
+      AND status = 'evaluating'
+      AND lease_owner = sqlc.arg(worker_id)::text
+      AND lease_expires_at > now()
+    RETURNING *
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, 'evaluating', 'running', sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM updated
+)
+SELECT * FROM updated;
 
 -- name: UpdateExecutionComplete :one
-UPDATE executions 
-SET 
-    status = $2,
-    completed_at = $3,
-    exit_code = $4,
-    log_uri = $5,
-    cost_actual_usd = $6,
-    carbon_emitted_kg = $7
-WHERE id = $1
-RETURNING *;
+-- Records the outcome of an execution still in from_status and the transition
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = sqlc.arg(status),
+        completed_at = sqlc.arg(completed_at),
+        exit_code = sqlc.arg(exit_code),
+        log_uri = sqlc.arg(log_uri),
+        cost_actual_usd = sqlc.arg(cost_actual_usd),
+        carbon_emitted_kg = sqlc.arg(carbon_emitted_kg),
+        energy_kwh = sqlc.arg(energy_kwh),
+        baseline_cost_usd = sqlc.arg(baseline_cost_usd),
+        baseline_carbon_kg = sqlc.arg(baseline_carbon_kg),
+        lease_owner = NULL,
+        lease_expires_at = NULL
+    WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+    RETURNING *
+),
+recorded AS (
+    INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+    SELECT id, sqlc.arg(from_status), status, sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM updated
+)
+SELECT * FROM updated;
 
 -- name: UpdateExecutionCostEstimate :one
 UPDATE executions 
@@ -82,19 +183,63 @@ SELECT
     COUNT(*) FILTER (WHERE status = 'completed_error') as failed_executions,
     COUNT(*) FILTER (WHERE status = 'pending') as pending_executions,
     COUNT(*) FILTER (WHERE status = 'running') as running_executions,
-    AVG(cost_actual_usd) as avg_cost,
-    SUM(carbon_emitted_kg) as total_carbon
+    COALESCE(AVG(cost_actual_usd), 0)::float8 as avg_cost,
+    COALESCE(SUM(cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(carbon_emitted_kg), 0)::float8 as total_carbon
 FROM executions 
 WHERE job_id = $1;
 
+-- Executions submitted in [created_from, created_to). Savings only count executions
+-- with both a baseline and an actual figure; deferral is planned start minus submission.
 -- name: GetUserExecutionStats :one
 SELECT 
     COUNT(*) as total_executions,
-    COUNT(*) FILTER (WHERE status = 'completed_success') as successful_executions,
-    COUNT(*) FILTER (WHERE status = 'completed_error') as failed_executions,
-    AVG(cost_actual_usd) as avg_cost,
-    SUM(cost_actual_usd) as total_cost,
-    SUM(carbon_emitted_kg) as total_carbon
+    COUNT(*) FILTER (WHERE e.status = 'pending') as pending_executions,
+    COUNT(*) FILTER (WHERE e.status = 'evaluating') as evaluating_executions,
+    COUNT(*) FILTER (WHERE e.status = 'running') as running_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_success') as successful_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_error') as failed_executions,
+    COUNT(*) FILTER (WHERE e.status = 'orphaned') as orphaned_executions,
+    COALESCE(AVG(e.cost_actual_usd), 0)::float8 as avg_cost,
+    COALESCE(SUM(e.cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(e.carbon_emitted_kg), 0)::float8 as total_carbon,
+    COALESCE(SUM(e.baseline_cost_usd - e.cost_actual_usd), 0)::float8 as cost_savings,
+    COALESCE(SUM(e.baseline_carbon_kg - e.carbon_emitted_kg), 0)::float8 as carbon_savings,
+    COALESCE(AVG(EXTRACT(EPOCH FROM (e.planned_start_at - COALESCE(e.scheduled_for, e.created_at)))), 0)::float8 as avg_deferral_seconds
+FROM executions e
+JOIN jobs j ON e.job_id = j.id
+WHERE j.owner_id = sqlc.arg(owner_id)
+  AND e.created_at >= sqlc.arg(created_from)
+  AND e.created_at < sqlc.arg(created_to);
+
+-- GetUserExecutionStats per UTC day, week or month of submission, for trend charts
+-- name: GetUserExecutionStatsByBucket :many
+SELECT 
+    date_trunc(sqlc.arg(bucket)::text, e.created_at, 'UTC')::timestamptz as bucket_start,
+    COUNT(*) as total_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_success') as successful_executions,
+    COUNT(*) FILTER (WHERE e.status = 'completed_error') as failed_executions,
+    COALESCE(SUM(e.cost_actual_usd), 0)::float8 as total_cost,
+    COALESCE(SUM(e.carbon_emitted_kg), 0)::float8 as total_carbon,
+    COALESCE(SUM(e.baseline_cost_usd - e.cost_actual_usd), 0)::float8 as cost_savings,
+    COALESCE(SUM(e.baseline_carbon_kg - e.carbon_emitted_kg), 0)::float8 as carbon_savings,
+    COALESCE(AVG(EXTRACT(EPOCH FROM (e.planned_start_at - COALESCE(e.scheduled_for, e.created_at)))), 0)::float8 as avg_deferral_seconds
+FROM executions e
+JOIN jobs j ON e.job_id = j.id
+WHERE j.owner_id = sqlc.arg(owner_id)
+  AND e.created_at >= sqlc.arg(created_from)
+  AND e.created_at < sqlc.arg(created_to)
+GROUP BY bucket_start
+ORDER BY bucket_start;
+
+-- Totals compare only executions with both a baseline and an actual figure
+-- name: GetUserSavings :one
+SELECT 
+    COUNT(*) FILTER (WHERE e.baseline_cost_usd IS NOT NULL OR e.baseline_carbon_kg IS NOT NULL) as executions,
+    COALESCE(SUM(e.baseline_cost_usd) FILTER (WHERE e.cost_actual_usd IS NOT NULL), 0)::float8 as baseline_cost,
+    COALESCE(SUM(e.cost_actual_usd) FILTER (WHERE e.baseline_cost_usd IS NOT NULL), 0)::float8 as actual_cost,
+    COALESCE(SUM(e.baseline_carbon_kg) FILTER (WHERE e.carbon_emitted_kg IS NOT NULL), 0)::float8 as baseline_carbon,
+    COALESCE(SUM(e.carbon_emitted_kg) FILTER (WHERE e.baseline_carbon_kg IS NOT NULL), 0)::float8 as actual_carbon
 FROM executions e
 JOIN jobs j ON e.job_id = j.id
 WHERE j.owner_id = $1;
@@ -102,3 +247,73 @@ WHERE j.owner_id = $1;
 -- name: DeleteExecution :exec
 DELETE FROM executions 
 WHERE id = $1;
+
+-- name: UpdateExecutionInstance :exec
+UPDATE executions 
+SET instance_id = $2
+WHERE id = $1;
+
+-- name: MarkExecutionOrphaned :execrows
+-- Marks an execution still in from_status orphaned and records the transition
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = 'orphaned',
+        instance_id = sqlc.arg(instance_id),
+        lease_owner = NULL,
+        lease_expires_at = NULL,
+        next_teardown_at = sqlc.arg(next_teardown_at),
+        last_teardown_error = sqlc.arg(last_teardown_error)
+    WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, sqlc.arg(from_status), 'orphaned', sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM updated;
+
+-- name: GetOrphanedExecutionsDue :many
+SELECT * FROM executions 
+WHERE status = 'orphaned'
+    AND (next_teardown_at IS NULL OR next_teardown_at <= sqlc.arg(due_before))
+ORDER BY next_teardown_at ASC NULLS FIRST
+LIMIT sqlc.arg(batch_size);
+
+-- name: GetAbandonedRunningExecutions :many
+-- Lists running executions whose worker stopped renewing its lease before cutoff.
+-- Executions started without a lease count once their job's max runtime had
+-- elapsed by cutoff.
+SELECT e.* FROM executions e
+JOIN jobs j ON j.id = e.job_id
+WHERE e.status = 'running'
+    AND (
+        e.lease_expires_at < sqlc.arg(cutoff)
+        OR (
+            e.lease_expires_at IS NULL
+            AND j.max_runtime_minutes IS NOT NULL
+            AND e.started_at + make_interval(mins => j.max_runtime_minutes) < sqlc.arg(cutoff)
+        )
+    )
+ORDER BY e.started_at ASC
+LIMIT sqlc.arg(batch_size);
+
+-- name: RecordTeardownFailure :exec
+UPDATE executions 
+SET 
+    teardown_attempts = teardown_attempts + 1,
+    next_teardown_at = $2,
+    last_teardown_error = $3
+WHERE id = $1;
+
+-- name: ResolveOrphanedExecution :execrows
+-- Restores the outcome recorded before teardown failed and records the transition
+WITH updated AS (
+    UPDATE executions 
+    SET 
+        status = CASE WHEN exit_code = 0 THEN 'completed_success'::execution_status ELSE 'completed_error'::execution_status END,
+        completed_at = COALESCE(completed_at, now()),
+        next_teardown_at = NULL,
+        last_teardown_error = NULL
+    WHERE id = sqlc.arg(id) AND status = 'orphaned'
+    RETURNING id, status
+)
+INSERT INTO execution_events (execution_id, from_status, to_status, actor, reason)
+SELECT id, 'orphaned', status, sqlc.arg(actor)::execution_actor, sqlc.arg(reason)::text FROM updated;
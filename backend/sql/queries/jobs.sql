@@ -3,15 +3,27 @@ INSERT INTO jobs (
     owner_id,
     image_uri,
     env_vars,
-    delay_tolerance_hours
+    delay_tolerance_hours,
+    cron_schedule,
+    timezone,
+    next_run_at,
+    cpu_cores,
+    memory_mb,
+    disk_gb,
+    expected_runtime_minutes,
+    max_runtime_minutes
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetJob :one
 SELECT * FROM jobs 
 WHERE id = $1 AND owner_id = $2;
 
+-- name: GetJobByID :one
+SELECT * FROM jobs 
+WHERE id = $1;
+
 -- name: GetJobsByOwner :many
 SELECT * FROM jobs 
 WHERE owner_id = $1
@@ -24,13 +36,23 @@ ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: UpdateJob :one
+-- Updates a job definition. next_run_at is only replaced when reschedule is set,
+-- so an edit that keeps the schedule neither skips nor shifts its next occurrence.
 UPDATE jobs 
 SET 
-    image_uri = $3,
-    env_vars = $4,
-    delay_tolerance_hours = $5,
+    image_uri = sqlc.arg(image_uri),
+    env_vars = sqlc.arg(env_vars),
+    delay_tolerance_hours = sqlc.arg(delay_tolerance_hours),
+    cron_schedule = sqlc.arg(cron_schedule),
+    timezone = sqlc.arg(timezone),
+    next_run_at = CASE WHEN sqlc.arg(reschedule)::boolean THEN sqlc.arg(next_run_at) ELSE next_run_at END,
+    cpu_cores = sqlc.arg(cpu_cores),
+    memory_mb = sqlc.arg(memory_mb),
+    disk_gb = sqlc.arg(disk_gb),
+    expected_runtime_minutes = sqlc.arg(expected_runtime_minutes),
+    max_runtime_minutes = sqlc.arg(max_runtime_minutes),
     updated_at = now()
-WHERE id = $1 AND owner_id = $2
+WHERE id = sqlc.arg(id) AND owner_id = sqlc.arg(owner_id)
 RETURNING *;
 
 -- name: DeleteJob :exec
@@ -46,3 +68,43 @@ SELECT * FROM jobs
 WHERE owner_id = $1 
     AND created_at >= $2
 ORDER BY created_at DESC;
+
+-- name: GetJobSchedulingInfo :one
+SELECT 
+    j.owner_id,
+    j.delay_tolerance_hours,
+    j.cpu_cores,
+    j.memory_mb,
+    j.disk_gb,
+    j.expected_runtime_minutes,
+    COALESCE(s.cost_weight, 0.50)::float8 AS cost_weight,
+    COALESCE(s.carbon_weight, 0.50)::float8 AS carbon_weight
+FROM jobs j
+LEFT JOIN user_settings s ON s.user_id = j.owner_id
+WHERE j.id = $1;
+
+-- name: GetDueJobSchedules :many
+SELECT * FROM jobs 
+WHERE cron_schedule IS NOT NULL
+    AND next_run_at <= sqlc.arg(due_before)
+ORDER BY next_run_at ASC
+LIMIT sqlc.arg(batch_size);
+
+-- name: FireJobSchedule :execrows
+-- Advances the schedule, creates the occurrence's execution and records its creation
+-- in one statement.
+-- Affects no rows if another scheduler replica already fired this occurrence.
+WITH advanced AS (
+    UPDATE jobs 
+    SET next_run_at = sqlc.arg(next_run_at)
+    WHERE id = sqlc.arg(job_id) AND next_run_at = sqlc.arg(scheduled_for)
+    RETURNING id
+),
+created AS (
+    INSERT INTO executions (job_id, status, scheduled_for)
+    SELECT id, 'pending', sqlc.arg(scheduled_for) FROM advanced
+    ON CONFLICT DO NOTHING
+    RETURNING id
+)
+INSERT INTO execution_events (execution_id, to_status, actor, reason)
+SELECT id, 'pending', 'scheduler', 'recurring schedule fired' FROM created;
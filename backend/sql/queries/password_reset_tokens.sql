@@ -0,0 +1,24 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+    user_id,
+    token_hash,
+    expires_at,
+    ip_address
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- Marks a usable token as used and returns it, so each token resets a password
+-- at most once even under concurrent requests.
+-- name: ConsumePasswordResetToken :one
+UPDATE password_reset_tokens 
+SET used_at = now()
+WHERE token_hash = $1 
+  AND used_at IS NULL 
+  AND expires_at > now()
+RETURNING *;
+
+-- name: InvalidateUserPasswordResetTokens :exec
+UPDATE password_reset_tokens 
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
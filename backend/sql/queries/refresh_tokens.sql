@@ -4,9 +4,10 @@ INSERT INTO refresh_tokens (
     token_hash,
     expires_at,
     user_agent,
-    ip_address
+    ip_address,
+    family_id
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetRefreshToken :one
@@ -15,6 +16,41 @@ WHERE token_hash = $1
   AND is_revoked = FALSE 
   AND expires_at > now();
 
+-- Loads a token whatever its state and locks it, so concurrent refreshes with
+-- the same token are serialised and the later one is detected as reuse.
+-- name: GetRefreshTokenForUpdate :one
+SELECT * FROM refresh_tokens 
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens 
+SET 
+    is_revoked = TRUE,
+    rotated_at = now(),
+    last_used = now()
+WHERE id = $1;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE family_id = $1;
+
+-- name: RevokeUserRefreshTokenFamily :execrows
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE user_id = $1 
+  AND family_id = $2 
+  AND is_revoked = FALSE;
+
+-- Signs a user out of every session except one.
+-- name: RevokeOtherUserRefreshTokens :execrows
+UPDATE refresh_tokens 
+SET is_revoked = TRUE
+WHERE user_id = $1 
+  AND family_id <> $2 
+  AND is_revoked = FALSE;
+
 -- name: UpdateRefreshTokenLastUsed :exec
 UPDATE refresh_tokens 
 SET last_used = now()
@@ -30,9 +66,16 @@ UPDATE refresh_tokens
 SET is_revoked = TRUE
 WHERE user_id = $1;
 
+-- Deletes expired tokens once no token in their family is still valid, so rotated
+-- tokens stay available for reuse detection for as long as the session lasts.
 -- name: DeleteExpiredRefreshTokens :exec
-DELETE FROM refresh_tokens 
-WHERE expires_at < now() OR is_revoked = TRUE;
+DELETE FROM refresh_tokens t
+WHERE t.expires_at < now()
+  AND NOT EXISTS (
+    SELECT 1 FROM refresh_tokens f
+    WHERE f.family_id = t.family_id
+      AND f.expires_at >= now()
+  );
 
 -- name: GetUserRefreshTokens :many
 SELECT * FROM refresh_tokens 
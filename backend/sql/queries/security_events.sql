@@ -0,0 +1,10 @@
+-- name: CreateSecurityEvent :exec
+INSERT INTO security_events (
+    user_id,
+    event_type,
+    details,
+    user_agent,
+    ip_address
+) VALUES (
+    $1, $2, $3, $4, $5
+);
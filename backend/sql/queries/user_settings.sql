@@ -0,0 +1,31 @@
+-- name: EnsureUserSettings :exec
+INSERT INTO user_settings (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- name: GetUserSettings :one
+SELECT
+    user_id,
+    cost_weight::float8 AS cost_weight,
+    carbon_weight::float8 AS carbon_weight,
+    updated_at
+FROM user_settings
+WHERE user_id = $1;
+
+-- name: UpsertUserSettings :one
+INSERT INTO user_settings (
+    user_id,
+    cost_weight,
+    carbon_weight
+) VALUES (
+    sqlc.arg(user_id), sqlc.arg(cost_weight)::float8, sqlc.arg(carbon_weight)::float8
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    cost_weight = EXCLUDED.cost_weight,
+    carbon_weight = EXCLUDED.carbon_weight,
+    updated_at = now()
+RETURNING
+    user_id,
+    cost_weight::float8 AS cost_weight,
+    carbon_weight::float8 AS carbon_weight,
+    updated_at;
@@ -0,0 +1,76 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    owner_id,
+    url,
+    secret,
+    events
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions 
+WHERE id = $1 AND owner_id = $2;
+
+-- name: GetWebhookSubscriptionByID :one
+SELECT * FROM webhook_subscriptions 
+WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions 
+WHERE owner_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions 
+WHERE id = $1 AND owner_id = $2;
+
+-- Leases a batch of due deliveries by pushing their next attempt past the lease,
+-- so a dispatcher that dies mid-delivery only delays the retry. Rows locked by
+-- other dispatchers are skipped.
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries 
+SET next_attempt_at = now() + (sqlc.arg(lease_seconds)::int * interval '1 second')
+WHERE id IN (
+    SELECT id FROM webhook_deliveries 
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at ASC
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries 
+WHERE id = $1 AND subscription_id = $2;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries 
+WHERE subscription_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries 
+SET 
+    status = $2,
+    attempts = attempts + 1,
+    next_attempt_at = $3,
+    last_error = $4,
+    delivered_at = $5
+WHERE id = $1;
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    status_code,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts 
+WHERE delivery_id = $1
+ORDER BY attempted_at ASC;
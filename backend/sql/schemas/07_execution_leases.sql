@@ -0,0 +1,18 @@
+-- +goose Up
+-- Execution leases: lets multiple scheduler replicas claim pending executions safely
+-- A worker owns an execution until its lease expires; expired leases return to pending
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS lease_owner TEXT;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+-- Partial index for finding expired leases (only leased rows are indexed)
+CREATE INDEX IF NOT EXISTS idx_executions_lease_expires_at ON executions (lease_expires_at) WHERE lease_owner IS NOT NULL;
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.lease_owner IS 'Identifier of the scheduler worker currently holding this execution';
+COMMENT ON COLUMN executions.lease_expires_at IS 'When the current lease expires and the execution returns to pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_executions_lease_expires_at;
+ALTER TABLE executions DROP COLUMN IF EXISTS lease_owner;
+ALTER TABLE executions DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Planned start time: when the scheduler intends an execution to begin
+-- Executions may be deferred within the job's delay tolerance to reach cleaner or cheaper slots
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS planned_start_at TIMESTAMPTZ;
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.planned_start_at IS 'Start time chosen by the scheduler within the delay tolerance window';
+
+-- +goose Down
+ALTER TABLE executions DROP COLUMN IF EXISTS planned_start_at;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Per-run overrides: a single job definition can be run many times with different settings
+-- NULL means the execution inherits the value from its job
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS env_vars JSONB;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS delay_tolerance_hours INTEGER
+    CHECK (delay_tolerance_hours >= 0 AND delay_tolerance_hours <= 168);
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.env_vars IS 'Environment variables merged over the job env_vars for this run';
+COMMENT ON COLUMN executions.delay_tolerance_hours IS 'Delay tolerance for this run, overriding the job value (0-168 hours)';
+
+-- +goose Down
+ALTER TABLE executions DROP COLUMN IF EXISTS env_vars;
+ALTER TABLE executions DROP COLUMN IF EXISTS delay_tolerance_hours;
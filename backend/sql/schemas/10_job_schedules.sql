@@ -0,0 +1,29 @@
+-- +goose Up
+-- Recurring jobs: a cron expression evaluated in the job's timezone
+-- The scheduler creates one execution per occurrence, anchored at its nominal fire time
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS cron_schedule TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS next_run_at TIMESTAMPTZ;
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ;
+
+-- Partial index for finding due schedules (only recurring jobs are indexed)
+CREATE INDEX IF NOT EXISTS idx_jobs_next_run_at ON jobs (next_run_at) WHERE cron_schedule IS NOT NULL;
+
+-- Each occurrence of a schedule produces at most one execution
+CREATE UNIQUE INDEX IF NOT EXISTS idx_executions_job_scheduled_for ON executions (job_id, scheduled_for) WHERE scheduled_for IS NOT NULL;
+
+-- Comments for new columns
+COMMENT ON COLUMN jobs.cron_schedule IS 'Cron expression for recurring runs (NULL for on-demand jobs)';
+COMMENT ON COLUMN jobs.timezone IS 'IANA timezone the cron expression is evaluated in';
+COMMENT ON COLUMN jobs.next_run_at IS 'Next nominal fire time of the cron schedule';
+COMMENT ON COLUMN executions.scheduled_for IS 'Nominal fire time of the schedule occurrence that created this execution';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_executions_job_scheduled_for;
+DROP INDEX IF EXISTS idx_jobs_next_run_at;
+ALTER TABLE executions DROP COLUMN IF EXISTS scheduled_for;
+ALTER TABLE jobs DROP COLUMN IF EXISTS next_run_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS timezone;
+ALTER TABLE jobs DROP COLUMN IF EXISTS cron_schedule;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Teardown tracking: executions remember the compute instance they ran on so that
+-- leaked resources can be found and torn down again with backoff
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS instance_id TEXT;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS teardown_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS next_teardown_at TIMESTAMPTZ;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS last_teardown_error TEXT;
+
+-- Partial index for the reconciler's retry queue (only orphaned rows are indexed)
+CREATE INDEX IF NOT EXISTS idx_executions_next_teardown_at ON executions (next_teardown_at) WHERE status = 'orphaned';
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.instance_id IS 'Provider-side identifier of the compute instance';
+COMMENT ON COLUMN executions.teardown_attempts IS 'Failed teardown attempts for an orphaned instance';
+COMMENT ON COLUMN executions.next_teardown_at IS 'When the reconciler next retries teardown';
+COMMENT ON COLUMN executions.last_teardown_error IS 'Error from the most recent failed teardown';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_executions_next_teardown_at;
+ALTER TABLE executions DROP COLUMN IF EXISTS last_teardown_error;
+ALTER TABLE executions DROP COLUMN IF EXISTS next_teardown_at;
+ALTER TABLE executions DROP COLUMN IF EXISTS teardown_attempts;
+ALTER TABLE executions DROP COLUMN IF EXISTS instance_id;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Administrators can access operational endpoints such as the orphaned resource list
+-- Grant with: UPDATE users SET is_admin = TRUE WHERE email = '...';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Comments for new columns
+COMMENT ON COLUMN users.is_admin IS 'Whether the user can access admin endpoints';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- Execution actor enum: who caused an execution to change status
+CREATE TYPE execution_actor AS ENUM (
+    'scheduler', -- Scheduler claimed, placed or released the execution
+    'executor',  -- Executor or reconciler ran, finished or tore down the execution
+    'user'       -- A user action, such as requesting a run
+);
+
+-- Execution events table: append-only history of status transitions
+-- Lets support explain an execution's timeline (e.g. why it was deferred)
+
+CREATE TABLE execution_events (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    execution_id UUID NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    from_status  execution_status, -- NULL when the execution was created
+    to_status    execution_status NOT NULL,
+    actor        execution_actor NOT NULL,
+    reason       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Index for reading an execution's timeline in order
+CREATE INDEX idx_execution_events_execution_id ON execution_events (execution_id, created_at);
+
+-- Comments for documentation
+COMMENT ON TYPE execution_actor IS 'Who caused an execution status transition';
+COMMENT ON TABLE execution_events IS 'History of execution status transitions';
+COMMENT ON COLUMN execution_events.id IS 'Unique event identifier';
+COMMENT ON COLUMN execution_events.execution_id IS 'Reference to the execution that changed';
+COMMENT ON COLUMN execution_events.from_status IS 'Status before the transition (NULL on creation)';
+COMMENT ON COLUMN execution_events.to_status IS 'Status after the transition';
+COMMENT ON COLUMN execution_events.actor IS 'Component or user that caused the transition';
+COMMENT ON COLUMN execution_events.reason IS 'Human-readable explanation of the transition';
+COMMENT ON COLUMN execution_events.created_at IS 'When the transition happened';
+
+-- +goose Down
+DROP TABLE IF EXISTS execution_events;
+DROP TYPE IF EXISTS execution_actor;
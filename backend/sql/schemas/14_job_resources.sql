@@ -0,0 +1,29 @@
+-- +goose Up
+-- Resource requirements of a job, used to pick a VM type that fits it
+-- Runtimes are optional: the scheduler falls back to a default expected runtime,
+-- and jobs without a max runtime are never stopped early
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS cpu_cores INTEGER NOT NULL DEFAULT 1
+    CHECK (cpu_cores BETWEEN 1 AND 416);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS memory_mb INTEGER NOT NULL DEFAULT 2048
+    CHECK (memory_mb BETWEEN 128 AND 12582912);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS disk_gb INTEGER NOT NULL DEFAULT 10
+    CHECK (disk_gb BETWEEN 1 AND 65536);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS expected_runtime_minutes INTEGER
+    CHECK (expected_runtime_minutes > 0);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS max_runtime_minutes INTEGER
+    CHECK (max_runtime_minutes > 0);
+
+-- Comments for new columns
+COMMENT ON COLUMN jobs.cpu_cores IS 'Minimum number of vCPUs the job needs';
+COMMENT ON COLUMN jobs.memory_mb IS 'Minimum memory the job needs, in MiB';
+COMMENT ON COLUMN jobs.disk_gb IS 'Minimum ephemeral disk the job needs, in GiB';
+COMMENT ON COLUMN jobs.expected_runtime_minutes IS 'Expected runtime used for cost and carbon estimates (NULL uses the scheduler default)';
+COMMENT ON COLUMN jobs.max_runtime_minutes IS 'Runtime after which an execution is stopped and marked failed (NULL for no limit)';
+
+-- +goose Down
+ALTER TABLE jobs DROP COLUMN IF EXISTS max_runtime_minutes;
+ALTER TABLE jobs DROP COLUMN IF EXISTS expected_runtime_minutes;
+ALTER TABLE jobs DROP COLUMN IF EXISTS disk_gb;
+ALTER TABLE jobs DROP COLUMN IF EXISTS memory_mb;
+ALTER TABLE jobs DROP COLUMN IF EXISTS cpu_cores;
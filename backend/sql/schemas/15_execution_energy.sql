@@ -0,0 +1,11 @@
+-- +goose Up
+-- Energy accounting: the energy an execution drew, from which carbon_emitted_kg
+-- is computed against the grid intensity over the run interval
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS energy_kwh NUMERIC(12, 6);
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.energy_kwh IS 'Estimated energy drawn by the execution, including data centre overhead (kWh)';
+
+-- +goose Down
+ALTER TABLE executions DROP COLUMN IF EXISTS energy_kwh;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Counterfactual baseline: what an execution would have cost and emitted had it
+-- started at submission time in the default region. The scheduler records where
+-- the baseline runs; the executor prices it over the actual runtime on completion.
+
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS baseline_region TEXT;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS baseline_vm_type TEXT;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS baseline_start_at TIMESTAMPTZ;
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS baseline_cost_usd NUMERIC(10, 6);
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS baseline_carbon_kg NUMERIC(12, 6);
+
+-- Comments for new columns
+COMMENT ON COLUMN executions.baseline_region IS 'Default region the execution would have run in without carbon-aware scheduling';
+COMMENT ON COLUMN executions.baseline_vm_type IS 'VM type the execution would have used in the baseline region';
+COMMENT ON COLUMN executions.baseline_start_at IS 'When the execution would have started without deferral (submission time)';
+COMMENT ON COLUMN executions.baseline_cost_usd IS 'Cost of the actual runtime in the baseline region and start time';
+COMMENT ON COLUMN executions.baseline_carbon_kg IS 'Carbon emissions of the actual runtime in the baseline region and start time (kg CO2)';
+
+-- +goose Down
+ALTER TABLE executions DROP COLUMN IF EXISTS baseline_carbon_kg;
+ALTER TABLE executions DROP COLUMN IF EXISTS baseline_cost_usd;
+ALTER TABLE executions DROP COLUMN IF EXISTS baseline_start_at;
+ALTER TABLE executions DROP COLUMN IF EXISTS baseline_vm_type;
+ALTER TABLE executions DROP COLUMN IF EXISTS baseline_region;
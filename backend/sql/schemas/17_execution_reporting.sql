@@ -0,0 +1,7 @@
+-- +goose Up
+-- Sustainability reports aggregate finished executions by completion time
+
+CREATE INDEX IF NOT EXISTS idx_executions_completed_at ON executions (completed_at) WHERE completed_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_executions_completed_at;
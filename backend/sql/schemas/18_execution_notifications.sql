@@ -0,0 +1,36 @@
+-- +goose Up
+-- Execution status changes are broadcast with NOTIFY so that every API replica can
+-- push them to connected clients. Executors publish log lines on the same channel.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_execution_status() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('execution_events', json_build_object(
+        'type', 'status',
+        'execution_id', NEW.id,
+        'job_id', NEW.job_id,
+        'owner_id', (SELECT owner_id FROM jobs WHERE id = NEW.job_id),
+        'status', NEW.status,
+        'at', now()
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER executions_notify_insert
+    AFTER INSERT ON executions
+    FOR EACH ROW EXECUTE FUNCTION notify_execution_status();
+
+CREATE TRIGGER executions_notify_status
+    AFTER UPDATE OF status ON executions
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_execution_status();
+
+COMMENT ON FUNCTION notify_execution_status() IS 'Publishes execution status changes on the execution_events channel';
+
+-- +goose Down
+DROP TRIGGER IF EXISTS executions_notify_status ON executions;
+DROP TRIGGER IF EXISTS executions_notify_insert ON executions;
+DROP FUNCTION IF EXISTS notify_execution_status();
@@ -0,0 +1,152 @@
+-- +goose Up
+-- Webhook delivery status enum
+CREATE TYPE webhook_delivery_status AS ENUM (
+    'pending',   -- Waiting for its first or next attempt
+    'delivered', -- Acknowledged with a 2xx response
+    'failed'     -- Gave up after the maximum number of attempts
+);
+
+-- Webhook subscriptions table: per-user endpoints notified of execution lifecycle events
+
+CREATE TABLE webhook_subscriptions (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id   UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    events     TEXT[] NOT NULL DEFAULT '{}'
+               CHECK (events <@ ARRAY['scheduled', 'started', 'completed_success', 'completed_error', 'orphaned']),
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_subscriptions_owner_id ON webhook_subscriptions (owner_id);
+
+-- Webhook deliveries table: outbox of events to send, written in the same
+-- transaction as the execution change that caused them
+
+CREATE TABLE webhook_deliveries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event           TEXT NOT NULL,
+    execution_id    UUID NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    payload         JSONB NOT NULL,
+    status          webhook_delivery_status NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Index for the dispatcher's due-delivery poll
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+-- Index for listing a subscription's deliveries, newest first
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id, created_at DESC);
+
+-- Webhook delivery attempts table: one row per HTTP request made for a delivery
+
+CREATE TABLE webhook_delivery_attempts (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id  UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    status_code  INTEGER,
+    error        TEXT,
+    duration_ms  INTEGER NOT NULL,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id, attempted_at);
+
+-- Enqueue deliveries for every matching subscription when an execution is
+-- scheduled, starts, finishes or is orphaned
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+DECLARE
+    event_name TEXT;
+BEGIN
+    IF NEW.chosen_at IS NOT NULL AND NEW.chosen_at IS DISTINCT FROM OLD.chosen_at THEN
+        event_name := 'scheduled';
+    ELSIF NEW.status IS NOT DISTINCT FROM OLD.status THEN
+        RETURN NULL;
+    ELSIF NEW.status = 'running' THEN
+        event_name := 'started';
+    ELSIF NEW.status = 'orphaned' THEN
+        event_name := 'orphaned';
+    ELSIF NEW.status IN ('completed_success', 'completed_error') AND OLD.status <> 'orphaned' THEN
+        -- Tearing down an orphaned instance does not complete the execution again
+        event_name := NEW.status::text;
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO webhook_deliveries (subscription_id, event, execution_id, payload)
+    SELECT s.id, event_name, NEW.id, json_build_object(
+        'event', event_name,
+        'occurred_at', now(),
+        'execution', json_build_object(
+            'id', NEW.id,
+            'job_id', NEW.job_id,
+            'status', NEW.status,
+            'cloud_region', NEW.cloud_region,
+            'vm_type', NEW.vm_type,
+            'planned_start_at', NEW.planned_start_at,
+            'started_at', NEW.started_at,
+            'completed_at', NEW.completed_at,
+            'exit_code', NEW.exit_code,
+            'cost_actual_usd', NEW.cost_actual_usd,
+            'carbon_emitted_kg', NEW.carbon_emitted_kg
+        )
+    )
+    FROM webhook_subscriptions s
+    JOIN jobs j ON j.owner_id = s.owner_id
+    WHERE j.id = NEW.job_id
+      AND s.is_active
+      AND (cardinality(s.events) = 0 OR event_name = ANY (s.events));
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER executions_enqueue_webhooks
+    AFTER UPDATE ON executions
+    FOR EACH ROW EXECUTE FUNCTION enqueue_webhook_deliveries();
+
+-- Comments for documentation
+COMMENT ON TYPE webhook_delivery_status IS 'Progress of a webhook delivery';
+COMMENT ON TABLE webhook_subscriptions IS 'Endpoints notified of execution lifecycle events';
+COMMENT ON COLUMN webhook_subscriptions.id IS 'Unique subscription identifier';
+COMMENT ON COLUMN webhook_subscriptions.owner_id IS 'Reference to the user whose executions are reported';
+COMMENT ON COLUMN webhook_subscriptions.url IS 'Endpoint that receives POSTed events';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'Key for the HMAC-SHA256 payload signature (kept in plain text to sign with)';
+COMMENT ON COLUMN webhook_subscriptions.events IS 'Events to deliver (empty for all)';
+COMMENT ON COLUMN webhook_subscriptions.is_active IS 'Whether new events are enqueued for this subscription';
+COMMENT ON COLUMN webhook_subscriptions.created_at IS 'Subscription creation timestamp';
+COMMENT ON COLUMN webhook_subscriptions.updated_at IS 'Last subscription update timestamp';
+COMMENT ON TABLE webhook_deliveries IS 'Outbox of webhook events to deliver';
+COMMENT ON COLUMN webhook_deliveries.id IS 'Unique delivery identifier, sent with every attempt';
+COMMENT ON COLUMN webhook_deliveries.subscription_id IS 'Reference to the receiving subscription';
+COMMENT ON COLUMN webhook_deliveries.event IS 'Lifecycle event being reported';
+COMMENT ON COLUMN webhook_deliveries.execution_id IS 'Reference to the execution the event is about';
+COMMENT ON COLUMN webhook_deliveries.payload IS 'JSON body sent to the endpoint';
+COMMENT ON COLUMN webhook_deliveries.status IS 'Whether the delivery is pending, delivered or failed';
+COMMENT ON COLUMN webhook_deliveries.attempts IS 'Number of attempts made so far';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the next attempt is due, or until when a dispatcher holds it';
+COMMENT ON COLUMN webhook_deliveries.last_error IS 'Error of the latest failed attempt';
+COMMENT ON COLUMN webhook_deliveries.delivered_at IS 'When the endpoint acknowledged the event';
+COMMENT ON COLUMN webhook_deliveries.created_at IS 'When the event was enqueued';
+COMMENT ON TABLE webhook_delivery_attempts IS 'History of HTTP requests made for webhook deliveries';
+COMMENT ON COLUMN webhook_delivery_attempts.id IS 'Unique attempt identifier';
+COMMENT ON COLUMN webhook_delivery_attempts.delivery_id IS 'Reference to the delivery attempted';
+COMMENT ON COLUMN webhook_delivery_attempts.status_code IS 'HTTP status of the response (NULL if none was received)';
+COMMENT ON COLUMN webhook_delivery_attempts.error IS 'Why the attempt failed (NULL on success)';
+COMMENT ON COLUMN webhook_delivery_attempts.duration_ms IS 'How long the request took, in milliseconds';
+COMMENT ON COLUMN webhook_delivery_attempts.attempted_at IS 'When the request was made';
+
+-- +goose Down
+DROP TRIGGER IF EXISTS executions_enqueue_webhooks ON executions;
+DROP FUNCTION IF EXISTS enqueue_webhook_deliveries();
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+DROP TYPE IF EXISTS webhook_delivery_status;
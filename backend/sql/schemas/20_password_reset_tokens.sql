@@ -0,0 +1,29 @@
+-- +goose Up
+-- Password reset tokens table: one-time tokens emailed to users who forgot their password
+-- Only the hash of each token is stored, so a database leak cannot be used to reset passwords
+
+CREATE TABLE password_reset_tokens (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ip_address INET
+);
+
+-- Index for invalidating a user's outstanding tokens
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens (user_id) WHERE used_at IS NULL;
+
+-- Comments for documentation
+COMMENT ON TABLE password_reset_tokens IS 'Stores single-use password reset tokens';
+COMMENT ON COLUMN password_reset_tokens.id IS 'Unique reset token identifier';
+COMMENT ON COLUMN password_reset_tokens.user_id IS 'Reference to users table';
+COMMENT ON COLUMN password_reset_tokens.token_hash IS 'SHA-256 hash of the reset token';
+COMMENT ON COLUMN password_reset_tokens.expires_at IS 'When this reset token expires';
+COMMENT ON COLUMN password_reset_tokens.used_at IS 'When this reset token was used or invalidated (NULL while usable)';
+COMMENT ON COLUMN password_reset_tokens.created_at IS 'When this reset token was requested';
+COMMENT ON COLUMN password_reset_tokens.ip_address IS 'IP address of the client that requested this token';
+
+-- +goose Down
+DROP TABLE IF EXISTS password_reset_tokens;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Refresh token families: every token issued by rotating another joins its family,
+-- which starts at login. A rotated token that is presented again has been copied,
+-- so the whole family is revoked and a security event is recorded.
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id  UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN rotated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Login session the token descends from; shared by all tokens rotated from it';
+COMMENT ON COLUMN refresh_tokens.rotated_at IS 'When this token was exchanged for its successor (NULL if never used to refresh)';
+
+CREATE TYPE security_event_type AS ENUM (
+    'refresh_token_reuse'
+);
+
+CREATE TABLE security_events (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type security_event_type NOT NULL,
+    details    JSONB NOT NULL DEFAULT '{}',
+    user_agent TEXT,
+    ip_address INET,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_security_events_user_id ON security_events (user_id, created_at DESC);
+
+COMMENT ON TYPE security_event_type IS 'Kind of security-relevant account event';
+COMMENT ON TABLE security_events IS 'Audit log of security-relevant account events';
+COMMENT ON COLUMN security_events.id IS 'Unique security event identifier';
+COMMENT ON COLUMN security_events.user_id IS 'Reference to the affected user';
+COMMENT ON COLUMN security_events.event_type IS 'What happened';
+COMMENT ON COLUMN security_events.details IS 'Event-specific context, such as the revoked token family';
+COMMENT ON COLUMN security_events.user_agent IS 'User agent of the client that triggered the event';
+COMMENT ON COLUMN security_events.ip_address IS 'IP address of the client that triggered the event';
+COMMENT ON COLUMN security_events.created_at IS 'When the event was recorded';
+
+-- +goose Down
+DROP TABLE IF EXISTS security_events;
+DROP TYPE IF EXISTS security_event_type;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS family_id;
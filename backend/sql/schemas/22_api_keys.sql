@@ -0,0 +1,37 @@
+-- +goose Up
+-- API keys table: long-lived personal credentials for CI pipelines and other headless clients
+-- Only the hash of each key is stored; the key itself is shown once, when it is created
+
+CREATE TABLE api_keys (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name          TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 100),
+    prefix        TEXT NOT NULL,
+    key_hash      TEXT NOT NULL UNIQUE,
+    scopes        TEXT[] NOT NULL CHECK (cardinality(scopes) > 0),
+    expires_at    TIMESTAMPTZ,
+    last_used_at  TIMESTAMPTZ,
+    last_used_ip  INET,
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Index for listing a user's keys
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id, created_at DESC) WHERE revoked_at IS NULL;
+
+-- Comments for documentation
+COMMENT ON TABLE api_keys IS 'Stores hashed personal API keys';
+COMMENT ON COLUMN api_keys.id IS 'Unique API key identifier';
+COMMENT ON COLUMN api_keys.user_id IS 'Reference to the user the key acts as';
+COMMENT ON COLUMN api_keys.name IS 'User-chosen label, such as the pipeline using the key';
+COMMENT ON COLUMN api_keys.prefix IS 'Leading characters of the key, shown to identify it';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 hash of the key';
+COMMENT ON COLUMN api_keys.scopes IS 'Permissions granted to the key, such as jobs:write';
+COMMENT ON COLUMN api_keys.expires_at IS 'When the key stops working (NULL if it never expires)';
+COMMENT ON COLUMN api_keys.last_used_at IS 'When the key last authenticated a request, to the minute';
+COMMENT ON COLUMN api_keys.last_used_ip IS 'IP address of the client that last used the key';
+COMMENT ON COLUMN api_keys.revoked_at IS 'When the key was revoked (NULL while active)';
+COMMENT ON COLUMN api_keys.created_at IS 'When the key was created';
+
+-- +goose Down
+DROP TABLE IF EXISTS api_keys;
@@ -391,22 +391,26 @@ class UnifiedApiClient {
    */
   async getJobExecutions(jobId: string): Promise<any[]> {
     const response = await this.authenticatedRequest<{
-      id: string;
-      job_id: string;
-      status: string;
-      cloud_region?: string;
-      vm_type?: string;
-      cost_actual_usd?: number;
-      carbon_emitted_kg?: number;
-      exit_code?: number;
-      started_at?: string;
-      completed_at?: string;
-      created_at: string;
-      updated_at: string;
-    }[]>(`/api/v1/jobs/${jobId}/executions`);
+      executions: {
+        id: string;
+        job_id: string;
+        status: string;
+        cloud_region?: string;
+        vm_type?: string;
+        cost_actual_usd?: number;
+        carbon_emitted_kg?: number;
+        exit_code?: number;
+        planned_start_at?: string;
+        started_at?: string;
+        completed_at?: string;
+        created_at: string;
+      }[];
+      limit: number;
+      offset: number;
+    }>(`/api/v1/jobs/${jobId}/executions`);
 
     // Transform backend response to frontend format
-    return response.map(execution => ({
+    return response.executions.map(execution => ({
       id: execution.id,
       jobId: execution.job_id,
       status: execution.status,
@@ -415,10 +419,10 @@ class UnifiedApiClient {
       costActualUsd: execution.cost_actual_usd,
       carbonEmittedKg: execution.carbon_emitted_kg,
       exitCode: execution.exit_code,
+      plannedStartAt: execution.planned_start_at,
       startedAt: execution.started_at,
       completedAt: execution.completed_at,
       createdAt: execution.created_at,
-      updatedAt: execution.updated_at,
     }));
   }
 